@@ -133,3 +133,8 @@ func (a *AccessLogConfig) GetAccessGroup() string {
 func (a *AccessLogConfig) GetDescription() string {
 	return a.Annotations[annotationDescription]
 }
+
+// GetResourceStatus returns the status of the AccessLogConfig
+func (a *AccessLogConfig) GetResourceStatus() *ResourceStatus {
+	return &a.Status.ResourceStatus
+}
@@ -64,3 +64,8 @@ func (c *Cluster) IsEqual(other *Cluster) bool {
 func (c *Cluster) GetDescription() string {
 	return c.Annotations[annotationDescription]
 }
+
+// GetResourceStatus returns the status of the Cluster
+func (c *Cluster) GetResourceStatus() *ResourceStatus {
+	return &c.Status.ResourceStatus
+}
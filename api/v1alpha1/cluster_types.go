@@ -23,8 +23,7 @@ import (
 
 // ClusterStatus defines the observed state of Cluster.
 type ClusterStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	ResourceStatus `json:",inline"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,59 @@
+package v1alpha1
+
+import (
+	"bytes"
+
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	"github.com/kaasops/envoy-xds-controller/internal/protoutil"
+)
+
+func (e *Endpoint) UnmarshalV3() (*endpointv3.ClusterLoadAssignment, error) {
+	return e.unmarshalV3()
+}
+
+func (e *Endpoint) UnmarshalV3AndValidate() (*endpointv3.ClusterLoadAssignment, error) {
+	claV3, err := e.unmarshalV3()
+	if err != nil {
+		return nil, err
+	}
+	if err := claV3.ValidateAll(); err != nil {
+		return nil, err
+	}
+	return claV3, nil
+}
+
+func (e *Endpoint) unmarshalV3() (*endpointv3.ClusterLoadAssignment, error) {
+	if e.Spec == nil {
+		return nil, ErrSpecNil
+	}
+	var claV3 endpointv3.ClusterLoadAssignment
+	if err := protoutil.Unmarshaler.Unmarshal(e.Spec.Raw, &claV3); err != nil {
+		return nil, err
+	}
+	return &claV3, nil
+}
+
+func (e *Endpoint) IsEqual(other *Endpoint) bool {
+	if e == nil && other == nil {
+		return true
+	}
+	if e == nil || other == nil {
+		return false
+	}
+	if e.Spec == nil && other.Spec == nil {
+		return true
+	}
+	if e.Spec == nil || other.Spec == nil {
+		return false
+	}
+	return bytes.Equal(e.Spec.Raw, other.Spec.Raw)
+}
+
+func (e *Endpoint) GetDescription() string {
+	return e.Annotations[annotationDescription]
+}
+
+// GetResourceStatus returns the status of the Endpoint
+func (e *Endpoint) GetResourceStatus() *ResourceStatus {
+	return &e.Status.ResourceStatus
+}
@@ -2,13 +2,24 @@ package v1alpha1
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
 
+	oauth2v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/oauth2/v3"
+	routerv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
 	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	"github.com/kaasops/envoy-xds-controller/internal/protoutil"
 	"sigs.k8s.io/yaml"
 )
 
+// AnnotationConfigDiscovery serves the filters of an HttpFilter over ECDS: HCMs reference them
+// by config_discovery, so a change of their typed configs does not re-push listeners
+const AnnotationConfigDiscovery = "envoy.kaasops.io/config-discovery"
+
+var ErrInvalidAnnotationConfigDiscoveryValue = errors.New("envoy.kaasops.io/config-discovery annotation value must be true or false")
+
 func (h *HttpFilter) UnmarshalV3() ([]*hcmv3.HttpFilter, error) {
 	return h.unmarshalV3()
 }
@@ -23,9 +34,59 @@ func (h *HttpFilter) UnmarshalV3AndValidate() ([]*hcmv3.HttpFilter, error) {
 			return nil, err
 		}
 	}
+	if _, err := h.configDiscovery(); err != nil {
+		return nil, err
+	}
+	if h.IsConfigDiscovery() {
+		if err := validateConfigDiscovery(httpFilters); err != nil {
+			return nil, err
+		}
+	}
 	return httpFilters, nil
 }
 
+// IsConfigDiscovery reports whether the filters of the HttpFilter are served over ECDS.
+func (h *HttpFilter) IsConfigDiscovery() bool {
+	enabled, _ := h.configDiscovery()
+	return enabled
+}
+
+func (h *HttpFilter) configDiscovery() (bool, error) {
+	val, ok := h.GetAnnotations()[AnnotationConfigDiscovery]
+	if !ok {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, ErrInvalidAnnotationConfigDiscoveryValue
+	}
+	return enabled, nil
+}
+
+// validateConfigDiscovery checks that the filters can be served over ECDS. Extension configs are
+// named after their filters, so the names must be unique. The router filter stays inline, while
+// OAuth2 filters reference clusters, which are only served with inline filters.
+func validateConfigDiscovery(httpFilters []*hcmv3.HttpFilter) error {
+	names := make(map[string]struct{}, len(httpFilters))
+	for _, httpFilter := range httpFilters {
+		tc := httpFilter.GetTypedConfig()
+		if tc == nil {
+			return fmt.Errorf("http filter %s: typed_config is required with config discovery", httpFilter.GetName())
+		}
+		if tc.MessageIs(&routerv3.Router{}) {
+			continue
+		}
+		if tc.MessageIs(&oauth2v3.OAuth2{}) {
+			return fmt.Errorf("http filter %s: oauth2 filters can not be served with config discovery", httpFilter.GetName())
+		}
+		if _, ok := names[httpFilter.GetName()]; ok {
+			return fmt.Errorf("http filter %s: duplicate filter name", httpFilter.GetName())
+		}
+		names[httpFilter.GetName()] = struct{}{}
+	}
+	return nil
+}
+
 func (h *HttpFilter) unmarshalV3() ([]*hcmv3.HttpFilter, error) {
 	if len(h.Spec) == 0 {
 		return nil, ErrSpecNil
@@ -52,6 +113,9 @@ func (h *HttpFilter) IsEqual(other *HttpFilter) bool {
 	if len(h.Spec) != len(other.Spec) {
 		return false
 	}
+	if h.GetAnnotations()[AnnotationConfigDiscovery] != other.GetAnnotations()[AnnotationConfigDiscovery] {
+		return false
+	}
 
 	for i, httpFilterSpec := range h.Spec {
 		if httpFilterSpec == nil || other.Spec[i] == nil {
@@ -106,3 +170,8 @@ func (h *HttpFilter) Raw() []byte {
 	}
 	return data
 }
+
+// GetResourceStatus returns the status of the HttpFilter
+func (h *HttpFilter) GetResourceStatus() *ResourceStatus {
+	return &h.Status.ResourceStatus
+}
@@ -23,8 +23,7 @@ import (
 
 // HttpFilterStatus defines the observed state of HttpFilter.
 type HttpFilterStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	ResourceStatus `json:",inline"`
 }
 
 // +kubebuilder:object:root=true
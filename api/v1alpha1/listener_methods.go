@@ -2,11 +2,26 @@ package v1alpha1
 
 import (
 	"bytes"
+	"errors"
 
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	"github.com/kaasops/envoy-xds-controller/internal/protoutil"
 )
 
+const (
+	// AnnotationRouteDiscovery selects how HCMs of VirtualServices on the Listener discover
+	// their routes: RouteDiscoveryRDS, the default, or RouteDiscoveryScopedRDS
+	AnnotationRouteDiscovery = "envoy.kaasops.io/route-discovery"
+
+	// RouteDiscoveryRDS references the route configuration of the VirtualService
+	RouteDiscoveryRDS = "rds"
+	// RouteDiscoveryScopedRDS selects route configurations by the host of the request
+	// over SRDS, and Envoy fetches them on demand
+	RouteDiscoveryScopedRDS = "scoped-rds"
+)
+
+var ErrInvalidAnnotationRouteDiscoveryValue = errors.New("envoy.kaasops.io/route-discovery annotation value must be rds or scoped-rds")
+
 func (l *Listener) UnmarshalV3() (*listenerv3.Listener, error) {
 	return l.unmarshalV3()
 }
@@ -27,6 +42,9 @@ func (l *Listener) UnmarshalV3AndValidate() (*listenerv3.Listener, error) {
 	if err != nil {
 		return nil, err
 	}
+	if _, err := l.GetRouteDiscovery(); err != nil {
+		return nil, err
+	}
 	// if len(listener.FilterChains) > 0 {
 	//	 return nil, fmt.Errorf("filter chains are not supported")
 	// }
@@ -40,9 +58,24 @@ func (l *Listener) IsEqual(other *Listener) bool {
 	if l == nil || other == nil || l.Spec == nil || other.Spec == nil || l.Spec.Raw == nil || other.Spec.Raw == nil {
 		return false
 	}
+	if l.GetAnnotations()[AnnotationRouteDiscovery] != other.GetAnnotations()[AnnotationRouteDiscovery] {
+		return false
+	}
 	return bytes.Equal(l.Spec.Raw, other.Spec.Raw)
 }
 
+// GetRouteDiscovery returns how HCMs of VirtualServices on the Listener discover their routes.
+func (l *Listener) GetRouteDiscovery() (string, error) {
+	switch val := l.GetAnnotations()[AnnotationRouteDiscovery]; val {
+	case "", RouteDiscoveryRDS:
+		return RouteDiscoveryRDS, nil
+	case RouteDiscoveryScopedRDS:
+		return val, nil
+	default:
+		return "", ErrInvalidAnnotationRouteDiscoveryValue
+	}
+}
+
 func (l *Listener) GetAccessGroup() string {
 	accessGroup := l.GetLabels()[LabelAccessGroup]
 	if accessGroup == "" {
@@ -54,3 +87,8 @@ func (l *Listener) GetAccessGroup() string {
 func (l *Listener) GetDescription() string {
 	return l.Annotations[annotationDescription]
 }
+
+// GetResourceStatus returns the status of the Listener
+func (l *Listener) GetResourceStatus() *ResourceStatus {
+	return &l.Status.ResourceStatus
+}
@@ -66,3 +66,8 @@ func (p *Policy) GetAccessGroup() string {
 func (p *Policy) GetDescription() string {
 	return p.Annotations[annotationDescription]
 }
+
+// GetResourceStatus returns the status of the Policy
+func (p *Policy) GetResourceStatus() *ResourceStatus {
+	return &p.Status.ResourceStatus
+}
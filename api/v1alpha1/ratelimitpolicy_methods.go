@@ -0,0 +1,128 @@
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+)
+
+const (
+	// RateLimitDefaultDomain is the domain of limits of policies not setting one
+	RateLimitDefaultDomain = "envoy-xds-controller"
+
+	// RateLimitPolicyDescriptorKey is the key of the first entry of descriptors, valued with
+	// the namespace and name of the policy and the name of the limit: <namespace>/<name>/<limit>
+	RateLimitPolicyDescriptorKey = "rate_limit_policy"
+	// RateLimitNodeDescriptorKey is the key of the second entry of descriptors, valued with the
+	// node ID of the proxy, so that requests are counted per node
+	RateLimitNodeDescriptorKey = "node"
+	// RateLimitHeaderDescriptorKey is the key of the entry of limits counted by header
+	RateLimitHeaderDescriptorKey = "header"
+)
+
+// rateLimitUnits are the durations of units of rate limits
+var rateLimitUnits = map[string]time.Duration{
+	RateLimitUnitSecond: time.Second,
+	RateLimitUnitMinute: time.Minute,
+	RateLimitUnitHour:   time.Hour,
+	RateLimitUnitDay:    24 * time.Hour,
+}
+
+func (p *RateLimitPolicy) Validate() error {
+	if p.Spec.VirtualService == "" {
+		return errors.New("virtualService is empty")
+	}
+	if p.Spec.Service.Cluster.Name == "" {
+		return errors.New("service cluster name is empty")
+	}
+	if p.Spec.Service.Timeout != nil && p.Spec.Service.Timeout.Duration < 0 {
+		return errors.New("service timeout is negative")
+	}
+	if len(p.Spec.Limits) == 0 {
+		return errors.New("limits are empty")
+	}
+	names := make(map[string]struct{}, len(p.Spec.Limits))
+	for i, limit := range p.Spec.Limits {
+		if limit.Name == "" {
+			return fmt.Errorf("limit %d: name is empty", i)
+		}
+		if _, ok := names[limit.Name]; ok {
+			return fmt.Errorf("limit %s: duplicate name", limit.Name)
+		}
+		names[limit.Name] = struct{}{}
+		if limit.Requests == 0 {
+			return fmt.Errorf("limit %s: requests must be greater than 0", limit.Name)
+		}
+		if _, ok := rateLimitUnits[limit.Unit]; !ok {
+			return fmt.Errorf("limit %s: unknown unit %q", limit.Name, limit.Unit)
+		}
+		if limit.By != nil && limit.By.RemoteAddress == (limit.By.Header != "") {
+			return fmt.Errorf("limit %s: exactly one of remoteAddress and header must be set", limit.Name)
+		}
+	}
+	return nil
+}
+
+// GetVirtualServiceNamespacedName returns the VirtualService whose requests are limited
+func (p *RateLimitPolicy) GetVirtualServiceNamespacedName() helpers.NamespacedName {
+	return helpers.NamespacedName{Namespace: p.Namespace, Name: p.Spec.VirtualService}
+}
+
+// GetServiceClusterNamespacedName returns the Cluster of the rate limit service
+func (p *RateLimitPolicy) GetServiceClusterNamespacedName() helpers.NamespacedName {
+	return helpers.NamespacedName{
+		Namespace: helpers.GetNamespace(p.Spec.Service.Cluster.Namespace, p.Namespace),
+		Name:      p.Spec.Service.Cluster.Name,
+	}
+}
+
+// GetDomain returns the domain of the limits
+func (p *RateLimitPolicy) GetDomain() string {
+	if p.Spec.Service.Domain == "" {
+		return RateLimitDefaultDomain
+	}
+	return p.Spec.Service.Domain
+}
+
+// GetLimit returns the limit with the name, nil if there is none
+func (p *RateLimitPolicy) GetLimit(name string) *RateLimit {
+	for i := range p.Spec.Limits {
+		if p.Spec.Limits[i].Name == name {
+			return &p.Spec.Limits[i]
+		}
+	}
+	return nil
+}
+
+// DescriptorValue returns the value of the RateLimitPolicyDescriptorKey entry of the limit
+func (p *RateLimitPolicy) DescriptorValue(limit string) string {
+	return p.Namespace + "/" + p.Name + "/" + limit
+}
+
+// UnitDuration returns the duration of the unit of the limit, zero if it is unknown
+func (l *RateLimit) UnitDuration() time.Duration {
+	return rateLimitUnits[l.Unit]
+}
+
+// IsEqual reports whether both policies render the same configuration
+func (p *RateLimitPolicy) IsEqual(other *RateLimitPolicy) bool {
+	if p == nil && other == nil {
+		return true
+	}
+	if p == nil || other == nil {
+		return false
+	}
+	return reflect.DeepEqual(p.Spec, other.Spec)
+}
+
+func (p *RateLimitPolicy) GetDescription() string {
+	return p.Annotations[annotationDescription]
+}
+
+// GetResourceStatus returns the status of the RateLimitPolicy
+func (p *RateLimitPolicy) GetResourceStatus() *ResourceStatus {
+	return &p.Status.ResourceStatus
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RateLimitPolicySpec defines the desired state of RateLimitPolicy.
+type RateLimitPolicySpec struct {
+	// VirtualService is the name of the VirtualService in the namespace of the policy
+	// whose requests are limited.
+	// +kubebuilder:validation:MinLength=1
+	VirtualService string `json:"virtualService"`
+
+	// Routes are the names of the routes to limit, including routes added by the template
+	// and additional routes. All routes to a cluster are limited if empty.
+	// +optional
+	Routes []string `json:"routes,omitempty"`
+
+	// Service is the rate limit service requests are checked against. All policies of a
+	// VirtualService must use the same service.
+	Service RateLimitService `json:"service"`
+
+	// Limits are checked independently, a request is rejected if any of them is exceeded.
+	// +kubebuilder:validation:MinItems=1
+	Limits []RateLimit `json:"limits"`
+}
+
+// RateLimitService is a gRPC service implementing the Envoy Rate Limit Service API, such as
+// the one served by the controller.
+type RateLimitService struct {
+	// Cluster of the service. It must use HTTP/2.
+	// If namespace is omitted, it defaults to the policy namespace.
+	Cluster ResourceRef `json:"cluster"`
+
+	// Domain of the limits sent to the service, "envoy-xds-controller" if omitted.
+	// +optional
+	Domain string `json:"domain,omitempty"`
+
+	// Timeout of calls to the service, 20ms if omitted.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// FailureModeDeny rejects requests when the service cannot be reached, instead of
+	// letting them through.
+	// +optional
+	FailureModeDeny bool `json:"failureModeDeny,omitempty"`
+}
+
+// Units of rate limits
+const (
+	RateLimitUnitSecond = "second"
+	RateLimitUnitMinute = "minute"
+	RateLimitUnitHour   = "hour"
+	RateLimitUnitDay    = "day"
+)
+
+// RateLimit is a number of requests allowed per unit of time.
+type RateLimit struct {
+	// Name of the limit, unique within the policy.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Requests allowed per unit.
+	// +kubebuilder:validation:Minimum=1
+	Requests uint32 `json:"requests"`
+
+	// Unit is one of second, minute, hour or day.
+	// +kubebuilder:validation:Enum=second;minute;hour;day
+	Unit string `json:"unit"`
+
+	// By counts requests separately per key. Requests are counted together if omitted.
+	// +optional
+	By *RateLimitKey `json:"by,omitempty"`
+}
+
+// RateLimitKey selects the key requests are counted by. Exactly one field must be set.
+type RateLimitKey struct {
+	// RemoteAddress counts requests per client address.
+	// +optional
+	RemoteAddress bool `json:"remoteAddress,omitempty"`
+
+	// Header counts requests per value of the request header. Requests without the header
+	// are not limited.
+	// +optional
+	Header string `json:"header,omitempty"`
+}
+
+// RateLimitPolicyStatus defines the observed state of RateLimitPolicy.
+type RateLimitPolicyStatus struct {
+	ResourceStatus `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=rlp
+// +kubebuilder:printcolumn:name="VirtualService",type="string",JSONPath=".spec.virtualService"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// RateLimitPolicy is the Schema for the ratelimitpolicies API. It limits the requests of a
+// VirtualService, or of some of its routes, with a rate limit service.
+type RateLimitPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RateLimitPolicySpec   `json:"spec,omitempty"`
+	Status RateLimitPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RateLimitPolicyList contains a list of RateLimitPolicy.
+type RateLimitPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RateLimitPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RateLimitPolicy{}, &RateLimitPolicyList{})
+}
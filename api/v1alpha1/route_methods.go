@@ -91,3 +91,8 @@ func (r *Route) Raw() []byte {
 	}
 	return data
 }
+
+// GetResourceStatus returns the status of the Route
+func (r *Route) GetResourceStatus() *ResourceStatus {
+	return &r.Status.ResourceStatus
+}
@@ -0,0 +1,71 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"errors"
+
+	runtimev3 "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
+	"github.com/kaasops/envoy-xds-controller/internal/protoutil"
+)
+
+func (r *Runtime) UnmarshalV3() (*runtimev3.Runtime, error) {
+	return r.unmarshalV3()
+}
+
+func (r *Runtime) UnmarshalV3AndValidate() (*runtimev3.Runtime, error) {
+	rtV3, err := r.unmarshalV3()
+	if err != nil {
+		return nil, err
+	}
+	if rtV3.Name == "" {
+		return nil, errors.New("runtime layer name is empty")
+	}
+	if err := rtV3.ValidateAll(); err != nil {
+		return nil, err
+	}
+	return rtV3, nil
+}
+
+func (r *Runtime) unmarshalV3() (*runtimev3.Runtime, error) {
+	if r.Spec == nil {
+		return nil, ErrSpecNil
+	}
+	var rtV3 runtimev3.Runtime
+	if err := protoutil.Unmarshaler.Unmarshal(r.Spec.Raw, &rtV3); err != nil {
+		return nil, err
+	}
+	return &rtV3, nil
+}
+
+// GetNodeIDs returns the nodes the runtime layer is served to, ["*"] for all nodes.
+func (r *Runtime) GetNodeIDs() []string {
+	return parseNodeIDs(r.GetAnnotations()[AnnotationNodeIDs])
+}
+
+func (r *Runtime) IsEqual(other *Runtime) bool {
+	if r == nil && other == nil {
+		return true
+	}
+	if r == nil || other == nil {
+		return false
+	}
+	if r.GetAnnotations()[AnnotationNodeIDs] != other.GetAnnotations()[AnnotationNodeIDs] {
+		return false
+	}
+	if r.Spec == nil && other.Spec == nil {
+		return true
+	}
+	if r.Spec == nil || other.Spec == nil {
+		return false
+	}
+	return bytes.Equal(r.Spec.Raw, other.Spec.Raw)
+}
+
+func (r *Runtime) GetDescription() string {
+	return r.Annotations[annotationDescription]
+}
+
+// GetResourceStatus returns the status of the Runtime
+func (r *Runtime) GetResourceStatus() *ResourceStatus {
+	return &r.Status.ResourceStatus
+}
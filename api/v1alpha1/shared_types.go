@@ -1,6 +1,15 @@
 package v1alpha1
 
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 const AnnotationSecretDomains = "envoy.kaasops.io/domains" // TODO: make private, access via getter
+
+// Comma-separated node IDs and access groups allowed to receive a Secret, '*' allows all.
+// A Secret without these annotations is not restricted.
+const (
+	AnnotationSecretAllowedNodeIDs      = "envoy.kaasops.io/allowed-node-ids"
+	AnnotationSecretAllowedAccessGroups = "envoy.kaasops.io/allowed-access-groups"
+)
 const annotationDescription = "envoy.kaasops.io/description"
 const GeneralAccessGroup = "general"
 
@@ -10,3 +19,38 @@ type ResourceRef struct {
 	Name      string  `json:"name,omitempty"`
 	Namespace *string `json:"namespace,omitempty"`
 }
+
+// Conditions of all resources, ConditionSynced is shared with VirtualServices.
+const (
+	// ConditionValid is True when the spec of the object passes validation.
+	ConditionValid = "Valid"
+	// ConditionInUse is True when VirtualServices are built from the object.
+	ConditionInUse = "InUse"
+	// ConditionReady is True when the object is valid and its configuration is not pending on proxies.
+	ConditionReady = "Ready"
+
+	ReasonValidated     = "Validated"
+	ReasonInvalid       = "Invalid"
+	ReasonReferenced    = "Referenced"
+	ReasonNotReferenced = "NotReferenced"
+	ReasonReady         = "Ready"
+)
+
+// ResourceStatus defines the observed state of resources other than VirtualServices.
+type ResourceStatus struct {
+	// ObservedGeneration is the generation of the object the status was computed from.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the object state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// VirtualServices built from the object, sorted by namespace and name.
+	// +optional
+	VirtualServices []ResourceRef `json:"virtualServices,omitempty"`
+}
@@ -39,3 +39,8 @@ func (t *Tracing) IsEqual(other *Tracing) bool {
 	}
 	return bytes.Equal(t.Spec.Raw, other.Spec.Raw)
 }
+
+// GetResourceStatus returns the status of the Tracing
+func (t *Tracing) GetResourceStatus() *ResourceStatus {
+	return &t.Status.ResourceStatus
+}
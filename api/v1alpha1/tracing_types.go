@@ -26,8 +26,7 @@ import (
 
 // TracingStatus defines the observed state of Tracing.
 type TracingStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	ResourceStatus `json:",inline"`
 }
 
 // +kubebuilder:object:root=true
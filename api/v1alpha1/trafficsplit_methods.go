@@ -0,0 +1,141 @@
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (ts *TrafficSplit) Validate() error {
+	if ts.Spec.VirtualService == "" {
+		return errors.New("virtualService is empty")
+	}
+	if ts.Spec.Route == "" {
+		return errors.New("route is empty")
+	}
+	if ts.Spec.Stable.Name == "" {
+		return errors.New("stable cluster name is empty")
+	}
+	if ts.Spec.Canary.Name == "" {
+		return errors.New("canary cluster name is empty")
+	}
+	if ts.GetStableNamespacedName() == ts.GetCanaryNamespacedName() {
+		return errors.New("stable and canary clusters are the same")
+	}
+	if len(ts.Spec.Steps) == 0 {
+		return errors.New("steps are empty")
+	}
+	for i, step := range ts.Spec.Steps {
+		if step.Weight > 100 {
+			return fmt.Errorf("step %d: weight %d is greater than 100", i, step.Weight)
+		}
+		if step.Duration.Duration < 0 {
+			return fmt.Errorf("step %d: duration is negative", i)
+		}
+	}
+	return nil
+}
+
+// GetVirtualServiceNamespacedName returns the VirtualService whose route is split
+func (ts *TrafficSplit) GetVirtualServiceNamespacedName() helpers.NamespacedName {
+	return helpers.NamespacedName{Namespace: ts.Namespace, Name: ts.Spec.VirtualService}
+}
+
+func (ts *TrafficSplit) GetStableNamespacedName() helpers.NamespacedName {
+	return helpers.NamespacedName{
+		Namespace: helpers.GetNamespace(ts.Spec.Stable.Namespace, ts.Namespace),
+		Name:      ts.Spec.Stable.Name,
+	}
+}
+
+func (ts *TrafficSplit) GetCanaryNamespacedName() helpers.NamespacedName {
+	return helpers.NamespacedName{
+		Namespace: helpers.GetNamespace(ts.Spec.Canary.Namespace, ts.Namespace),
+		Name:      ts.Spec.Canary.Name,
+	}
+}
+
+// CanaryWeight returns the percentage of traffic sent to the canary Cluster at the current
+// step, 0 when aborted.
+func (ts *TrafficSplit) CanaryWeight() uint32 {
+	if ts.Spec.Aborted || len(ts.Spec.Steps) == 0 {
+		return 0
+	}
+	return ts.Spec.Steps[ts.currentStep()].Weight
+}
+
+// currentStep returns the current step within the steps of the spec
+func (ts *TrafficSplit) currentStep() int32 {
+	last := int32(len(ts.Spec.Steps)) - 1
+	switch {
+	case ts.Status.CurrentStep < 0:
+		return 0
+	case ts.Status.CurrentStep > last:
+		return last
+	default:
+		return ts.Status.CurrentStep
+	}
+}
+
+// Progress moves the status to the step due at now and returns the time until the next step,
+// zero if the TrafficSplit is not progressing. Steps missed while the controller was down are
+// skipped, a paused or aborted TrafficSplit restarts its current step once resumed.
+func (ts *TrafficSplit) Progress(now time.Time) time.Duration {
+	st := &ts.Status
+	if len(ts.Spec.Steps) == 0 {
+		return 0
+	}
+	st.CurrentStep = ts.currentStep()
+	defer func() { st.CanaryWeight = ts.CanaryWeight() }()
+
+	switch {
+	case ts.Spec.Aborted:
+		st.Phase = TrafficSplitPhaseAborted
+		return 0
+	case ts.Spec.Paused:
+		st.Phase = TrafficSplitPhasePaused
+		return 0
+	}
+
+	if st.StepStartTime == nil || st.Phase == TrafficSplitPhasePaused || st.Phase == TrafficSplitPhaseAborted {
+		start := metav1.NewTime(now).Rfc3339Copy()
+		st.StepStartTime = &start
+	}
+	last := int32(len(ts.Spec.Steps)) - 1
+	for st.CurrentStep < last {
+		end := st.StepStartTime.Add(ts.Spec.Steps[st.CurrentStep].Duration.Duration)
+		if now.Before(end) {
+			st.Phase = TrafficSplitPhaseProgressing
+			return end.Sub(now)
+		}
+		start := metav1.NewTime(end).Rfc3339Copy()
+		st.StepStartTime = &start
+		st.CurrentStep++
+	}
+	st.Phase = TrafficSplitPhaseCompleted
+	return 0
+}
+
+// IsEqual reports whether both TrafficSplits render the same routes
+func (ts *TrafficSplit) IsEqual(other *TrafficSplit) bool {
+	if ts == nil && other == nil {
+		return true
+	}
+	if ts == nil || other == nil {
+		return false
+	}
+	return reflect.DeepEqual(ts.Spec, other.Spec) && ts.CanaryWeight() == other.CanaryWeight()
+}
+
+func (ts *TrafficSplit) GetDescription() string {
+	return ts.Annotations[annotationDescription]
+}
+
+// GetResourceStatus returns the status of the TrafficSplit
+func (ts *TrafficSplit) GetResourceStatus() *ResourceStatus {
+	return &ts.Status.ResourceStatus
+}
@@ -0,0 +1,56 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTrafficSplit_Progress(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts := &TrafficSplit{Spec: TrafficSplitSpec{Steps: []TrafficSplitStep{
+		{Weight: 5, Duration: metav1.Duration{Duration: time.Minute}},
+		{Weight: 25, Duration: metav1.Duration{Duration: 10 * time.Minute}},
+		{Weight: 100},
+	}}}
+
+	if requeue := ts.Progress(start); requeue != time.Minute {
+		t.Errorf("expected requeue after 1m, got %v", requeue)
+	}
+	if ts.Status.Phase != TrafficSplitPhaseProgressing || ts.Status.CurrentStep != 0 || ts.Status.CanaryWeight != 5 {
+		t.Errorf("expected first step in progress, got %+v", ts.Status)
+	}
+
+	if requeue := ts.Progress(start.Add(90 * time.Second)); requeue != 9*time.Minute+30*time.Second {
+		t.Errorf("expected requeue after 9m30s, got %v", requeue)
+	}
+	if ts.Status.CurrentStep != 1 || ts.Status.CanaryWeight != 25 {
+		t.Errorf("expected second step, got %+v", ts.Status)
+	}
+
+	// A paused TrafficSplit restarts its step once resumed
+	ts.Spec.Paused = true
+	if requeue := ts.Progress(start.Add(time.Hour)); requeue != 0 || ts.Status.Phase != TrafficSplitPhasePaused {
+		t.Errorf("expected paused without requeue, got %v %+v", requeue, ts.Status)
+	}
+	ts.Spec.Paused = false
+	if requeue := ts.Progress(start.Add(time.Hour)); requeue != 10*time.Minute || ts.Status.CurrentStep != 1 {
+		t.Errorf("expected second step restarted, got %v %+v", requeue, ts.Status)
+	}
+
+	// Aborting sends no traffic to the canary, missed steps are skipped
+	ts.Spec.Aborted = true
+	ts.Progress(start.Add(2 * time.Hour))
+	if ts.Status.Phase != TrafficSplitPhaseAborted || ts.Status.CanaryWeight != 0 || ts.CanaryWeight() != 0 {
+		t.Errorf("expected aborted without canary traffic, got %+v", ts.Status)
+	}
+	ts.Spec.Aborted = false
+	ts.Progress(start.Add(2 * time.Hour))
+	if requeue := ts.Progress(start.Add(3 * time.Hour)); requeue != 0 {
+		t.Errorf("expected no requeue once completed, got %v", requeue)
+	}
+	if ts.Status.Phase != TrafficSplitPhaseCompleted || ts.Status.CurrentStep != 2 || ts.Status.CanaryWeight != 100 {
+		t.Errorf("expected completed at the last step, got %+v", ts.Status)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TrafficSplitSpec defines the desired state of TrafficSplit.
+type TrafficSplitSpec struct {
+	// VirtualService is the name of the VirtualService in the namespace of the TrafficSplit
+	// whose route is split.
+	// +kubebuilder:validation:MinLength=1
+	VirtualService string `json:"virtualService"`
+
+	// Route is the name of the route of the VirtualService to split, including routes
+	// added by its template and additional routes.
+	// +kubebuilder:validation:MinLength=1
+	Route string `json:"route"`
+
+	// Stable is the Cluster receiving the traffic not sent to the canary.
+	// If namespace is omitted, it defaults to the TrafficSplit namespace.
+	Stable ResourceRef `json:"stable"`
+
+	// Canary is the Cluster receiving the weight of the current step.
+	// If namespace is omitted, it defaults to the TrafficSplit namespace.
+	Canary ResourceRef `json:"canary"`
+
+	// Steps are applied in order, each one for its duration. The last step is kept.
+	// +kubebuilder:validation:MinItems=1
+	Steps []TrafficSplitStep `json:"steps"`
+
+	// Paused holds the current step until it is unset. The step is restarted on resume.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Aborted sends all traffic to the stable Cluster until it is unset.
+	// +optional
+	Aborted bool `json:"aborted,omitempty"`
+}
+
+// TrafficSplitStep is a step of the schedule of a TrafficSplit.
+type TrafficSplitStep struct {
+	// Weight is the percentage of traffic sent to the canary Cluster.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Weight uint32 `json:"weight"`
+
+	// Duration is how long the step is applied before moving to the next one.
+	// It is ignored on the last step.
+	// +optional
+	Duration metav1.Duration `json:"duration,omitempty"`
+}
+
+// Phases of a TrafficSplit
+const (
+	TrafficSplitPhaseProgressing = "Progressing"
+	TrafficSplitPhasePaused      = "Paused"
+	TrafficSplitPhaseCompleted   = "Completed"
+	TrafficSplitPhaseAborted     = "Aborted"
+)
+
+// TrafficSplitStatus defines the observed state of TrafficSplit.
+type TrafficSplitStatus struct {
+	ResourceStatus `json:",inline"`
+
+	// Phase is one of Progressing, Paused, Completed or Aborted.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// CurrentStep is the index of the step being applied.
+	// +optional
+	CurrentStep int32 `json:"currentStep,omitempty"`
+
+	// CanaryWeight is the percentage of traffic currently sent to the canary Cluster.
+	// +optional
+	CanaryWeight uint32 `json:"canaryWeight,omitempty"`
+
+	// StepStartTime is when the current step started.
+	// +optional
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=ts
+// +kubebuilder:printcolumn:name="VirtualService",type="string",JSONPath=".spec.virtualService"
+// +kubebuilder:printcolumn:name="Route",type="string",JSONPath=".spec.route"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Step",type="integer",JSONPath=".status.currentStep"
+// +kubebuilder:printcolumn:name="Weight",type="integer",JSONPath=".status.canaryWeight"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// TrafficSplit is the Schema for the trafficsplits API. It shifts the traffic of a route of a
+// VirtualService from a stable Cluster to a canary one, following a schedule of weights.
+type TrafficSplit struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TrafficSplitSpec   `json:"spec,omitempty"`
+	Status TrafficSplitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TrafficSplitList contains a list of TrafficSplit.
+type TrafficSplitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TrafficSplit `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TrafficSplit{}, &TrafficSplitList{})
+}
@@ -3,10 +3,18 @@ package v1alpha1
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// minLocalRateLimitFillInterval is the shortest fill interval of token buckets accepted by Envoy
+const minLocalRateLimitFillInterval = 50 * time.Millisecond
+
 type VirtualServiceCommonSpec struct {
 	VirtualHost           *runtime.RawExtension   `json:"virtualHost,omitempty"`
 	Listener              *ResourceRef            `json:"listener,omitempty"`
@@ -53,6 +61,95 @@ type VirtualServiceCommonSpec struct {
 	// See: https://www.envoyproxy.io/docs/envoy/latest/api-v3/config/core/v3/protocol.proto
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Http2ProtocolOptions *runtime.RawExtension `json:"http2ProtocolOptions,omitempty"`
+
+	// Canary routes requests with any of the headers or cookies to a canary Cluster. Each route
+	// to a cluster is preceded by a copy matching these requests and routing them to the canary.
+	Canary *CanarySpec `json:"canary,omitempty"`
+
+	// Mirror shadows a fraction of the requests of routes to a Cluster, rendered into their
+	// request_mirror_policies. Responses of the mirror Cluster are ignored.
+	Mirror *MirrorSpec `json:"mirror,omitempty"`
+
+	// LocalRateLimit limits requests with token buckets held by each Envoy, rendered into the
+	// envoy.filters.http.local_ratelimit filter and the typed_per_filter_config of routes.
+	LocalRateLimit *LocalRateLimitSpec `json:"localRateLimit,omitempty"`
+}
+
+type CanarySpec struct {
+	// Cluster receiving canary requests.
+	// If namespace is omitted, it defaults to the VirtualService namespace.
+	Cluster ResourceRef `json:"cluster"`
+
+	// Routes are the names of the routes to copy, all routes to a cluster if empty.
+	// +optional
+	Routes []string `json:"routes,omitempty"`
+
+	// Headers matching canary requests. A header without value matches when it is present.
+	// +optional
+	Headers []CanaryMatch `json:"headers,omitempty"`
+
+	// Cookies matching canary requests. A cookie without value matches when it is present.
+	// +optional
+	Cookies []CanaryMatch `json:"cookies,omitempty"`
+}
+
+type CanaryMatch struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Value is matched exactly.
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+type MirrorSpec struct {
+	// Cluster receiving the copies of the requests.
+	// If namespace is omitted, it defaults to the VirtualService namespace.
+	Cluster ResourceRef `json:"cluster"`
+
+	// Routes are the names of the routes to mirror, all routes to a cluster if empty.
+	// +optional
+	Routes []string `json:"routes,omitempty"`
+
+	// Percent of the requests mirrored, 100 if omitted.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Percent *uint32 `json:"percent,omitempty"`
+
+	// RuntimeKey overrides the percent with the runtime value of the key, if set.
+	// +optional
+	RuntimeKey string `json:"runtimeKey,omitempty"`
+
+	// TraceSampled sets the trace sampling decision of mirrored requests, the one of the
+	// original requests if omitted.
+	// +optional
+	TraceSampled *bool `json:"traceSampled,omitempty"`
+}
+
+type LocalRateLimitSpec struct {
+	// Bucket shared by the requests of routes without an override. Only routes with an
+	// override are limited if omitted.
+	LocalRateLimitBucket `json:",inline"`
+
+	// Routes override fields of the bucket by route name, each of these routes getting a bucket
+	// of its own. Omitted fields are taken from the bucket of the VirtualService.
+	// +optional
+	Routes map[string]LocalRateLimitBucket `json:"routes,omitempty"`
+}
+
+type LocalRateLimitBucket struct {
+	// Tokens added to the bucket every fill interval, each request taking one.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Tokens uint32 `json:"tokens,omitempty"`
+
+	// MaxTokens is the capacity of the bucket, tokens if omitted.
+	// +optional
+	MaxTokens uint32 `json:"maxTokens,omitempty"`
+
+	// FillInterval between additions of tokens, at least 50ms.
+	// +optional
+	FillInterval *metav1.Duration `json:"fillInterval,omitempty"`
 }
 
 type TlsConfig struct {
@@ -87,3 +184,81 @@ func (vsc *VirtualServiceCommonSpec) IsEqual(other *VirtualServiceCommonSpec) bo
 	}
 	return bytes.Equal(vscBytes, vscOtherBytes)
 }
+
+// IsSet reports whether any field of the bucket is set
+func (b *LocalRateLimitBucket) IsSet() bool {
+	return b.Tokens != 0 || b.MaxTokens != 0 || b.FillInterval != nil
+}
+
+// Validate checks the bucket is complete
+func (b *LocalRateLimitBucket) Validate() error {
+	if b.Tokens == 0 {
+		return errors.New("tokens must be greater than 0")
+	}
+	if b.MaxTokens != 0 && b.MaxTokens < b.Tokens {
+		return errors.New("maxTokens must not be lower than tokens")
+	}
+	if b.FillInterval == nil {
+		return errors.New("fillInterval is required")
+	}
+	if b.FillInterval.Duration < minLocalRateLimitFillInterval {
+		return fmt.Errorf("fillInterval must be at least %s", minLocalRateLimitFillInterval)
+	}
+	return nil
+}
+
+// RouteNames returns the names of the routes with an override, sorted
+func (l *LocalRateLimitSpec) RouteNames() []string {
+	names := make([]string, 0, len(l.Routes))
+	for name := range l.Routes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// RouteBucket returns the override of the route completed with the fields of the bucket of the
+// VirtualService, and whether the route has one.
+func (l *LocalRateLimitSpec) RouteBucket(name string) (LocalRateLimitBucket, bool) {
+	override, ok := l.Routes[name]
+	if !ok {
+		return LocalRateLimitBucket{}, false
+	}
+	bucket := l.LocalRateLimitBucket
+	if override.Tokens != 0 {
+		bucket.Tokens = override.Tokens
+	}
+	if override.MaxTokens != 0 {
+		bucket.MaxTokens = override.MaxTokens
+	}
+	if override.FillInterval != nil {
+		bucket.FillInterval = override.FillInterval
+	}
+	return bucket, true
+}
+
+// Validate checks the bucket of the VirtualService, if set, and the bucket of each route with an
+// override. A VirtualService built from a template is only complete once filled from it.
+func (l *LocalRateLimitSpec) Validate() error {
+	if l == nil {
+		return nil
+	}
+	if !l.IsSet() && len(l.Routes) == 0 {
+		return errors.New("no bucket is set")
+	}
+	if l.IsSet() {
+		if err := l.LocalRateLimitBucket.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, name := range l.RouteNames() {
+		if name == "" {
+			return errors.New("route name is empty")
+		}
+		bucket, _ := l.RouteBucket(name)
+		if err := bucket.Validate(); err != nil {
+			return fmt.Errorf("route %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
 	"github.com/kaasops/envoy-xds-controller/internal/merge"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
@@ -17,11 +18,33 @@ const (
 	AnnotationEditable = "envoy.kaasops.io/editable"
 	LabelAccessGroup   = "exc-access-group"
 	LabelName          = "exc-name"
+	// AnnotationNodeSelector selects nodes of connected Envoys by a label selector over their
+	// node metadata, cluster and locality, e.g. "env=prod,region=eu"
+	AnnotationNodeSelector = "envoy.kaasops.io/node-selector"
+)
+
+const (
+	// ConditionRejected is set on a VirtualService when Envoy NACKed resources built from it.
+	ConditionRejected = "Rejected"
+
+	ReasonEnvoyNACK = "EnvoyNACK"
+	ReasonAccepted  = "Accepted"
+
+	// ConditionSynced is set on a VirtualService when all connected proxies of its nodes
+	// acknowledged a snapshot containing its current configuration.
+	ConditionSynced = "Synced"
+
+	ReasonAcknowledged = "Acknowledged"
+	ReasonPending      = "Pending"
+	ReasonNoProxies    = "NoProxies"
 )
 
 func (vs *VirtualService) GetNodeIDs() []string {
-	annotations := vs.GetAnnotations()
-	nodeIDsAnnotation := annotations[AnnotationNodeIDs]
+	return parseNodeIDs(vs.GetAnnotations()[AnnotationNodeIDs])
+}
+
+// parseNodeIDs returns the unique node IDs of a comma-separated node IDs annotation.
+func parseNodeIDs(nodeIDsAnnotation string) []string {
 	if nodeIDsAnnotation == "" {
 		return nil
 	}
@@ -37,6 +60,19 @@ func (vs *VirtualService) GetNodeIDs() []string {
 	return list
 }
 
+// GetNodeSelector returns the node selector of the VirtualService, nil if it has none.
+func (vs *VirtualService) GetNodeSelector() (labels.Selector, error) {
+	selector := strings.TrimSpace(vs.GetAnnotations()[AnnotationNodeSelector])
+	if selector == "" {
+		return nil, nil
+	}
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node selector %q: %w", selector, err)
+	}
+	return parsed, nil
+}
+
 func (vs *VirtualService) SetNodeIDs(nodeIDs []string) {
 	annotations := vs.GetAnnotations()
 	if len(nodeIDs) == 0 {
@@ -203,6 +239,9 @@ func (vs *VirtualService) IsEqual(other *VirtualService) bool {
 	if vsNodeIDs != otherNodeIDs {
 		return false
 	}
+	if vs.Annotations[AnnotationNodeSelector] != other.Annotations[AnnotationNodeSelector] {
+		return false
+	}
 	if !vs.Spec.VirtualServiceCommonSpec.IsEqual(&other.Spec.VirtualServiceCommonSpec) {
 		return false
 	}
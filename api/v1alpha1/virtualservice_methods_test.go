@@ -1,7 +1,9 @@
 package v1alpha1
 
 import (
+	"slices"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -287,3 +289,56 @@ func TestVirtualService_NormalizeSpec_TracingRefNamespaceDefault(t *testing.T) {
 		t.Fatalf("expected tracingRef.namespace %q, got %q", vs.Namespace, got)
 	}
 }
+
+func TestVirtualService_FillFromTemplate_LocalRateLimit(t *testing.T) {
+	vst := &VirtualServiceTemplate{}
+	vst.Spec.LocalRateLimit = &LocalRateLimitSpec{
+		LocalRateLimitBucket: LocalRateLimitBucket{Tokens: 100, FillInterval: &metav1.Duration{Duration: time.Second}},
+		Routes: map[string]LocalRateLimitBucket{
+			"api":    {Tokens: 10},
+			"upload": {Tokens: 1, FillInterval: &metav1.Duration{Duration: time.Minute}},
+		},
+	}
+	vs := &VirtualService{}
+	vs.Spec.LocalRateLimit = &LocalRateLimitSpec{
+		LocalRateLimitBucket: LocalRateLimitBucket{MaxTokens: 200},
+		Routes: map[string]LocalRateLimitBucket{
+			"api":    {Tokens: 20},
+			"health": {Tokens: 150},
+		},
+	}
+	if err := vs.FillFromTemplate(vst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spec := vs.Spec.LocalRateLimit
+	if err := spec.Validate(); err != nil {
+		t.Fatalf("expected merged local rate limit to be valid, got %v", err)
+	}
+	if spec.Tokens != 100 || spec.MaxTokens != 200 || spec.FillInterval.Duration != time.Second {
+		t.Errorf("unexpected bucket of the VirtualService: %+v", spec.LocalRateLimitBucket)
+	}
+	if names := spec.RouteNames(); !slices.Equal(names, []string{"api", "health", "upload"}) {
+		t.Errorf("expected routes of the template and the VirtualService, got %v", names)
+	}
+
+	// Route overrides are completed with the bucket of the VirtualService
+	expected := map[string]LocalRateLimitBucket{
+		"api":    {Tokens: 20, MaxTokens: 200, FillInterval: &metav1.Duration{Duration: time.Second}},
+		"health": {Tokens: 150, MaxTokens: 200, FillInterval: &metav1.Duration{Duration: time.Second}},
+		"upload": {Tokens: 1, MaxTokens: 200, FillInterval: &metav1.Duration{Duration: time.Minute}},
+	}
+	for name, want := range expected {
+		got, ok := spec.RouteBucket(name)
+		if !ok || got.Tokens != want.Tokens || got.MaxTokens != want.MaxTokens ||
+			got.FillInterval.Duration != want.FillInterval.Duration {
+			t.Errorf("route %s: expected bucket %+v, got %+v", name, want, got)
+		}
+	}
+
+	// Tokens of overrides must fit in the capacity inherited from the VirtualService
+	spec.Routes["health"] = LocalRateLimitBucket{Tokens: 1000}
+	if err := spec.Validate(); err == nil {
+		t.Errorf("expected maxTokens lower than tokens to be invalid")
+	}
+}
@@ -102,3 +102,8 @@ func (vst *VirtualServiceTemplate) Raw() []byte {
 	}
 	return data
 }
+
+// GetResourceStatus returns the status of the VirtualServiceTemplate
+func (vst *VirtualServiceTemplate) GetResourceStatus() *ResourceStatus {
+	return &vst.Status.ResourceStatus
+}
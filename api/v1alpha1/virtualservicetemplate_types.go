@@ -50,8 +50,7 @@ type ExtraField struct {
 
 // VirtualServiceTemplateStatus defines the observed state of VirtualServiceTemplate.
 type VirtualServiceTemplateStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	ResourceStatus `json:",inline"`
 }
 
 // +kubebuilder:object:root=true
@@ -21,7 +21,8 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -34,7 +35,7 @@ func (in *AccessLogConfig) DeepCopyInto(out *AccessLogConfig) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessLogConfig.
@@ -90,6 +91,7 @@ func (in *AccessLogConfigList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccessLogConfigStatus) DeepCopyInto(out *AccessLogConfigStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessLogConfigStatus.
@@ -102,6 +104,52 @@ func (in *AccessLogConfigStatus) DeepCopy() *AccessLogConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryMatch) DeepCopyInto(out *CanaryMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryMatch.
+func (in *CanaryMatch) DeepCopy() *CanaryMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]CanaryMatch, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cookies != nil {
+		in, out := &in.Cookies, &out.Cookies
+		*out = make([]CanaryMatch, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanarySpec.
+func (in *CanarySpec) DeepCopy() *CanarySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CanarySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -112,7 +160,7 @@ func (in *Cluster) DeepCopyInto(out *Cluster) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cluster.
@@ -168,6 +216,7 @@ func (in *ClusterList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -180,6 +229,85 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Endpoint) DeepCopyInto(out *Endpoint) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Endpoint.
+func (in *Endpoint) DeepCopy() *Endpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(Endpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Endpoint) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointList) DeepCopyInto(out *EndpointList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Endpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointList.
+func (in *EndpointList) DeepCopy() *EndpointList {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EndpointList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointStatus) DeepCopyInto(out *EndpointStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointStatus.
+func (in *EndpointStatus) DeepCopy() *EndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExtraField) DeepCopyInto(out *ExtraField) {
 	*out = *in
@@ -216,7 +344,7 @@ func (in *HttpFilter) DeepCopyInto(out *HttpFilter) {
 			}
 		}
 	}
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HttpFilter.
@@ -272,6 +400,7 @@ func (in *HttpFilterList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HttpFilterStatus) DeepCopyInto(out *HttpFilterStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HttpFilterStatus.
@@ -294,7 +423,7 @@ func (in *Listener) DeepCopyInto(out *Listener) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Listener.
@@ -350,6 +479,7 @@ func (in *ListenerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ListenerStatus) DeepCopyInto(out *ListenerStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListenerStatus.
@@ -362,6 +492,80 @@ func (in *ListenerStatus) DeepCopy() *ListenerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalRateLimitBucket) DeepCopyInto(out *LocalRateLimitBucket) {
+	*out = *in
+	if in.FillInterval != nil {
+		in, out := &in.FillInterval, &out.FillInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalRateLimitBucket.
+func (in *LocalRateLimitBucket) DeepCopy() *LocalRateLimitBucket {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalRateLimitBucket)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalRateLimitSpec) DeepCopyInto(out *LocalRateLimitSpec) {
+	*out = *in
+	in.LocalRateLimitBucket.DeepCopyInto(&out.LocalRateLimitBucket)
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make(map[string]LocalRateLimitBucket, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalRateLimitSpec.
+func (in *LocalRateLimitSpec) DeepCopy() *LocalRateLimitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalRateLimitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirrorSpec) DeepCopyInto(out *MirrorSpec) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Percent != nil {
+		in, out := &in.Percent, &out.Percent
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.TraceSampled != nil {
+		in, out := &in.TraceSampled, &out.TraceSampled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorSpec.
+func (in *MirrorSpec) DeepCopy() *MirrorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MirrorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Policy) DeepCopyInto(out *Policy) {
 	*out = *in
@@ -372,7 +576,7 @@ func (in *Policy) DeepCopyInto(out *Policy) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Policy.
@@ -428,6 +632,7 @@ func (in *PolicyList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyStatus) DeepCopyInto(out *PolicyStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyStatus.
@@ -440,6 +645,165 @@ func (in *PolicyStatus) DeepCopy() *PolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+	*out = *in
+	if in.By != nil {
+		in, out := &in.By, &out.By
+		*out = new(RateLimitKey)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
+func (in *RateLimit) DeepCopy() *RateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitKey) DeepCopyInto(out *RateLimitKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitKey.
+func (in *RateLimitKey) DeepCopy() *RateLimitKey {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitPolicy) DeepCopyInto(out *RateLimitPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitPolicy.
+func (in *RateLimitPolicy) DeepCopy() *RateLimitPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RateLimitPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitPolicyList) DeepCopyInto(out *RateLimitPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RateLimitPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitPolicyList.
+func (in *RateLimitPolicyList) DeepCopy() *RateLimitPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RateLimitPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitPolicySpec) DeepCopyInto(out *RateLimitPolicySpec) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Service.DeepCopyInto(&out.Service)
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make([]RateLimit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitPolicySpec.
+func (in *RateLimitPolicySpec) DeepCopy() *RateLimitPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitPolicyStatus) DeepCopyInto(out *RateLimitPolicyStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitPolicyStatus.
+func (in *RateLimitPolicyStatus) DeepCopy() *RateLimitPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitService) DeepCopyInto(out *RateLimitService) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitService.
+func (in *RateLimitService) DeepCopy() *RateLimitService {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitService)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceRef) DeepCopyInto(out *ResourceRef) {
 	*out = *in
@@ -460,6 +824,35 @@ func (in *ResourceRef) DeepCopy() *ResourceRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceStatus) DeepCopyInto(out *ResourceStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VirtualServices != nil {
+		in, out := &in.VirtualServices, &out.VirtualServices
+		*out = make([]ResourceRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceStatus.
+func (in *ResourceStatus) DeepCopy() *ResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Route) DeepCopyInto(out *Route) {
 	*out = *in
@@ -476,7 +869,7 @@ func (in *Route) DeepCopyInto(out *Route) {
 			}
 		}
 	}
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Route.
@@ -532,6 +925,7 @@ func (in *RouteList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteStatus) DeepCopyInto(out *RouteStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteStatus.
@@ -544,6 +938,85 @@ func (in *RouteStatus) DeepCopy() *RouteStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Runtime) DeepCopyInto(out *Runtime) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Runtime.
+func (in *Runtime) DeepCopy() *Runtime {
+	if in == nil {
+		return nil
+	}
+	out := new(Runtime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Runtime) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeList) DeepCopyInto(out *RuntimeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Runtime, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeList.
+func (in *RuntimeList) DeepCopy() *RuntimeList {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RuntimeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeStatus) DeepCopyInto(out *RuntimeStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeStatus.
+func (in *RuntimeStatus) DeepCopy() *RuntimeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateOpts) DeepCopyInto(out *TemplateOpts) {
 	*out = *in
@@ -594,7 +1067,7 @@ func (in *Tracing) DeepCopyInto(out *Tracing) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tracing.
@@ -650,6 +1123,7 @@ func (in *TracingList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TracingStatus) DeepCopyInto(out *TracingStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingStatus.
@@ -662,6 +1136,123 @@ func (in *TracingStatus) DeepCopy() *TracingStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficSplit) DeepCopyInto(out *TrafficSplit) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficSplit.
+func (in *TrafficSplit) DeepCopy() *TrafficSplit {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficSplit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TrafficSplit) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficSplitList) DeepCopyInto(out *TrafficSplitList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TrafficSplit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficSplitList.
+func (in *TrafficSplitList) DeepCopy() *TrafficSplitList {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficSplitList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TrafficSplitList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficSplitSpec) DeepCopyInto(out *TrafficSplitSpec) {
+	*out = *in
+	in.Stable.DeepCopyInto(&out.Stable)
+	in.Canary.DeepCopyInto(&out.Canary)
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]TrafficSplitStep, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficSplitSpec.
+func (in *TrafficSplitSpec) DeepCopy() *TrafficSplitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficSplitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficSplitStatus) DeepCopyInto(out *TrafficSplitStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	if in.StepStartTime != nil {
+		in, out := &in.StepStartTime, &out.StepStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficSplitStatus.
+func (in *TrafficSplitStatus) DeepCopy() *TrafficSplitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficSplitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficSplitStep) DeepCopyInto(out *TrafficSplitStep) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficSplitStep.
+func (in *TrafficSplitStep) DeepCopy() *TrafficSplitStep {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficSplitStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualService) DeepCopyInto(out *VirtualService) {
 	*out = *in
@@ -813,6 +1404,21 @@ func (in *VirtualServiceCommonSpec) DeepCopyInto(out *VirtualServiceCommonSpec)
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanarySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(MirrorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LocalRateLimit != nil {
+		in, out := &in.LocalRateLimit, &out.LocalRateLimit
+		*out = new(LocalRateLimitSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualServiceCommonSpec.
@@ -947,6 +1553,13 @@ func (in *VirtualServiceStatus) DeepCopyInto(out *VirtualServiceStatus) {
 		*out = new(uint32)
 		**out = **in
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualServiceStatus.
@@ -965,7 +1578,7 @@ func (in *VirtualServiceTemplate) DeepCopyInto(out *VirtualServiceTemplate) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualServiceTemplate.
@@ -1048,6 +1661,7 @@ func (in *VirtualServiceTemplateSpec) DeepCopy() *VirtualServiceTemplateSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualServiceTemplateStatus) DeepCopyInto(out *VirtualServiceTemplateStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualServiceTemplateStatus.
@@ -20,10 +20,12 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"flag"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
@@ -32,6 +34,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	xdsClients "github.com/kaasops/envoy-xds-controller/internal/xds/clients"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/nack"
 
 	"github.com/kaasops/envoy-xds-controller/internal/filewatcher"
 
@@ -40,6 +43,8 @@ import (
 	"github.com/go-logr/zapr"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/ratelimit"
 	"github.com/kaasops/envoy-xds-controller/internal/store"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -48,11 +53,15 @@ import (
 
 	"github.com/envoyproxy/go-control-plane/pkg/server/v3"
 	"github.com/kelseyhightower/envconfig"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/kaasops/envoy-xds-controller/internal/xds"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/api"
+	xdsauth "github.com/kaasops/envoy-xds-controller/internal/xds/auth"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/cache"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
 
@@ -60,9 +69,11 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -93,6 +104,21 @@ type Config struct {
 	TargetNamespace       string   `default:"envoy-xds-controller" envconfig:"TARGET_NAMESPACE"` // ns for creating cr
 	XDS                   struct {
 		Port int `default:"9000" envconfig:"XDS_PORT"`
+		// RebuildWindow enables coalesced snapshot rebuilds: changes within the window are merged into one rebuild
+		RebuildWindow time.Duration `default:"0s" envconfig:"XDS_REBUILD_WINDOW"`
+		// RebuildMaxLatency bounds the delay between the first pending change and the coalesced rebuild
+		RebuildMaxLatency time.Duration `default:"1s" envconfig:"XDS_REBUILD_MAX_LATENCY"`
+		// DefaultNodeID is the node whose configuration is served to Envoys no VirtualService selects
+		DefaultNodeID string `default:"" envconfig:"XDS_DEFAULT_NODE_ID"`
+		TLS           struct {
+			Enabled  bool   `default:"false"                envconfig:"XDS_TLS_ENABLED"`
+			CertFile string `default:"/etc/xds-tls/tls.crt" envconfig:"XDS_TLS_CERT_FILE"`
+			KeyFile  string `default:"/etc/xds-tls/tls.key" envconfig:"XDS_TLS_KEY_FILE"`
+			// ClientCAFile enables verification of client certificates; required for authorization
+			ClientCAFile string `default:"" envconfig:"XDS_TLS_CLIENT_CA_FILE"`
+		}
+		// AuthPolicyFile binds client certificate identities to the node IDs they may request
+		AuthPolicyFile string `default:"" envconfig:"XDS_AUTH_POLICY_FILE"`
 	}
 	Webhook struct {
 		TLSSecretName  string `default:"envoy-xds-controller-webhook-cert"           envconfig:"WEBHOOK_TLS_SECRET_NAME"`
@@ -107,6 +133,11 @@ type Config struct {
 		// ValidationIndices enables store-backed validation indices for O(1) domain and listener address conflict detection
 		ValidationIndices bool `default:"false"                                       envconfig:"WEBHOOK_VALIDATION_INDICES"`
 	}
+	// RateLimit is the built-in rate limit service RateLimitPolicies can point to
+	RateLimit struct {
+		Enabled bool `default:"false" envconfig:"RATE_LIMIT_SERVICE_ENABLED"`
+		Port    int  `default:"9001"  envconfig:"RATE_LIMIT_SERVICE_PORT"`
+	}
 }
 
 func (c *Config) GetNamespaceForResourceCreation() string {
@@ -141,7 +172,7 @@ func main() {
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
+			"Every replica serves xDS, only the leader writes statuses and webhook certificates.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
@@ -248,6 +279,10 @@ func main() {
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
 		// LeaderElectionReleaseOnCancel: true,
+
+		// Every replica fills its store from informers and serves xDS, so controllers run
+		// on followers too. Writes to the cluster are limited to the leader.
+		Controller: config.Controller{NeedLeaderElection: ptr.To(false)},
 	}
 	if mgrCacheOpts := managerCacheOptions(&cfg); mgrCacheOpts != nil {
 		setupLog.Info("watching namespaces", "namespaces", cfg.WatchNamespaces)
@@ -265,6 +300,7 @@ func main() {
 
 	snapshotCache := cache.NewSnapshotCache()
 	cacheUpdater := updater.NewCacheUpdater(snapshotCache, resStore)
+	cacheUpdater.SetDefaultNodeID(cfg.XDS.DefaultNodeID)
 	fWatcher, err := filewatcher.NewFileWatcher()
 	if err != nil {
 		setupLog.Error(err, "unable to create file watcher")
@@ -275,20 +311,115 @@ func main() {
 	cacheReadyCh := make(chan struct{})
 	vsReconcileChan := make(chan event.GenericEvent)
 
+	// Objects VirtualServices are built from list them in their statuses
+	statusWriter := controller.NewStatusWriter(mgr.GetClient(), cacheUpdater, mgr.Elected())
+	cacheUpdater.SetOnReferencesChange(statusWriter.Enqueue)
+
+	// enqueueVirtualServices triggers reconciliation of VirtualServices whose status changed outside of their reconcile,
+	// and of the objects they are built from, whose statuses aggregate them
+	enqueueVirtualServices := func(vsNNs []helpers.NamespacedName) {
+		go func() {
+			for _, nn := range vsNNs {
+				vsReconcileChan <- event.GenericEvent{
+					Object: &envoyv1alpha1.VirtualService{
+						ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace},
+					},
+				}
+			}
+		}()
+		statusWriter.Enqueue(cacheUpdater.GetReferences(vsNNs...))
+	}
+	cacheUpdater.SetOnStatusChange(enqueueVirtualServices)
+
+	nackTracker := nack.NewTracker(cacheUpdater)
+	nackTracker.SetOnChange(enqueueVirtualServices)
+
+	// ACKs of connected proxies update the Synced condition of VirtualServices served on their nodes
+	connectedClients := xdsClients.NewRegistry()
+	connectedClients.SetOnChange(func(nodeID string) {
+		enqueueVirtualServices(cacheUpdater.GetVirtualServicesForNode(nodeID))
+	})
+	cacheUpdater.SetAckSource(connectedClients)
+
 	if err = (&controller.ClusterReconciler{
 		Client:         mgr.GetClient(),
 		Scheme:         mgr.GetScheme(),
 		Updater:        cacheUpdater,
 		CacheReadyChan: cacheReadyCh,
+		StatusWriter:   statusWriter,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
 		os.Exit(1)
 	}
+	if err = (&controller.EndpointReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Updater:        cacheUpdater,
+		CacheReadyChan: cacheReadyCh,
+		StatusWriter:   statusWriter,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Endpoint")
+		os.Exit(1)
+	}
+	if err = (&controller.EndpointSliceReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Updater:        cacheUpdater,
+		CacheReadyChan: cacheReadyCh,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "EndpointSlice")
+		os.Exit(1)
+	}
+	if err = (&controller.RuntimeReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Updater:        cacheUpdater,
+		CacheReadyChan: cacheReadyCh,
+		StatusWriter:   statusWriter,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Runtime")
+		os.Exit(1)
+	}
+	// TrafficSplits which cannot be applied to their routes report it in their statuses
+	tsReconcileChan := make(chan event.GenericEvent)
+	cacheUpdater.SetOnTrafficSplitsChange(func(tsNNs []helpers.NamespacedName) {
+		go func() {
+			for _, nn := range tsNNs {
+				tsReconcileChan <- event.GenericEvent{
+					Object: &envoyv1alpha1.TrafficSplit{
+						ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace},
+					},
+				}
+			}
+		}()
+	})
+	if err = (&controller.TrafficSplitReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Updater:        cacheUpdater,
+		CacheReadyChan: cacheReadyCh,
+		StatusWriter:   statusWriter,
+		ReconcileChan:  tsReconcileChan,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TrafficSplit")
+		os.Exit(1)
+	}
+	if err = (&controller.RateLimitPolicyReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Updater:        cacheUpdater,
+		CacheReadyChan: cacheReadyCh,
+		StatusWriter:   statusWriter,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RateLimitPolicy")
+		os.Exit(1)
+	}
 	if err = (&controller.ListenerReconciler{
 		Client:         mgr.GetClient(),
 		Scheme:         mgr.GetScheme(),
 		Updater:        cacheUpdater,
 		CacheReadyChan: cacheReadyCh,
+		StatusWriter:   statusWriter,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Listener")
 		os.Exit(1)
@@ -298,6 +429,7 @@ func main() {
 		Scheme:         mgr.GetScheme(),
 		Updater:        cacheUpdater,
 		CacheReadyChan: cacheReadyCh,
+		StatusWriter:   statusWriter,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Route")
 		os.Exit(1)
@@ -308,6 +440,9 @@ func main() {
 		Updater:         cacheUpdater,
 		CacheReadyChan:  cacheReadyCh,
 		VSReconcileChan: vsReconcileChan,
+		NackTracker:     nackTracker,
+		Recorder:        mgr.GetEventRecorderFor("virtualservice-controller"),
+		Elected:         mgr.Elected(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "VirtualService")
 		os.Exit(1)
@@ -317,6 +452,7 @@ func main() {
 		Scheme:         mgr.GetScheme(),
 		Updater:        cacheUpdater,
 		CacheReadyChan: cacheReadyCh,
+		StatusWriter:   statusWriter,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AccessLogConfig")
 		os.Exit(1)
@@ -326,6 +462,7 @@ func main() {
 		Scheme:         mgr.GetScheme(),
 		Updater:        cacheUpdater,
 		CacheReadyChan: cacheReadyCh,
+		StatusWriter:   statusWriter,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "HttpFilter")
 		os.Exit(1)
@@ -335,6 +472,7 @@ func main() {
 		Scheme:         mgr.GetScheme(),
 		Updater:        cacheUpdater,
 		CacheReadyChan: cacheReadyCh,
+		StatusWriter:   statusWriter,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Policy")
 		os.Exit(1)
@@ -345,6 +483,7 @@ func main() {
 		Updater:         cacheUpdater,
 		CacheReadyChan:  cacheReadyCh,
 		VSReconcileChan: vsReconcileChan,
+		StatusWriter:    statusWriter,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "VirtualServiceTemplate")
 		os.Exit(1)
@@ -363,6 +502,7 @@ func main() {
 		Scheme:         mgr.GetScheme(),
 		Updater:        cacheUpdater,
 		CacheReadyChan: cacheReadyCh,
+		StatusWriter:   statusWriter,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Tracing")
 		os.Exit(1)
@@ -401,8 +541,13 @@ func main() {
 				Namespace: cfg.InstallationNamespace,
 			},
 		}
-		// Reconcile secret with TLS for webhook
-		if err := webhookReconciler.ReconcileCertificates(context.Background(), certSecret); err != nil {
+		// Reconcile secret with TLS for webhook. With leader election it is only bootstrapped here,
+		// the leader maintains it.
+		reconcileCertificates := webhookReconciler.ReconcileCertificates
+		if enableLeaderElection {
+			reconcileCertificates = webhookReconciler.BootstrapCertificates
+		}
+		if err := reconcileCertificates(context.Background(), certSecret); err != nil {
 			setupLog.Error(err, "unable to reconcile webhook secret")
 			os.Exit(1)
 		}
@@ -419,6 +564,22 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Cluster")
 			os.Exit(1)
 		}
+		if err = webhookenvoyv1alpha1.SetupEndpointWebhookWithManager(mgr, cacheUpdater); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Endpoint")
+			os.Exit(1)
+		}
+		if err = webhookenvoyv1alpha1.SetupRuntimeWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Runtime")
+			os.Exit(1)
+		}
+		if err = webhookenvoyv1alpha1.SetupTrafficSplitWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TrafficSplit")
+			os.Exit(1)
+		}
+		if err = webhookenvoyv1alpha1.SetupRateLimitPolicyWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RateLimitPolicy")
+			os.Exit(1)
+		}
 		if err = webhookenvoyv1alpha1.SetupRouteWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Route")
 			os.Exit(1)
@@ -480,22 +641,50 @@ func main() {
 		} else {
 			setupLog.Info("cache was successfully built")
 		}
+		cacheUpdater.StartRebuildScheduler(ctx, cfg.XDS.RebuildWindow, cfg.XDS.RebuildMaxLatency)
 
 		close(cacheReadyCh)
 
-		connectedClients := xdsClients.NewRegistry()
+		// Envoys connecting with a node not listed by VirtualServices get their snapshots built on demand
+		connectedClients.SetOnNode(
+			func(info xdsClients.Info) {
+				cacheUpdater.ApplyNode(ctx, info.NodeID, info.Labels())
+			},
+			func(nodeID string) {
+				cacheUpdater.DeleteNode(ctx, nodeID)
+			},
+		)
+
+		xdsCallbacks := xds.NewCallbacks(
+			ctrl.Log.WithName("xds.server.callbacks"),
+			connectedClients,
+			nackTracker,
+		)
+		xdsServerOptions, err := xdsTLSOptions(cfg, fWatcher, xdsCallbacks)
+		if err != nil {
+			setupServers.Error(err, "unable to configure xDS server TLS")
+			os.Exit(1)
+		}
 
 		go func() {
-			srv := server.NewServer(ctx, snapshotCache, xds.NewCallbacks(
-				ctrl.Log.WithName("xds.server.callbacks"),
-				connectedClients),
-			)
-			if err = xds.RunServer(srv, cfg.XDS.Port); err != nil {
+			srv := server.NewServer(ctx, snapshotCache, xdsCallbacks)
+			if err = xds.RunServer(srv, cfg.XDS.Port, xdsServerOptions...); err != nil {
 				setupServers.Error(err, "cannot run xDS server")
 				os.Exit(1)
 			}
 		}()
 
+		// The rate limit service is called by the same proxies, it is served with the TLS settings of xDS
+		if cfg.RateLimit.Enabled {
+			go func() {
+				svc := ratelimit.NewService(resStore)
+				if err := ratelimit.RunServer(svc, cfg.RateLimit.Port, xdsServerOptions...); err != nil {
+					setupServers.Error(err, "cannot run rate limit server")
+					os.Exit(1)
+				}
+			}()
+		}
+
 		if enableAPI {
 			go func() {
 				apiServerCfg := &api.Config{}
@@ -521,7 +710,7 @@ func main() {
 					setupServers.Error(err, "failed to parse static resources config")
 					os.Exit(1)
 				}
-				apiServer, err := api.New(snapshotCache, apiServerCfg, zapLogger, devMode, fWatcher, configPath)
+				apiServer, err := api.New(snapshotCache, connectedClients, apiServerCfg, zapLogger, devMode, fWatcher, configPath)
 				if err != nil {
 					setupServers.Error(err, "failed to create api server")
 					os.Exit(1)
@@ -587,7 +776,7 @@ func main() {
 					_, _ = w.Write(data)
 				})
 				http.HandleFunc("/debug/connected-clients", func(w http.ResponseWriter, _ *http.Request) {
-					data, err := json.MarshalIndent(connectedClients.List(), "", "\t")
+					data, err := json.MarshalIndent(connectedClients.List(snapshotCache.GetVersion), "", "\t")
 					if err != nil {
 						dLog.Error(err, "failed to marshal connected clients")
 						w.WriteHeader(http.StatusInternalServerError)
@@ -595,6 +784,15 @@ func main() {
 					}
 					_, _ = w.Write(data)
 				})
+				http.HandleFunc("/debug/nacks", func(w http.ResponseWriter, _ *http.Request) {
+					data, err := json.MarshalIndent(nackTracker.List(), "", "\t")
+					if err != nil {
+						dLog.Error(err, "failed to marshal rejections")
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					_, _ = w.Write(data)
+				})
 				http.HandleFunc("/debug/buildinfo", func(w http.ResponseWriter, _ *http.Request) {
 					data, err := json.MarshalIndent(buildinfo.GetInfo(), "", "\t")
 					if err != nil {
@@ -611,11 +809,27 @@ func main() {
 		return nil
 	}
 
-	if err = mgr.Add(startServers); err != nil {
+	// Servers run on every replica, not only on the leader
+	if err = mgr.Add(followerRunnable{startServers}); err != nil {
 		setupLog.Error(err, "unable to add startServers to manager")
 		os.Exit(1)
 	}
 
+	// Followers do not write statuses, a newly elected leader writes the current ones
+	var writeStatuses manager.RunnableFunc = func(ctx context.Context) error {
+		select {
+		case <-cacheReadyCh:
+		case <-ctx.Done():
+			return nil
+		}
+		enqueueVirtualServices(cacheUpdater.ListVirtualServices())
+		return nil
+	}
+	if err = mgr.Add(writeStatuses); err != nil {
+		setupLog.Error(err, "unable to add writeStatuses to manager")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -636,3 +850,50 @@ func managerCacheOptions(cfg *Config) *mgrCache.Options {
 	mgrCacheOpts.DefaultNamespaces[cfg.InstallationNamespace] = mgrCache.Config{}
 	return mgrCacheOpts
 }
+
+// xdsTLSOptions returns gRPC options serving xDS over TLS with hot-reloaded certificates.
+// With client verification enabled, streams are authenticated by their client certificates
+// and, if a policy is configured, authorised for the node IDs they request. The built-in rate
+// limit service is served with the same options, so it requires client certificates as well.
+func xdsTLSOptions(cfg Config, fw *filewatcher.FileWatcher, callbacks *xds.Callbacks) ([]grpc.ServerOption, error) {
+	tlsCfg := cfg.XDS.TLS
+	if !tlsCfg.Enabled {
+		if cfg.XDS.AuthPolicyFile != "" {
+			return nil, errors.New("xDS authorization policy requires TLS with client verification")
+		}
+		return nil, nil
+	}
+	if cfg.XDS.AuthPolicyFile != "" && tlsCfg.ClientCAFile == "" {
+		return nil, errors.New("xDS authorization policy requires a client CA")
+	}
+
+	reloader, err := xdsauth.NewCertificateReloader(xdsauth.TLSConfig{
+		CertFile:     tlsCfg.CertFile,
+		KeyFile:      tlsCfg.KeyFile,
+		ClientCAFile: tlsCfg.ClientCAFile,
+	}, fw, ctrl.Log.WithName("xds.tls"))
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsCfg.ClientCAFile != "" {
+		authorizer := xdsauth.NewAuthorizer(ctrl.Log.WithName("xds.auth"))
+		if cfg.XDS.AuthPolicyFile != "" {
+			if err := authorizer.WatchPolicy(cfg.XDS.AuthPolicyFile, fw); err != nil {
+				return nil, err
+			}
+		}
+		callbacks.SetAuthorizer(authorizer)
+	}
+
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(reloader.TLSConfig()))}, nil
+}
+
+// followerRunnable runs on every replica regardless of leader election
+type followerRunnable struct {
+	manager.RunnableFunc
+}
+
+func (followerRunnable) NeedLeaderElection() bool {
+	return false
+}
@@ -91,6 +91,12 @@ Create the name of the service account to use
  {{- $modelVolumeMount := dict "name" "auth" "mountPath" "/etc/exc/access-control" -}}
  {{- $mounts = append $mounts $modelVolumeMount -}}
  {{- end -}}
+ {{- if .Values.xds.tls.enabled -}}
+ {{- $mounts = append $mounts (dict "name" "xds-tls" "mountPath" "/etc/xds-tls" "readOnly" true) -}}
+ {{- if and .Values.xds.tls.verifyClients .Values.xds.authorizationRules -}}
+ {{- $mounts = append $mounts (dict "name" "xds-authorization" "mountPath" "/etc/xds-authorization" "readOnly" true) -}}
+ {{- end -}}
+ {{- end -}}
  {{- if .Values.config -}}
  {{- $configVolumeMount := dict "name" "config" "mountPath" "/etc/exc" -}}
  {{- $mounts = append $mounts $configVolumeMount -}}
@@ -120,6 +126,17 @@ Create the name of the service account to use
  {{- $modelVolume := dict "name" "auth" "configMap" (dict "name" "access-control-model" ) -}}
  {{- $volumes = append $volumes $modelVolume -}}
  {{- end -}}
+ {{- if .Values.xds.tls.enabled -}}
+ {{- $xdsTLSVolume := dict "name" "xds-tls"
+     "secret" (dict
+         "secretName" (.Values.xds.tls.secretName | required "xds.tls.secretName is required")
+         "defaultMode" 420
+     ) -}}
+ {{- $volumes = append $volumes $xdsTLSVolume -}}
+ {{- if and .Values.xds.tls.verifyClients .Values.xds.authorizationRules -}}
+ {{- $volumes = append $volumes (dict "name" "xds-authorization" "configMap" (dict "name" "xds-authorization-policy")) -}}
+ {{- end -}}
+ {{- end -}}
  {{- if .Values.config -}}
  {{- $configVolume := dict "name" "config" "configMap" (dict "name" "config" ) -}}
  {{- $volumes = append $volumes $configVolume -}}
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -35,6 +36,8 @@ type AccessLogConfigReconciler struct {
 	Scheme         *runtime.Scheme
 	Updater        *updater.CacheUpdater
 	CacheReadyChan chan struct{}
+	// StatusWriter writes the status of the AccessLogConfig. Nil disables it.
+	StatusWriter *StatusWriter
 }
 
 // +kubebuilder:rbac:groups=envoy.kaasops.io,resources=accesslogconfigs,verbs=get;list;watch;create;update;patch;delete
@@ -66,6 +69,11 @@ func (r *AccessLogConfigReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	r.Updater.ApplyAccessLogConfig(ctx, &accessLogConfig)
 
+	_, validationErr := accessLogConfig.UnmarshalAndValidateV3()
+	if err := r.StatusWriter.Update(ctx, &accessLogConfig, validationErr); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	rlog.Info("Finished Reconciling AccessLogConfig")
 
 	return ctrl.Result{}, nil
@@ -73,8 +81,11 @@ func (r *AccessLogConfigReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *AccessLogConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&envoyv1alpha1.AccessLogConfig{}).
-		Named("accesslogconfig").
-		Complete(r)
+		Named("accesslogconfig")
+	if r.StatusWriter != nil {
+		b = b.WatchesRawSource(r.StatusWriter.Source(store.DependencyAccessLogConfig))
+	}
+	return b.Complete(r)
 }
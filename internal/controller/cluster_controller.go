@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -35,6 +36,8 @@ type ClusterReconciler struct {
 	Scheme         *runtime.Scheme
 	Updater        *updater.CacheUpdater
 	CacheReadyChan chan struct{}
+	// StatusWriter writes the status of the Cluster. Nil disables it.
+	StatusWriter *StatusWriter
 }
 
 // +kubebuilder:rbac:groups=envoy.kaasops.io,resources=clusters,verbs=get;list;watch;create;update;patch;delete
@@ -67,6 +70,11 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	r.Updater.ApplyCluster(ctx, &cluster)
 
+	_, validationErr := cluster.UnmarshalV3AndValidate()
+	if err := r.StatusWriter.Update(ctx, &cluster, validationErr); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	rlog.Info("Finished Reconciling Cluster")
 
 	return ctrl.Result{}, nil
@@ -74,8 +82,11 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&envoyv1alpha1.Cluster{}).
-		Named("cluster").
-		Complete(r)
+		Named("cluster")
+	if r.StatusWriter != nil {
+		b = b.WatchesRawSource(r.StatusWriter.Source(store.DependencyCluster))
+	}
+	return b.Complete(r)
 }
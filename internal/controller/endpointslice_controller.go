@@ -0,0 +1,75 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// EndpointSliceReconciler reconciles Kubernetes EndpointSlices of Services referenced by EDS clusters
+type EndpointSliceReconciler struct {
+	client.Client
+	Scheme         *runtime.Scheme
+	Updater        *updater.CacheUpdater
+	CacheReadyChan chan struct{}
+}
+
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+
+// Reconcile keeps EndpointSlices in the store. Load assignments of EDS clusters
+// referencing their Service are rebuilt from them.
+func (r *EndpointSliceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	<-r.CacheReadyChan
+	rlog := log.FromContext(ctx).WithName("endpointslice-reconciler").WithValues("endpointslice", req.NamespacedName)
+	rlog.V(1).Info("Reconciling EndpointSlice")
+
+	var slice discoveryv1.EndpointSlice
+	if err := r.Get(ctx, req.NamespacedName, &slice); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		r.Updater.DeleteEndpointSlice(ctx, req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	r.Updater.ApplyEndpointSlice(ctx, &slice)
+
+	rlog.V(1).Info("Finished Reconciling EndpointSlice")
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *EndpointSliceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&discoveryv1.EndpointSlice{}).
+		Named("kubernetes-endpointslice").
+		WithEventFilter(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			_, ok := obj.GetLabels()[discoveryv1.LabelServiceName]
+			return ok
+		})).
+		Complete(r)
+}
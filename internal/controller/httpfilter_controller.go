@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -35,6 +36,8 @@ type HttpFilterReconciler struct {
 	Scheme         *runtime.Scheme
 	Updater        *updater.CacheUpdater
 	CacheReadyChan chan struct{}
+	// StatusWriter writes the status of the HttpFilter. Nil disables it.
+	StatusWriter *StatusWriter
 }
 
 // +kubebuilder:rbac:groups=envoy.kaasops.io,resources=httpfilters,verbs=get;list;watch;create;update;patch;delete
@@ -66,6 +69,11 @@ func (r *HttpFilterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	r.Updater.ApplyHTTPFilter(ctx, &httpFilter)
 
+	_, validationErr := httpFilter.UnmarshalV3AndValidate()
+	if err := r.StatusWriter.Update(ctx, &httpFilter, validationErr); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	rlog.Info("Finished Reconciling HttpFilter")
 
 	return ctrl.Result{}, nil
@@ -73,8 +81,11 @@ func (r *HttpFilterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *HttpFilterReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&envoyv1alpha1.HttpFilter{}).
-		Named("httpfilter").
-		Complete(r)
+		Named("httpfilter")
+	if r.StatusWriter != nil {
+		b = b.WatchesRawSource(r.StatusWriter.Source(store.DependencyHTTPFilter))
+	}
+	return b.Complete(r)
 }
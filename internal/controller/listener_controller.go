@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -35,6 +36,8 @@ type ListenerReconciler struct {
 	Scheme         *runtime.Scheme
 	Updater        *updater.CacheUpdater
 	CacheReadyChan chan struct{}
+	// StatusWriter writes the status of the Listener. Nil disables it.
+	StatusWriter *StatusWriter
 }
 
 // +kubebuilder:rbac:groups=envoy.kaasops.io,resources=listeners,verbs=get;list;watch;create;update;patch;delete
@@ -67,6 +70,11 @@ func (r *ListenerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	r.Updater.ApplyListener(ctx, &listener)
 
+	_, validationErr := listener.UnmarshalV3AndValidate()
+	if err := r.StatusWriter.Update(ctx, &listener, validationErr); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	rlog.Info("Finished Reconciling Listener")
 
 	return ctrl.Result{}, nil
@@ -74,8 +82,11 @@ func (r *ListenerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ListenerReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&envoyv1alpha1.Listener{}).
-		Named("listener").
-		Complete(r)
+		Named("listener")
+	if r.StatusWriter != nil {
+		b = b.WatchesRawSource(r.StatusWriter.Source(store.DependencyListener))
+	}
+	return b.Complete(r)
 }
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -35,6 +36,8 @@ type PolicyReconciler struct {
 	Scheme         *runtime.Scheme
 	Updater        *updater.CacheUpdater
 	CacheReadyChan chan struct{}
+	// StatusWriter writes the status of the Policy. Nil disables it.
+	StatusWriter *StatusWriter
 }
 
 // +kubebuilder:rbac:groups=envoy.kaasops.io,resources=policies,verbs=get;list;watch;create;update;patch;delete
@@ -65,6 +68,11 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	}
 	r.Updater.ApplyPolicy(ctx, &policy)
 
+	_, validationErr := policy.UnmarshalV3AndValidate()
+	if err := r.StatusWriter.Update(ctx, &policy, validationErr); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	rlog.Info("Finished Reconciling Policy")
 
 	return ctrl.Result{}, nil
@@ -72,8 +80,11 @@ func (r *PolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *PolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&envoyv1alpha1.Policy{}).
-		Named("policy").
-		Complete(r)
+		Named("policy")
+	if r.StatusWriter != nil {
+		b = b.WatchesRawSource(r.StatusWriter.Source(store.DependencyPolicy))
+	}
+	return b.Complete(r)
 }
@@ -0,0 +1,80 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+)
+
+// RateLimitPolicyReconciler reconciles a RateLimitPolicy object
+type RateLimitPolicyReconciler struct {
+	client.Client
+	Scheme         *runtime.Scheme
+	Updater        *updater.CacheUpdater
+	CacheReadyChan chan struct{}
+	// StatusWriter writes the status of the RateLimitPolicy. Nil disables it.
+	StatusWriter *StatusWriter
+}
+
+// +kubebuilder:rbac:groups=envoy.kaasops.io,resources=ratelimitpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=envoy.kaasops.io,resources=ratelimitpolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=envoy.kaasops.io,resources=ratelimitpolicies/finalizers,verbs=update
+
+// Reconcile applies the RateLimitPolicy to the VirtualService it targets. Its limits are also
+// read from the store by the rate limit service of the controller, if enabled.
+func (r *RateLimitPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	<-r.CacheReadyChan
+
+	rlog := log.FromContext(ctx).WithName("ratelimitpolicy-reconciler").
+		WithValues("ratelimitpolicy", req.NamespacedName)
+	rlog.Info("Reconciling RateLimitPolicy")
+
+	var policy envoyv1alpha1.RateLimitPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		r.Updater.DeleteRateLimitPolicy(ctx, req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	r.Updater.ApplyRateLimitPolicy(ctx, &policy)
+
+	if err := r.StatusWriter.Update(ctx, &policy, policy.Validate()); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	rlog.Info("Finished Reconciling RateLimitPolicy")
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RateLimitPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&envoyv1alpha1.RateLimitPolicy{}).
+		Named("ratelimitpolicy").
+		Complete(r)
+}
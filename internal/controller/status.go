@@ -0,0 +1,303 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
+)
+
+// resourceStatusObject is an object with a ResourceStatus
+type resourceStatusObject interface {
+	client.Object
+	GetResourceStatus() *envoyv1alpha1.ResourceStatus
+}
+
+// referenceKinds are the kinds of objects VirtualServices are built from, with their constructors
+var referenceKinds = map[store.DependencyKind]func() resourceStatusObject{
+	store.DependencyListener:        func() resourceStatusObject { return &envoyv1alpha1.Listener{} },
+	store.DependencyRoute:           func() resourceStatusObject { return &envoyv1alpha1.Route{} },
+	store.DependencyHTTPFilter:      func() resourceStatusObject { return &envoyv1alpha1.HttpFilter{} },
+	store.DependencyCluster:         func() resourceStatusObject { return &envoyv1alpha1.Cluster{} },
+	store.DependencyTemplate:        func() resourceStatusObject { return &envoyv1alpha1.VirtualServiceTemplate{} },
+	store.DependencyTracing:         func() resourceStatusObject { return &envoyv1alpha1.Tracing{} },
+	store.DependencyAccessLogConfig: func() resourceStatusObject { return &envoyv1alpha1.AccessLogConfig{} },
+	store.DependencyPolicy:          func() resourceStatusObject { return &envoyv1alpha1.Policy{} },
+}
+
+// referenceKind returns the kind of the object if VirtualServices may be built from it
+func referenceKind(obj resourceStatusObject) (store.DependencyKind, bool) {
+	switch obj.(type) {
+	case *envoyv1alpha1.Listener:
+		return store.DependencyListener, true
+	case *envoyv1alpha1.Route:
+		return store.DependencyRoute, true
+	case *envoyv1alpha1.HttpFilter:
+		return store.DependencyHTTPFilter, true
+	case *envoyv1alpha1.Cluster:
+		return store.DependencyCluster, true
+	case *envoyv1alpha1.VirtualServiceTemplate:
+		return store.DependencyTemplate, true
+	case *envoyv1alpha1.Tracing:
+		return store.DependencyTracing, true
+	case *envoyv1alpha1.AccessLogConfig:
+		return store.DependencyAccessLogConfig, true
+	case *envoyv1alpha1.Policy:
+		return store.DependencyPolicy, true
+	default:
+		return "", false
+	}
+}
+
+// StatusWriter writes statuses of resources other than VirtualServices. Objects VirtualServices
+// are built from list them in their statuses, and are reconciled again when they change.
+type StatusWriter struct {
+	client   client.Client
+	updater  *updater.CacheUpdater
+	elected  <-chan struct{}
+	channels map[store.DependencyKind]chan event.GenericEvent
+}
+
+// NewStatusWriter creates a status writer. Statuses are only written once elected is closed,
+// nil means always.
+func NewStatusWriter(cl client.Client, u *updater.CacheUpdater, elected <-chan struct{}) *StatusWriter {
+	w := &StatusWriter{
+		client:   cl,
+		updater:  u,
+		elected:  elected,
+		channels: make(map[store.DependencyKind]chan event.GenericEvent, len(referenceKinds)),
+	}
+	for kind := range referenceKinds {
+		w.channels[kind] = make(chan event.GenericEvent)
+	}
+	return w
+}
+
+// Source returns the source of objects of the kind to reconcile when VirtualServices built
+// from them change. Every kind returned by referenceKind must be watched by its controller.
+func (w *StatusWriter) Source(kind store.DependencyKind) source.Source {
+	return source.Channel(w.channels[kind], &handler.EnqueueRequestForObject{})
+}
+
+// Enqueue triggers reconciliation of the objects. It does not block.
+func (w *StatusWriter) Enqueue(refs []store.Dependency) {
+	if len(refs) == 0 {
+		return
+	}
+	go func() {
+		for _, ref := range refs {
+			ch, ok := w.channels[ref.Kind]
+			if !ok {
+				continue
+			}
+			obj := referenceKinds[ref.Kind]()
+			obj.SetNamespace(ref.Name.Namespace)
+			obj.SetName(ref.Name.Name)
+			ch <- event.GenericEvent{Object: obj}
+		}
+	}()
+}
+
+// Update sets the conditions of the object from the error of its validation and, for objects
+// VirtualServices are built from, from these VirtualServices. The status is only written by
+// the leader and if it changed. The object is not modified, as it may be held by the store.
+func (w *StatusWriter) Update(ctx context.Context, obj resourceStatusObject, validationErr error) error {
+	return w.UpdateWith(ctx, obj, validationErr, nil)
+}
+
+// UpdateWith is Update also calling mutate, if not nil, with the copy of the object whose
+// status is written, to set the fields of the status specific to its kind.
+func (w *StatusWriter) UpdateWith(
+	ctx context.Context,
+	obj resourceStatusObject,
+	validationErr error,
+	mutate func(resourceStatusObject),
+) error {
+	if w == nil {
+		return nil
+	}
+	prevObj := obj
+	obj = obj.DeepCopyObject().(resourceStatusObject)
+	if mutate != nil {
+		mutate(obj)
+	}
+	status := obj.GetResourceStatus()
+	generation := obj.GetGeneration()
+	status.ObservedGeneration = generation
+
+	kind, isReference := referenceKind(obj)
+	var vsNNs []helpers.NamespacedName
+	if isReference {
+		vsNNs = w.updater.GetReferencingVirtualServices(kind, helpers.NamespacedName{
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+		})
+		if kind == store.DependencyTemplate && validationErr == nil {
+			validationErr = w.templateError(vsNNs)
+		}
+	}
+
+	valid := validCondition(generation, validationErr)
+	meta.SetStatusCondition(&status.Conditions, valid)
+	var synced *metav1.Condition
+	if isReference {
+		status.VirtualServices = resourceRefs(vsNNs)
+		meta.SetStatusCondition(&status.Conditions, inUseCondition(generation, vsNNs))
+		synced = w.syncedCondition(generation, vsNNs)
+		meta.SetStatusCondition(&status.Conditions, *synced)
+	}
+	meta.SetStatusCondition(&status.Conditions, readyCondition(generation, valid, synced))
+
+	if equality.Semantic.DeepEqual(prevObj, obj) || !w.isLeader() {
+		return nil
+	}
+	return w.client.Status().Update(ctx, obj)
+}
+
+func (w *StatusWriter) isLeader() bool {
+	if w.elected == nil {
+		return true
+	}
+	select {
+	case <-w.elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// templateError returns the error of the first VirtualService failing to apply the template
+func (w *StatusWriter) templateError(vsNNs []helpers.NamespacedName) error {
+	for _, vsNN := range vsNNs {
+		vs := w.updater.GetVirtualServiceWithStatus(vsNN)
+		if vs == nil || !vs.Status.Invalid {
+			continue
+		}
+		if w.updater.GetVirtualServiceInvalidReason(vsNN) == updater.InvalidReasonTemplateFailed {
+			return fmt.Errorf("VirtualService %s: %s", vsNN.String(), vs.Status.Message)
+		}
+	}
+	return nil
+}
+
+// syncedCondition reflects whether all proxies serving VirtualServices built from the object
+// acknowledged their current configurations.
+func (w *StatusWriter) syncedCondition(generation int64, vsNNs []helpers.NamespacedName) *metav1.Condition {
+	condition := &metav1.Condition{
+		Type:               envoyv1alpha1.ConditionSynced,
+		Status:             metav1.ConditionUnknown,
+		ObservedGeneration: generation,
+		Reason:             envoyv1alpha1.ReasonNotReferenced,
+		Message:            "no VirtualService is built from the object",
+	}
+	if len(vsNNs) == 0 {
+		return condition
+	}
+	var served, synced int
+	for _, vsNN := range vsNNs {
+		syncStatus := w.updater.GetSyncStatus(vsNN)
+		if _, total := syncStatus.Proxies(); total == 0 {
+			continue
+		}
+		served++
+		if syncStatus.Synced() {
+			synced++
+		}
+	}
+	switch {
+	case served == 0:
+		condition.Reason = envoyv1alpha1.ReasonNoProxies
+		condition.Message = "no connected proxies serve VirtualServices built from the object"
+	case synced < served:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = envoyv1alpha1.ReasonPending
+		condition.Message = fmt.Sprintf("%d/%d VirtualServices acknowledged by all their proxies", synced, served)
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = envoyv1alpha1.ReasonAcknowledged
+		condition.Message = fmt.Sprintf("%d/%d VirtualServices acknowledged by all their proxies", synced, served)
+	}
+	return condition
+}
+
+func validCondition(generation int64, validationErr error) metav1.Condition {
+	if validationErr != nil {
+		return metav1.Condition{
+			Type:               envoyv1alpha1.ConditionValid,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             envoyv1alpha1.ReasonInvalid,
+			Message:            validationErr.Error(),
+		}
+	}
+	return metav1.Condition{
+		Type:               envoyv1alpha1.ConditionValid,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             envoyv1alpha1.ReasonValidated,
+	}
+}
+
+func inUseCondition(generation int64, vsNNs []helpers.NamespacedName) metav1.Condition {
+	if len(vsNNs) == 0 {
+		return metav1.Condition{
+			Type:               envoyv1alpha1.ConditionInUse,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             envoyv1alpha1.ReasonNotReferenced,
+			Message:            "no VirtualService is built from the object",
+		}
+	}
+	return metav1.Condition{
+		Type:               envoyv1alpha1.ConditionInUse,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             envoyv1alpha1.ReasonReferenced,
+		Message:            fmt.Sprintf("%d VirtualServices are built from the object", len(vsNNs)),
+	}
+}
+
+// readyCondition is True when the object is valid and not pending on proxies. A nil synced
+// condition is not taken into account.
+func readyCondition(generation int64, valid metav1.Condition, synced *metav1.Condition) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               envoyv1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             envoyv1alpha1.ReasonReady,
+	}
+	switch {
+	case valid.Status != metav1.ConditionTrue:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = valid.Reason
+		condition.Message = valid.Message
+	case synced != nil && synced.Status == metav1.ConditionFalse:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = synced.Reason
+		condition.Message = synced.Message
+	}
+	return condition
+}
+
+func resourceRefs(nns []helpers.NamespacedName) []envoyv1alpha1.ResourceRef {
+	if len(nns) == 0 {
+		return nil
+	}
+	refs := make([]envoyv1alpha1.ResourceRef, 0, len(nns))
+	for _, nn := range nns {
+		namespace := nn.Namespace
+		refs = append(refs, envoyv1alpha1.ResourceRef{Name: nn.Name, Namespace: &namespace})
+	}
+	return refs
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -33,6 +34,8 @@ type TracingReconciler struct {
 	Scheme         *runtime.Scheme
 	Updater        *updater.CacheUpdater
 	CacheReadyChan chan struct{}
+	// StatusWriter writes the status of the Tracing. Nil disables it.
+	StatusWriter *StatusWriter
 }
 
 // +kubebuilder:rbac:groups=envoy.kaasops.io,resources=tracings,verbs=get;list;watch;create;update;patch;delete
@@ -65,6 +68,11 @@ func (r *TracingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	r.Updater.ApplyTracing(ctx, &tracing)
 
+	_, validationErr := tracing.UnmarshalV3AndValidate()
+	if err := r.StatusWriter.Update(ctx, &tracing, validationErr); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	rlog.Info("Finished Reconciling Tracing")
 
 	return ctrl.Result{}, nil
@@ -72,8 +80,11 @@ func (r *TracingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *TracingReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&envoyv1alpha1.Tracing{}).
-		Named("tracing").
-		Complete(r)
+		Named("tracing")
+	if r.StatusWriter != nil {
+		b = b.WatchesRawSource(r.StatusWriter.Source(store.DependencyTracing))
+	}
+	return b.Complete(r)
 }
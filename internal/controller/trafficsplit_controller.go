@@ -0,0 +1,102 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+)
+
+// TrafficSplitReconciler reconciles a TrafficSplit object
+type TrafficSplitReconciler struct {
+	client.Client
+	Scheme         *runtime.Scheme
+	Updater        *updater.CacheUpdater
+	CacheReadyChan chan struct{}
+	// StatusWriter writes the status of the TrafficSplit, which holds its current step.
+	// Nil disables it, and the TrafficSplit does not progress.
+	StatusWriter *StatusWriter
+	// ReconcileChan triggers reconciliation of TrafficSplits whose error changed after a rebuild,
+	// as reported by the updater. Nil disables it.
+	ReconcileChan chan event.GenericEvent
+}
+
+// +kubebuilder:rbac:groups=envoy.kaasops.io,resources=trafficsplits,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=envoy.kaasops.io,resources=trafficsplits/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=envoy.kaasops.io,resources=trafficsplits/finalizers,verbs=update
+
+// Reconcile applies the current step of the TrafficSplit, as stored in its status, and moves
+// the status to the next step once the current one is over. Routes are only rendered from the
+// written status, so that all replicas serve the same weights.
+func (r *TrafficSplitReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	<-r.CacheReadyChan
+
+	rlog := log.FromContext(ctx).WithName("trafficsplit-reconciler").WithValues("trafficsplit", req.NamespacedName)
+	rlog.Info("Reconciling TrafficSplit")
+
+	var ts envoyv1alpha1.TrafficSplit
+	if err := r.Get(ctx, req.NamespacedName, &ts); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		r.Updater.DeleteTrafficSplit(ctx, req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	r.Updater.ApplyTrafficSplit(ctx, &ts)
+
+	var requeueAfter time.Duration
+	now := time.Now()
+	progress := func(obj resourceStatusObject) {
+		requeueAfter = obj.(*envoyv1alpha1.TrafficSplit).Progress(now)
+	}
+	// A TrafficSplit the last rebuild could not apply, like one splitting a missing route, is invalid
+	validationErr := ts.Validate()
+	if validationErr == nil {
+		validationErr = r.Updater.GetTrafficSplitError(helpers.NamespacedName{Namespace: ts.Namespace, Name: ts.Name})
+	}
+	if err := r.StatusWriter.UpdateWith(ctx, &ts, validationErr, progress); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	rlog.Info("Finished Reconciling TrafficSplit", "requeueAfter", requeueAfter)
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TrafficSplitReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&envoyv1alpha1.TrafficSplit{}).
+		Named("trafficsplit")
+	if r.ReconcileChan != nil {
+		b = b.WatchesRawSource(source.Channel(r.ReconcileChan, &handler.EnqueueRequestForObject{}))
+	}
+	return b.Complete(r)
+}
@@ -18,12 +18,20 @@ package controller
 
 import (
 	"context"
-
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/nack"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -41,11 +49,18 @@ type VirtualServiceReconciler struct {
 	Updater         *updater.CacheUpdater
 	CacheReadyChan  chan struct{}
 	VSReconcileChan chan event.GenericEvent
+	NackTracker     *nack.Tracker
+	// Recorder records events of VirtualServices becoming invalid or recovering. Nil disables them.
+	Recorder record.EventRecorder
+	// Elected is closed once this replica is the leader. Every replica applies VirtualServices
+	// to its cache to serve xDS, but only the leader writes their statuses. Nil means always leader.
+	Elected <-chan struct{}
 }
 
 // +kubebuilder:rbac:groups=envoy.kaasops.io,resources=virtualservices,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=envoy.kaasops.io,resources=virtualservices/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=envoy.kaasops.io,resources=virtualservices/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -76,12 +91,28 @@ func (r *VirtualServiceReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	// Get status from store (stored separately for immutability)
 	nn := helpers.NamespacedName{Namespace: req.Namespace, Name: req.Name}
 	vsWithStatus := r.Updater.GetVirtualServiceWithStatus(nn)
-	if vsWithStatus != nil {
-		vs.Status = vsWithStatus.Status
+	// The rebuild is debounced, the stored status may still be built from an older generation.
+	// Its status change re-enqueues the VirtualService once the current generation is built.
+	if vsWithStatus == nil || vsWithStatus.Status.ObservedGeneration == 0 {
+		rlog.V(1).Info("VirtualService not built yet, keeping its status")
+		return ctrl.Result{}, nil
+	}
+	vs.Status = vsWithStatus.Status
+	// Conditions are owned by the reconciler, the stored copy may be stale
+	vs.Status.Conditions = prevStatus.Conditions
+	conditionsChanged := r.setRejectedCondition(&vs, nn)
+	conditionsChanged = r.setSyncedCondition(&vs, nn) || conditionsChanged
+	conditionsChanged = setValidAndReadyConditions(&vs) || conditionsChanged
+
+	if !r.isLeader() {
+		rlog.V(1).Info("Skipping status update on follower")
+		return ctrl.Result{}, nil
 	}
 
 	if prevStatus.Invalid != vs.Status.Invalid ||
-		prevStatus.Message != vs.Status.Message {
+		prevStatus.Message != vs.Status.Message ||
+		prevStatus.ObservedGeneration != vs.Status.ObservedGeneration ||
+		conditionsChanged {
 		if vs.Status.Message != "" {
 			if vs.Status.Invalid {
 				rlog.Info("[WARN] VirtualService is invalid", "message", vs.Status.Message)
@@ -92,6 +123,7 @@ func (r *VirtualServiceReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		if err := r.Status().Update(ctx, &vs); err != nil {
 			return ctrl.Result{}, err
 		}
+		r.recordStatusEvents(ctx, &vs, prevStatus)
 	}
 
 	rlog.Info("Finished Reconciling VirtualService")
@@ -99,6 +131,102 @@ func (r *VirtualServiceReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	return ctrl.Result{}, nil
 }
 
+func (r *VirtualServiceReconciler) isLeader() bool {
+	if r.Elected == nil {
+		return true
+	}
+	select {
+	case <-r.Elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// setRejectedCondition reflects Envoy NACKs of resources built from the VirtualService
+// in the Rejected condition. It returns true if the conditions were changed.
+func (r *VirtualServiceReconciler) setRejectedCondition(vs *envoyv1alpha1.VirtualService, nn helpers.NamespacedName) bool {
+	if r.NackTracker == nil {
+		return false
+	}
+
+	rejections := r.NackTracker.GetRejectionsForVirtualService(nn)
+	if len(rejections) == 0 {
+		if meta.FindStatusCondition(vs.Status.Conditions, envoyv1alpha1.ConditionRejected) == nil {
+			return false
+		}
+		return meta.SetStatusCondition(&vs.Status.Conditions, metav1.Condition{
+			Type:               envoyv1alpha1.ConditionRejected,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: vs.Status.ObservedGeneration,
+			Reason:             envoyv1alpha1.ReasonAccepted,
+			Message:            "configuration accepted by Envoy",
+		})
+	}
+
+	messages := make([]string, 0, len(rejections))
+	for _, rejection := range rejections {
+		messages = append(messages, fmt.Sprintf("node %s rejected %s version %s at %s: %s",
+			rejection.NodeID,
+			rejection.TypeURL,
+			rejection.Version,
+			rejection.Timestamp.UTC().Format(time.RFC3339),
+			rejection.Message,
+		))
+	}
+	return meta.SetStatusCondition(&vs.Status.Conditions, metav1.Condition{
+		Type:               envoyv1alpha1.ConditionRejected,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: vs.Status.ObservedGeneration,
+		Reason:             envoyv1alpha1.ReasonEnvoyNACK,
+		Message:            strings.Join(messages, "; "),
+	})
+}
+
+// setSyncedCondition reflects how many connected proxies acknowledged a snapshot containing
+// the current configuration of the VirtualService. It returns true if the conditions were changed.
+func (r *VirtualServiceReconciler) setSyncedCondition(vs *envoyv1alpha1.VirtualService, nn helpers.NamespacedName) bool {
+	syncStatus := r.Updater.GetSyncStatus(nn)
+	condition := metav1.Condition{
+		Type:               envoyv1alpha1.ConditionSynced,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: vs.Status.ObservedGeneration,
+		Reason:             envoyv1alpha1.ReasonPending,
+		Message:            syncStatus.Message(),
+	}
+	if _, total := syncStatus.Proxies(); total == 0 {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = envoyv1alpha1.ReasonNoProxies
+	} else if syncStatus.Synced() {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = envoyv1alpha1.ReasonAcknowledged
+	}
+	return meta.SetStatusCondition(&vs.Status.Conditions, condition)
+}
+
+// setValidAndReadyConditions reflects the build status of the VirtualService in the Valid condition,
+// and in the Ready condition whether it is also accepted and acknowledged by its proxies.
+// Conditions observe the generation the status was built from, which may lag behind the VirtualService.
+// It returns true if the conditions were changed.
+func setValidAndReadyConditions(vs *envoyv1alpha1.VirtualService) bool {
+	generation := vs.Status.ObservedGeneration
+	var validationErr error
+	if vs.Status.Invalid {
+		validationErr = errors.New(vs.Status.Message)
+	}
+	valid := validCondition(generation, validationErr)
+	changed := meta.SetStatusCondition(&vs.Status.Conditions, valid)
+
+	ready := readyCondition(generation, valid, meta.FindStatusCondition(vs.Status.Conditions, envoyv1alpha1.ConditionSynced))
+	rejected := meta.FindStatusCondition(vs.Status.Conditions, envoyv1alpha1.ConditionRejected)
+	if ready.Status == metav1.ConditionTrue && rejected != nil && rejected.Status == metav1.ConditionTrue {
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = rejected.Reason
+		ready.Message = rejected.Message
+	}
+	return meta.SetStatusCondition(&vs.Status.Conditions, ready) || changed
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *VirtualServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
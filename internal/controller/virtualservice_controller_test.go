@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
@@ -111,5 +112,26 @@ var _ = Describe("VirtualService Controller", func() {
 			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
+
+		It("should record an event once when the resource becomes invalid", func() {
+			By("Reconciling the resource referencing a missing listener")
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &VirtualServiceReconciler{
+				Client:         k8sClient,
+				Scheme:         k8sClient.Scheme(),
+				Updater:        cacheUpdater,
+				CacheReadyChan: cacheReadyChan,
+				Recorder:       recorder,
+			}
+
+			for i := 0; i < 2; i++ {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(HavePrefix("Warning " + EventReasonListenerNotFound))
+		})
 	})
 })
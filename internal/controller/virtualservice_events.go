@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
+)
+
+// Reasons of events recorded on VirtualServices and the objects they reference
+const (
+	EventReasonInvalid          = "Invalid"
+	EventReasonTemplateFailed   = "TemplateFailed"
+	EventReasonListenerNotFound = "ListenerNotFound"
+	EventReasonSecretNotFound   = "SecretNotFound"
+	EventReasonClusterNotFound  = "ClusterNotFound"
+	EventReasonRecovered        = "Recovered"
+
+	EventReasonVirtualServiceInvalid   = "VirtualServiceInvalid"
+	EventReasonVirtualServiceRecovered = "VirtualServiceRecovered"
+)
+
+// invalidReason returns the event reason of a VirtualService invalid for the reason
+func invalidReason(reason updater.InvalidReason) string {
+	switch reason {
+	case updater.InvalidReasonTemplateFailed:
+		return EventReasonTemplateFailed
+	case updater.InvalidReasonListenerNotFound:
+		return EventReasonListenerNotFound
+	case updater.InvalidReasonSecretNotFound:
+		return EventReasonSecretNotFound
+	case updater.InvalidReasonClusterNotFound:
+		return EventReasonClusterNotFound
+	default:
+		return EventReasonInvalid
+	}
+}
+
+// recordStatusEvents records events when the VirtualService becomes invalid, fails with another
+// message or recovers, on the VirtualService and on its Template and Listener. Events are only
+// recorded on changes of the status, and the recorder aggregates repeated ones.
+func (r *VirtualServiceReconciler) recordStatusEvents(
+	ctx context.Context,
+	vs *envoyv1alpha1.VirtualService,
+	prevStatus *envoyv1alpha1.VirtualServiceStatus,
+) {
+	if r.Recorder == nil {
+		return
+	}
+	nn := helpers.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+	switch {
+	case vs.Status.Invalid && (!prevStatus.Invalid || prevStatus.Message != vs.Status.Message):
+		reason := invalidReason(r.Updater.GetVirtualServiceInvalidReason(nn))
+		r.Recorder.Event(vs, corev1.EventTypeWarning, reason, vs.Status.Message)
+		for _, obj := range r.referencedObjects(ctx, vs) {
+			r.Recorder.Eventf(obj, corev1.EventTypeWarning, EventReasonVirtualServiceInvalid,
+				"VirtualService %s is invalid: %s", nn.String(), vs.Status.Message)
+		}
+	case !vs.Status.Invalid && prevStatus.Invalid:
+		r.Recorder.Event(vs, corev1.EventTypeNormal, EventReasonRecovered, "VirtualService is valid")
+		for _, obj := range r.referencedObjects(ctx, vs) {
+			r.Recorder.Eventf(obj, corev1.EventTypeNormal, EventReasonVirtualServiceRecovered,
+				"VirtualService %s is valid", nn.String())
+		}
+	}
+}
+
+// referencedObjects returns the existing Template and Listener of the VirtualService.
+// The Listener may be set by the Template.
+func (r *VirtualServiceReconciler) referencedObjects(ctx context.Context, vs *envoyv1alpha1.VirtualService) []client.Object {
+	var objs []client.Object
+	listenerRef := vs.Spec.Listener
+	if vs.Spec.Template != nil {
+		var vst envoyv1alpha1.VirtualServiceTemplate
+		key := types.NamespacedName{
+			Namespace: helpers.GetNamespace(vs.Spec.Template.Namespace, vs.Namespace),
+			Name:      vs.Spec.Template.Name,
+		}
+		if err := r.Get(ctx, key, &vst); err == nil {
+			objs = append(objs, &vst)
+			if listenerRef == nil {
+				listenerRef = vst.Spec.Listener
+			}
+		}
+	}
+	if listenerRef != nil {
+		var listener envoyv1alpha1.Listener
+		key := types.NamespacedName{
+			Namespace: helpers.GetNamespace(listenerRef.Namespace, vs.Namespace),
+			Name:      listenerRef.Name,
+		}
+		if err := r.Get(ctx, key, &listener); err == nil {
+			objs = append(objs, &listener)
+		}
+	}
+	return objs
+}
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
@@ -37,6 +38,8 @@ type VirtualServiceTemplateReconciler struct {
 	Updater         *updater.CacheUpdater
 	CacheReadyChan  chan struct{}
 	VSReconcileChan chan event.GenericEvent
+	// StatusWriter writes the status of the VirtualServiceTemplate. Nil disables it.
+	StatusWriter *StatusWriter
 }
 
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
@@ -77,6 +80,11 @@ func (r *VirtualServiceTemplateReconciler) Reconcile(ctx context.Context, req ct
 		}
 	}
 
+	// Templates are validated by applying them to VirtualServices
+	if err := r.StatusWriter.Update(ctx, &vst, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	rlog.Info("Finished Reconciling VirtualServiceTemplate")
 
 	return ctrl.Result{}, nil
@@ -84,8 +92,11 @@ func (r *VirtualServiceTemplateReconciler) Reconcile(ctx context.Context, req ct
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *VirtualServiceTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&envoyv1alpha1.VirtualServiceTemplate{}).
-		Named("virtualservicetemplate").
-		Complete(r)
+		Named("virtualservicetemplate")
+	if r.StatusWriter != nil {
+		b = b.WatchesRawSource(r.StatusWriter.Source(store.DependencyTemplate))
+	}
+	return b.Complete(r)
 }
@@ -36,9 +36,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -82,7 +84,9 @@ func (r *WebhookReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		}
 	})
 
+	// Resource controllers run on every replica, webhook certificates are written by the leader only
 	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{NeedLeaderElection: ptr.To(true)}).
 		For(&corev1.Secret{}, namesMatchingPredicate(r.TLSSecretName)).
 		Watches(
 			&admissionregistrationv1.ValidatingWebhookConfiguration{},
@@ -179,6 +183,18 @@ func (r *WebhookReconciler) ReconcileCertificates(ctx context.Context, certSecre
 	return nil
 }
 
+// BootstrapCertificates writes certificates only if the secret has no valid ones. With leader
+// election, replicas use it on startup to serve webhooks before a leader is elected, afterwards
+// certificates are maintained by the leader only.
+func (r *WebhookReconciler) BootstrapCertificates(ctx context.Context, certSecret *corev1.Secret) error {
+	current := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(certSecret), current); err == nil &&
+		!r.shouldUpdateCertificate(current) {
+		return nil
+	}
+	return r.ReconcileCertificates(ctx, certSecret)
+}
+
 // shouldUpdateCertificate checks whether it is necessary to update or create a certificate
 func (r *WebhookReconciler) shouldUpdateCertificate(secret *corev1.Secret) bool {
 	if _, ok := secret.Data[corev1.ServiceAccountRootCAKey]; !ok {
@@ -179,7 +179,8 @@ func lookupAction(route string) string {
 		return ActionListAccessLogConfigs
 	case virtual_service_templatev1connect.VirtualServiceTemplateStoreServiceListVirtualServiceTemplatesProcedure:
 		return ActionListVirtualServiceTemplates
-	case nodev1connect.NodeStoreServiceListNodesProcedure:
+	case nodev1connect.NodeStoreServiceListNodesProcedure,
+		nodev1connect.NodeStoreServiceListClientsProcedure:
 		return ActionListNodes
 	case routev1connect.RouteStoreServiceListRoutesProcedure:
 		return ActionListRoutes
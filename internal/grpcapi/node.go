@@ -5,8 +5,11 @@ import (
 	"sort"
 
 	"connectrpc.com/connect"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/clients"
 	v1 "github.com/kaasops/envoy-xds-controller/pkg/api/grpc/node/v1"
 	"github.com/kaasops/envoy-xds-controller/pkg/api/grpc/node/v1/nodev1connect"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type NodeStore struct {
@@ -16,6 +19,7 @@ type NodeStore struct {
 
 type NodeService interface {
 	GetNodeIDs() []string
+	ListClients() []clients.Info
 }
 
 func NewNodeStore(svc NodeService) *NodeStore {
@@ -51,3 +55,81 @@ func (s *NodeStore) ListNodes(ctx context.Context, req *connect.Request[v1.ListN
 	})
 	return connect.NewResponse(&v1.ListNodesResponse{Items: list}), nil
 }
+
+func (s *NodeStore) ListClients(ctx context.Context, req *connect.Request[v1.ListClientsRequest]) (*connect.Response[v1.ListClientsResponse], error) {
+	authorizer := GetAuthorizerFromContext(ctx)
+
+	accessGroup := req.Msg.AccessGroup
+	if accessGroup == "" {
+		accessGroup = GeneralAccessGroup
+	}
+
+	list := make([]*v1.Client, 0)
+	for _, info := range s.nodeSvc.ListClients() {
+		if req.Msg.NodeId != "" && info.NodeID != req.Msg.NodeId {
+			continue
+		}
+		isAllowed, err := authorizer.Authorize(accessGroup, info.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		if !isAllowed {
+			continue
+		}
+		item, err := clientToProto(info)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		list = append(list, item)
+	}
+	return connect.NewResponse(&v1.ListClientsResponse{Items: list}), nil
+}
+
+func clientToProto(info clients.Info) (*v1.Client, error) {
+	item := &v1.Client{
+		StreamId:     info.ID,
+		NodeId:       info.NodeID,
+		Cluster:      info.Cluster,
+		Address:      info.Address,
+		Protocol:     info.Protocol,
+		EnvoyVersion: info.Version,
+		ConnectedAt:  timestamppb.New(info.ConnectedAt),
+		Stale:        info.Stale,
+	}
+	if info.Metadata != nil {
+		metadata, err := structpb.NewStruct(info.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		item.Metadata = metadata
+	}
+	if info.Locality != nil {
+		item.Locality = &v1.Locality{
+			Region:  info.Locality.Region,
+			Zone:    info.Locality.Zone,
+			SubZone: info.Locality.SubZone,
+		}
+	}
+	for _, sub := range info.Subscriptions {
+		subscription := &v1.Subscription{
+			TypeUrl:        sub.TypeURL,
+			ResourceNames:  sub.ResourceNames,
+			SentVersion:    sub.SentVersion,
+			AckedVersion:   sub.AckedVersion,
+			CurrentVersion: sub.CurrentVersion,
+			Synced:         sub.Synced,
+		}
+		if sub.AckedAt != nil {
+			subscription.AckedAt = timestamppb.New(*sub.AckedAt)
+		}
+		if sub.LastNack != nil {
+			subscription.LastNack = &v1.Nack{
+				Version:   sub.LastNack.Version,
+				Message:   sub.LastNack.Message,
+				Timestamp: timestamppb.New(sub.LastNack.Timestamp),
+			}
+		}
+		item.Subscriptions = append(item.Subscriptions, subscription)
+	}
+	return item, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/kaasops/envoy-xds-controller/internal/grpcapi"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
 	"github.com/kaasops/envoy-xds-controller/internal/protoutil"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
 	commonv1 "github.com/kaasops/envoy-xds-controller/pkg/api/grpc/common/v1"
 	v1 "github.com/kaasops/envoy-xds-controller/pkg/api/grpc/virtual_service/v1"
 	virtual_service_templatev1 "github.com/kaasops/envoy-xds-controller/pkg/api/grpc/virtual_service_template/v1"
@@ -153,9 +154,32 @@ func (s *VirtualServiceStore) GetVirtualService(_ context.Context, req *connect.
 		tlsConfig.AutoDiscovery = vs.Spec.TlsConfig.AutoDiscovery
 		resp.TlsConfig = tlsConfig
 	}
+	if s.cacheUpdater != nil {
+		resp.SyncStatus = syncStatusToProto(s.cacheUpdater.GetSyncStatus(helpers.NamespacedName{
+			Namespace: vs.Namespace,
+			Name:      vs.Name,
+		}))
+	}
 	return connect.NewResponse(resp), nil
 }
 
+func syncStatusToProto(status updater.SyncStatus) *v1.SyncStatus {
+	result := &v1.SyncStatus{
+		Synced:  status.Synced(),
+		Message: status.Message(),
+		Nodes:   make([]*v1.NodeSyncStatus, 0, len(status.Nodes)),
+	}
+	for _, node := range status.Nodes {
+		result.Nodes = append(result.Nodes, &v1.NodeSyncStatus{
+			NodeId:       node.NodeID,
+			Version:      node.Version,
+			Acknowledged: uint32(node.Acknowledged),
+			Total:        uint32(node.Total),
+		})
+	}
+	return result
+}
+
 func rawJSONString(input any) string {
 	data, err := json.Marshal(input)
 	if err != nil {
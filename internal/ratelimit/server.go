@@ -0,0 +1,21 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+
+	rlsv3 "github.com/envoyproxy/go-control-plane/envoy/service/ratelimit/v3"
+	"google.golang.org/grpc"
+)
+
+// RunServer serves the rate limit service at the given port
+func RunServer(svc *Service, port int, options ...grpc.ServerOption) error {
+	grpcServer := grpc.NewServer(options...)
+	rlsv3.RegisterRateLimitServiceServer(grpcServer, svc)
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+	return grpcServer.Serve(lis)
+}
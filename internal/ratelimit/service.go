@@ -0,0 +1,202 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	ratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/common/ratelimit/v3"
+	rlsv3 "github.com/envoyproxy/go-control-plane/envoy/service/ratelimit/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// sweepInterval is how often buckets back to full capacity are dropped
+const sweepInterval = time.Minute
+
+var responseUnits = map[string]rlsv3.RateLimitResponse_RateLimit_Unit{
+	v1alpha1.RateLimitUnitSecond: rlsv3.RateLimitResponse_RateLimit_SECOND,
+	v1alpha1.RateLimitUnitMinute: rlsv3.RateLimitResponse_RateLimit_MINUTE,
+	v1alpha1.RateLimitUnitHour:   rlsv3.RateLimitResponse_RateLimit_HOUR,
+	v1alpha1.RateLimitUnitDay:    rlsv3.RateLimitResponse_RateLimit_DAY,
+}
+
+// Service implements the Envoy Rate Limit Service API for the limits of RateLimitPolicies,
+// with in-memory token buckets. Descriptors carry the node of the proxy, so buckets are shared
+// by the proxies of a node. They are held by the process: replicas behind the same Cluster
+// count requests separately.
+type Service struct {
+	rlsv3.UnimplementedRateLimitServiceServer
+
+	store store.Store
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewService creates a rate limit service for the RateLimitPolicies of the store
+func NewService(st store.Store) *Service {
+	return &Service{
+		store:   st,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// bucket holds the tokens of a descriptor, refilled continuously up to the requests of its limit
+type bucket struct {
+	tokens   float64
+	capacity float64
+	perSec   float64
+	updated  time.Time
+}
+
+func newBucket(limit *v1alpha1.RateLimit, now time.Time) *bucket {
+	capacity := float64(limit.Requests)
+	return &bucket{
+		tokens:   capacity,
+		capacity: capacity,
+		perSec:   capacity / limit.UnitDuration().Seconds(),
+		updated:  now,
+	}
+}
+
+// update applies changes of the limit, keeping the tokens taken
+func (b *bucket) update(limit *v1alpha1.RateLimit) {
+	capacity := float64(limit.Requests)
+	if capacity == b.capacity && b.perSec == capacity/limit.UnitDuration().Seconds() {
+		return
+	}
+	b.tokens = math.Max(0, b.tokens+capacity-b.capacity)
+	b.capacity = capacity
+	b.perSec = capacity / limit.UnitDuration().Seconds()
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.perSec)
+		b.updated = now
+	}
+}
+
+// take removes the hits from the bucket if it holds enough tokens
+func (b *bucket) take(hits float64, now time.Time) bool {
+	b.refill(now)
+	if b.tokens < hits {
+		return false
+	}
+	b.tokens -= hits
+	return true
+}
+
+// untilFull returns the time until the bucket is back to its capacity
+func (b *bucket) untilFull() time.Duration {
+	return time.Duration((b.capacity - b.tokens) / b.perSec * float64(time.Second))
+}
+
+// ShouldRateLimit takes the hits of the request from the bucket of each descriptor identifying
+// the limit of a RateLimitPolicy. Other descriptors are not limited.
+func (s *Service) ShouldRateLimit(
+	_ context.Context,
+	req *rlsv3.RateLimitRequest,
+) (*rlsv3.RateLimitResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	resp := &rlsv3.RateLimitResponse{
+		OverallCode: rlsv3.RateLimitResponse_OK,
+		Statuses:    make([]*rlsv3.RateLimitResponse_DescriptorStatus, 0, len(req.Descriptors)),
+	}
+	for _, descriptor := range req.Descriptors {
+		status := &rlsv3.RateLimitResponse_DescriptorStatus{Code: rlsv3.RateLimitResponse_OK}
+		resp.Statuses = append(resp.Statuses, status)
+
+		limit := s.limit(descriptor)
+		if limit == nil {
+			continue
+		}
+		key := bucketKey(req.Domain, descriptor)
+		b, ok := s.buckets[key]
+		if !ok {
+			b = newBucket(limit, now)
+			s.buckets[key] = b
+		} else {
+			b.update(limit)
+		}
+
+		hits := uint64(req.HitsAddend)
+		if descriptor.HitsAddend != nil {
+			hits = descriptor.HitsAddend.Value
+		}
+		if hits == 0 {
+			hits = 1
+		}
+		if !b.take(float64(hits), now) {
+			status.Code = rlsv3.RateLimitResponse_OVER_LIMIT
+			resp.OverallCode = rlsv3.RateLimitResponse_OVER_LIMIT
+		}
+		status.CurrentLimit = &rlsv3.RateLimitResponse_RateLimit{
+			Name:            descriptor.Entries[0].Value,
+			RequestsPerUnit: limit.Requests,
+			Unit:            responseUnits[limit.Unit],
+		}
+		status.LimitRemaining = uint32(b.tokens)
+		status.DurationUntilReset = durationpb.New(b.untilFull())
+	}
+	return resp, nil
+}
+
+// limit returns the limit of the RateLimitPolicy identified by the first entry of the descriptor
+func (s *Service) limit(descriptor *ratelimitv3.RateLimitDescriptor) *v1alpha1.RateLimit {
+	if len(descriptor.Entries) == 0 || descriptor.Entries[0].Key != v1alpha1.RateLimitPolicyDescriptorKey {
+		return nil
+	}
+	parts := strings.Split(descriptor.Entries[0].Value, "/")
+	if len(parts) != 3 {
+		return nil
+	}
+	policy := s.store.GetRateLimitPolicy(helpers.NamespacedName{Namespace: parts[0], Name: parts[1]})
+	if policy == nil {
+		return nil
+	}
+	limit := policy.GetLimit(parts[2])
+	if limit == nil || limit.Requests == 0 || limit.UnitDuration() == 0 {
+		return nil
+	}
+	return limit
+}
+
+// sweep drops buckets back to full capacity, which are recreated as new ones
+func (s *Service) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if b.refill(now); b.tokens >= b.capacity {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// bucketKey identifies the bucket of the descriptor, by all its entries including the node
+func bucketKey(domain string, descriptor *ratelimitv3.RateLimitDescriptor) string {
+	var sb strings.Builder
+	sb.WriteString(domain)
+	for _, entry := range descriptor.Entries {
+		sb.WriteByte(0)
+		sb.WriteString(entry.Key)
+		sb.WriteByte('=')
+		sb.WriteString(entry.Value)
+	}
+	return sb.String()
+}
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/common/ratelimit/v3"
+	rlsv3 "github.com/envoyproxy/go-control-plane/envoy/service/ratelimit/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func request(entries ...string) *rlsv3.RateLimitRequest {
+	descriptor := &ratelimitv3.RateLimitDescriptor{}
+	for i := 0; i+1 < len(entries); i += 2 {
+		descriptor.Entries = append(descriptor.Entries, &ratelimitv3.RateLimitDescriptor_Entry{
+			Key:   entries[i],
+			Value: entries[i+1],
+		})
+	}
+	return &rlsv3.RateLimitRequest{
+		Domain:      v1alpha1.RateLimitDefaultDomain,
+		Descriptors: []*ratelimitv3.RateLimitDescriptor{descriptor},
+	}
+}
+
+func TestService_ShouldRateLimit(t *testing.T) {
+	ctx := context.Background()
+	st := store.New()
+	st.SetRateLimitPolicy(&v1alpha1.RateLimitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "api"},
+		Spec: v1alpha1.RateLimitPolicySpec{
+			VirtualService: "vs",
+			Limits: []v1alpha1.RateLimit{
+				{Name: "per-client", Requests: 2, Unit: v1alpha1.RateLimitUnitMinute},
+			},
+		},
+	})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc := NewService(st)
+	svc.now = func() time.Time { return now }
+
+	check := func(req *rlsv3.RateLimitRequest, expected rlsv3.RateLimitResponse_Code) {
+		t.Helper()
+		resp, err := svc.ShouldRateLimit(ctx, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.OverallCode != expected {
+			t.Errorf("expected %v, got %v", expected, resp.OverallCode)
+		}
+	}
+
+	clientA := request(v1alpha1.RateLimitPolicyDescriptorKey, "ns/api/per-client", "remote_address", "10.0.0.1")
+	clientB := request(v1alpha1.RateLimitPolicyDescriptorKey, "ns/api/per-client", "remote_address", "10.0.0.2")
+	check(clientA, rlsv3.RateLimitResponse_OK)
+	check(clientA, rlsv3.RateLimitResponse_OK)
+	check(clientA, rlsv3.RateLimitResponse_OVER_LIMIT)
+	// Each key has its own bucket
+	check(clientB, rlsv3.RateLimitResponse_OK)
+
+	// Each node has its own bucket
+	nodeA := request(v1alpha1.RateLimitPolicyDescriptorKey, "ns/api/per-client",
+		v1alpha1.RateLimitNodeDescriptorKey, "node-a", "remote_address", "10.0.0.1")
+	nodeB := request(v1alpha1.RateLimitPolicyDescriptorKey, "ns/api/per-client",
+		v1alpha1.RateLimitNodeDescriptorKey, "node-b", "remote_address", "10.0.0.1")
+	check(nodeA, rlsv3.RateLimitResponse_OK)
+	check(nodeA, rlsv3.RateLimitResponse_OK)
+	check(nodeA, rlsv3.RateLimitResponse_OVER_LIMIT)
+	check(nodeB, rlsv3.RateLimitResponse_OK)
+
+	// Tokens are refilled over the unit
+	now = now.Add(30 * time.Second)
+	check(clientA, rlsv3.RateLimitResponse_OK)
+	check(clientA, rlsv3.RateLimitResponse_OVER_LIMIT)
+
+	// Descriptors of unknown policies or limits are not limited
+	for i := 0; i < 3; i++ {
+		check(request(v1alpha1.RateLimitPolicyDescriptorKey, "ns/api/unknown"), rlsv3.RateLimitResponse_OK)
+		check(request(v1alpha1.RateLimitPolicyDescriptorKey, "ns/missing/per-client"), rlsv3.RateLimitResponse_OK)
+		check(request("generic_key", "value"), rlsv3.RateLimitResponse_OK)
+	}
+
+	// Buckets back to full capacity are dropped
+	now = now.Add(2 * sweepInterval)
+	check(request("generic_key", "value"), rlsv3.RateLimitResponse_OK)
+	if len(svc.buckets) != 0 {
+		t.Errorf("expected full buckets to be dropped, got %d", len(svc.buckets))
+	}
+}
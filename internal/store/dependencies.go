@@ -0,0 +1,181 @@
+package store
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+)
+
+// DependencyKind is the kind of object a VirtualService depends on
+type DependencyKind string
+
+const (
+	DependencyListener        DependencyKind = "Listener"
+	DependencyRoute           DependencyKind = "Route"
+	DependencyHTTPFilter      DependencyKind = "HttpFilter"
+	DependencyCluster         DependencyKind = "Cluster"
+	DependencySpecCluster     DependencyKind = "SpecCluster"
+	DependencySecret          DependencyKind = "Secret"
+	DependencyTemplate        DependencyKind = "VirtualServiceTemplate"
+	DependencyTracing         DependencyKind = "Tracing"
+	DependencyAccessLogConfig DependencyKind = "AccessLogConfig"
+	DependencyPolicy          DependencyKind = "Policy"
+	DependencyTrafficSplit    DependencyKind = "TrafficSplit"
+	DependencyRateLimitPolicy DependencyKind = "RateLimitPolicy"
+)
+
+// Dependency references an object used to build a VirtualService.
+// An empty Name means the VirtualService depends on all objects of the kind,
+// e.g. secrets discovered by domain. SpecCluster dependencies use the Envoy
+// cluster name as Name.Name with an empty namespace. TrafficSplit and RateLimitPolicy
+// dependencies use the name of the VirtualService they are looked up for.
+type Dependency struct {
+	Kind DependencyKind
+	Name helpers.NamespacedName
+}
+
+// AnyOf returns a dependency on all objects of the kind
+func AnyOf(kind DependencyKind) Dependency {
+	return Dependency{Kind: kind}
+}
+
+// DependencyIndex keeps track of the objects each VirtualService was built from,
+// and the reverse mapping used to find VirtualServices affected by a change.
+type DependencyIndex struct {
+	mu         sync.RWMutex
+	byVS       map[helpers.NamespacedName]map[Dependency]struct{}
+	dependents map[Dependency]map[helpers.NamespacedName]struct{}
+}
+
+// NewDependencyIndex creates a new dependency index
+func NewDependencyIndex() *DependencyIndex {
+	return &DependencyIndex{
+		byVS:       make(map[helpers.NamespacedName]map[Dependency]struct{}),
+		dependents: make(map[Dependency]map[helpers.NamespacedName]struct{}),
+	}
+}
+
+// Set replaces the dependencies of the VirtualService
+func (d *DependencyIndex) Set(vs helpers.NamespacedName, deps map[Dependency]struct{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.delete(vs)
+	if len(deps) == 0 {
+		return
+	}
+	own := make(map[Dependency]struct{}, len(deps))
+	for dep := range deps {
+		own[dep] = struct{}{}
+		vsSet, ok := d.dependents[dep]
+		if !ok {
+			vsSet = make(map[helpers.NamespacedName]struct{})
+			d.dependents[dep] = vsSet
+		}
+		vsSet[vs] = struct{}{}
+	}
+	d.byVS[vs] = own
+}
+
+// Delete removes the dependencies of the VirtualService
+func (d *DependencyIndex) Delete(vs helpers.NamespacedName) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.delete(vs)
+}
+
+func (d *DependencyIndex) delete(vs helpers.NamespacedName) {
+	for dep := range d.byVS[vs] {
+		if vsSet, ok := d.dependents[dep]; ok {
+			delete(vsSet, vs)
+			if len(vsSet) == 0 {
+				delete(d.dependents, dep)
+			}
+		}
+	}
+	delete(d.byVS, vs)
+}
+
+// Get returns the dependencies of the VirtualService
+func (d *DependencyIndex) Get(vs helpers.NamespacedName) []Dependency {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	result := make([]Dependency, 0, len(d.byVS[vs]))
+	for dep := range d.byVS[vs] {
+		result = append(result, dep)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Name.String() < result[j].Name.String()
+	})
+	return result
+}
+
+// Dependents returns VirtualServices depending on any of the given objects,
+// including VirtualServices depending on all objects of their kinds.
+func (d *DependencyIndex) Dependents(deps ...Dependency) []helpers.NamespacedName {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	set := make(map[helpers.NamespacedName]struct{})
+	for _, dep := range deps {
+		for vs := range d.dependents[dep] {
+			set[vs] = struct{}{}
+		}
+		for vs := range d.dependents[AnyOf(dep.Kind)] {
+			set[vs] = struct{}{}
+		}
+	}
+	result := make([]helpers.NamespacedName, 0, len(set))
+	for vs := range set {
+		result = append(result, vs)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].String() < result[j].String()
+	})
+	return result
+}
+
+// References returns VirtualServices built from any of the given objects, not including
+// VirtualServices depending on all objects of their kinds.
+func (d *DependencyIndex) References(deps ...Dependency) []helpers.NamespacedName {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	set := make(map[helpers.NamespacedName]struct{})
+	for _, dep := range deps {
+		for vs := range d.dependents[dep] {
+			set[vs] = struct{}{}
+		}
+	}
+	result := make([]helpers.NamespacedName, 0, len(set))
+	for vs := range set {
+		result = append(result, vs)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].String() < result[j].String()
+	})
+	return result
+}
+
+// Copy creates a deep copy of the dependency index
+func (d *DependencyIndex) Copy() *DependencyIndex {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	newIndex := NewDependencyIndex()
+	for vs, deps := range d.byVS {
+		own := make(map[Dependency]struct{}, len(deps))
+		for dep := range deps {
+			own[dep] = struct{}{}
+		}
+		newIndex.byVS[vs] = own
+	}
+	for dep, vsSet := range d.dependents {
+		set := make(map[helpers.NamespacedName]struct{}, len(vsSet))
+		for vs := range vsSet {
+			set[vs] = struct{}{}
+		}
+		newIndex.dependents[dep] = set
+	}
+	return newIndex
+}
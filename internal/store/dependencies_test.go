@@ -0,0 +1,52 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestDependencyIndex verifies lookups of dependent and referencing VirtualServices, including
+// dependencies on all objects of a kind, and cleanup on VirtualService deletion.
+func TestDependencyIndex(t *testing.T) {
+	s := New()
+	vsA := helpers.NamespacedName{Namespace: "ns", Name: "vs-a"}
+	vsB := helpers.NamespacedName{Namespace: "ns", Name: "vs-b"}
+	routeNN := helpers.NamespacedName{Namespace: "ns", Name: "route"}
+	secretNN := helpers.NamespacedName{Namespace: "ns", Name: "secret"}
+
+	s.SetVirtualService(&v1alpha1.VirtualService{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "vs-a"}})
+
+	recorder := NewDependencyRecorder(s)
+	_ = recorder.GetRoute(routeNN)
+	_ = recorder.GetDomainSecretWithWildcardFallback("example.com", "ns")
+	s.SetVirtualServiceDependencies(vsA, recorder.Dependencies())
+	s.SetVirtualServiceDependencies(vsB, map[Dependency]struct{}{
+		{Kind: DependencySecret, Name: secretNN}: {},
+	})
+
+	if got := s.GetDependentVirtualServices(Dependency{Kind: DependencyRoute, Name: routeNN}); len(got) != 1 || got[0] != vsA {
+		t.Errorf("expected [%v] to depend on route, got %v", vsA, got)
+	}
+	if got := s.GetDependentVirtualServices(Dependency{Kind: DependencySecret, Name: secretNN}); len(got) != 2 {
+		t.Errorf("expected both VirtualServices to depend on secret, got %v", got)
+	}
+	if got := s.GetDependentVirtualServices(Dependency{Kind: DependencyListener, Name: routeNN}); len(got) != 0 {
+		t.Errorf("expected no dependents of listener, got %v", got)
+	}
+	if got := s.GetReferencingVirtualServices(Dependency{Kind: DependencySecret, Name: secretNN}); len(got) != 1 || got[0] != vsB {
+		t.Errorf("expected only [%v] to reference secret by name, got %v", vsB, got)
+	}
+
+	// Copies are independent of the original store
+	storeCopy := s.Copy()
+	s.DeleteVirtualService(vsA)
+	if got := s.GetVirtualServiceDependencies(vsA); len(got) != 0 {
+		t.Errorf("expected dependencies of deleted VirtualService to be removed, got %v", got)
+	}
+	if got := storeCopy.GetVirtualServiceDependencies(vsA); len(got) != 2 {
+		t.Errorf("expected copy to keep dependencies, got %v", got)
+	}
+}
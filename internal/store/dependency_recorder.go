@@ -0,0 +1,175 @@
+package store
+
+import (
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DependencyRecorder wraps a Store and records every object read through it.
+// It is used while building resources for a single VirtualService, so the
+// dependencies of the VirtualService are exactly the objects the builder used,
+// including lookups of objects that do not exist yet. It is not safe for concurrent use.
+type DependencyRecorder struct {
+	Store
+	deps map[Dependency]struct{}
+}
+
+// NewDependencyRecorder creates a recorder reading from the given store
+func NewDependencyRecorder(s Store) *DependencyRecorder {
+	return &DependencyRecorder{
+		Store: s,
+		deps:  make(map[Dependency]struct{}),
+	}
+}
+
+// Dependencies returns the recorded dependencies
+func (r *DependencyRecorder) Dependencies() map[Dependency]struct{} {
+	return r.deps
+}
+
+func (r *DependencyRecorder) record(kind DependencyKind, nn helpers.NamespacedName) {
+	r.deps[Dependency{Kind: kind, Name: nn}] = struct{}{}
+}
+
+func (r *DependencyRecorder) GetVirtualServiceTemplate(nn helpers.NamespacedName) *v1alpha1.VirtualServiceTemplate {
+	r.record(DependencyTemplate, nn)
+	return r.Store.GetVirtualServiceTemplate(nn)
+}
+
+func (r *DependencyRecorder) MapVirtualServiceTemplates() map[helpers.NamespacedName]*v1alpha1.VirtualServiceTemplate {
+	r.deps[AnyOf(DependencyTemplate)] = struct{}{}
+	return r.Store.MapVirtualServiceTemplates()
+}
+
+func (r *DependencyRecorder) GetListener(nn helpers.NamespacedName) *v1alpha1.Listener {
+	r.record(DependencyListener, nn)
+	return r.Store.GetListener(nn)
+}
+
+func (r *DependencyRecorder) MapListeners() map[helpers.NamespacedName]*v1alpha1.Listener {
+	r.deps[AnyOf(DependencyListener)] = struct{}{}
+	return r.Store.MapListeners()
+}
+
+func (r *DependencyRecorder) GetRoute(nn helpers.NamespacedName) *v1alpha1.Route {
+	r.record(DependencyRoute, nn)
+	return r.Store.GetRoute(nn)
+}
+
+func (r *DependencyRecorder) MapRoutes() map[helpers.NamespacedName]*v1alpha1.Route {
+	r.deps[AnyOf(DependencyRoute)] = struct{}{}
+	return r.Store.MapRoutes()
+}
+
+func (r *DependencyRecorder) GetCluster(nn helpers.NamespacedName) *v1alpha1.Cluster {
+	r.record(DependencyCluster, nn)
+	return r.Store.GetCluster(nn)
+}
+
+func (r *DependencyRecorder) GetSpecCluster(name string) *v1alpha1.Cluster {
+	r.record(DependencySpecCluster, helpers.NamespacedName{Name: name})
+	return r.Store.GetSpecCluster(name)
+}
+
+func (r *DependencyRecorder) MapClusters() map[helpers.NamespacedName]*v1alpha1.Cluster {
+	r.deps[AnyOf(DependencyCluster)] = struct{}{}
+	return r.Store.MapClusters()
+}
+
+func (r *DependencyRecorder) MapSpecClusters() map[string]*v1alpha1.Cluster {
+	r.deps[AnyOf(DependencySpecCluster)] = struct{}{}
+	return r.Store.MapSpecClusters()
+}
+
+func (r *DependencyRecorder) GetHTTPFilter(nn helpers.NamespacedName) *v1alpha1.HttpFilter {
+	r.record(DependencyHTTPFilter, nn)
+	return r.Store.GetHTTPFilter(nn)
+}
+
+func (r *DependencyRecorder) MapHTTPFilters() map[helpers.NamespacedName]*v1alpha1.HttpFilter {
+	r.deps[AnyOf(DependencyHTTPFilter)] = struct{}{}
+	return r.Store.MapHTTPFilters()
+}
+
+func (r *DependencyRecorder) GetAccessLog(nn helpers.NamespacedName) *v1alpha1.AccessLogConfig {
+	r.record(DependencyAccessLogConfig, nn)
+	return r.Store.GetAccessLog(nn)
+}
+
+func (r *DependencyRecorder) MapAccessLogs() map[helpers.NamespacedName]*v1alpha1.AccessLogConfig {
+	r.deps[AnyOf(DependencyAccessLogConfig)] = struct{}{}
+	return r.Store.MapAccessLogs()
+}
+
+func (r *DependencyRecorder) GetPolicy(nn helpers.NamespacedName) *v1alpha1.Policy {
+	r.record(DependencyPolicy, nn)
+	return r.Store.GetPolicy(nn)
+}
+
+func (r *DependencyRecorder) MapPolicies() map[helpers.NamespacedName]*v1alpha1.Policy {
+	r.deps[AnyOf(DependencyPolicy)] = struct{}{}
+	return r.Store.MapPolicies()
+}
+
+func (r *DependencyRecorder) GetTracing(nn helpers.NamespacedName) *v1alpha1.Tracing {
+	r.record(DependencyTracing, nn)
+	return r.Store.GetTracing(nn)
+}
+
+func (r *DependencyRecorder) MapTracings() map[helpers.NamespacedName]*v1alpha1.Tracing {
+	r.deps[AnyOf(DependencyTracing)] = struct{}{}
+	return r.Store.MapTracings()
+}
+
+func (r *DependencyRecorder) GetTrafficSplitsForVirtualService(vs helpers.NamespacedName) []*v1alpha1.TrafficSplit {
+	r.record(DependencyTrafficSplit, vs)
+	return r.Store.GetTrafficSplitsForVirtualService(vs)
+}
+
+func (r *DependencyRecorder) GetRateLimitPoliciesForVirtualService(
+	vs helpers.NamespacedName,
+) []*v1alpha1.RateLimitPolicy {
+	r.record(DependencyRateLimitPolicy, vs)
+	return r.Store.GetRateLimitPoliciesForVirtualService(vs)
+}
+
+func (r *DependencyRecorder) GetSecret(nn helpers.NamespacedName) *corev1.Secret {
+	r.record(DependencySecret, nn)
+	return r.Store.GetSecret(nn)
+}
+
+func (r *DependencyRecorder) MapSecrets() map[helpers.NamespacedName]*corev1.Secret {
+	r.deps[AnyOf(DependencySecret)] = struct{}{}
+	return r.Store.MapSecrets()
+}
+
+// Secrets looked up by domain may change whenever any secret changes
+
+func (r *DependencyRecorder) MapDomainSecrets() map[string]*corev1.Secret {
+	r.deps[AnyOf(DependencySecret)] = struct{}{}
+	return r.Store.MapDomainSecrets()
+}
+
+func (r *DependencyRecorder) MapDomainSecretsForNamespace(preferredNamespace string) map[string]*corev1.Secret {
+	r.deps[AnyOf(DependencySecret)] = struct{}{}
+	return r.Store.MapDomainSecretsForNamespace(preferredNamespace)
+}
+
+func (r *DependencyRecorder) GetDomainSecretForNamespace(domain string, preferredNamespace string) *corev1.Secret {
+	r.deps[AnyOf(DependencySecret)] = struct{}{}
+	return r.Store.GetDomainSecretForNamespace(domain, preferredNamespace)
+}
+
+func (r *DependencyRecorder) GetDomainSecretWithWildcardFallback(domain string, preferredNamespace string) *corev1.Secret {
+	r.deps[AnyOf(DependencySecret)] = struct{}{}
+	return r.Store.GetDomainSecretWithWildcardFallback(domain, preferredNamespace)
+}
+
+func (r *DependencyRecorder) GetDomainSecretWithWildcardFallbackInfo(
+	domain string,
+	preferredNamespace string,
+) SecretLookupResult {
+	r.deps[AnyOf(DependencySecret)] = struct{}{}
+	return r.Store.GetDomainSecretWithWildcardFallbackInfo(domain, preferredNamespace)
+}
@@ -0,0 +1,167 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+// Endpoint operations
+
+func (s *OptimizedStore) SetEndpoint(endpoint *v1alpha1.Endpoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	endpoint.Name = s.stringPool.Intern(endpoint.Name)
+	endpoint.Namespace = s.stringPool.InternNamespace(endpoint.Namespace)
+
+	key := helpers.NamespacedName{Namespace: endpoint.Namespace, Name: endpoint.Name}
+
+	if old := s.endpoints[key]; old != nil {
+		s.removeSpecEndpoint(old)
+	}
+
+	s.endpoints[key] = endpoint
+	s.addSpecEndpoint(endpoint)
+}
+
+func (s *OptimizedStore) GetEndpoint(name helpers.NamespacedName) *v1alpha1.Endpoint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.endpoints[name]
+}
+
+func (s *OptimizedStore) DeleteEndpoint(name helpers.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if endpoint := s.endpoints[name]; endpoint != nil {
+		s.removeSpecEndpoint(endpoint)
+		delete(s.endpoints, name)
+	}
+}
+
+func (s *OptimizedStore) IsExistingEndpoint(name helpers.NamespacedName) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.endpoints[name]
+	return ok
+}
+
+func (s *OptimizedStore) MapEndpoints() map[helpers.NamespacedName]*v1alpha1.Endpoint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[helpers.NamespacedName]*v1alpha1.Endpoint, len(s.endpoints))
+	for k, v := range s.endpoints {
+		result[k] = v
+	}
+	return result
+}
+
+// GetSpecEndpoint returns the Endpoint whose ClusterLoadAssignment has the given cluster name
+func (s *OptimizedStore) GetSpecEndpoint(clusterName string) *v1alpha1.Endpoint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.specEndpoints[clusterName]
+}
+
+func (s *OptimizedStore) addSpecEndpoint(endpoint *v1alpha1.Endpoint) {
+	if claV3, err := endpoint.UnmarshalV3(); err == nil {
+		s.specEndpoints[claV3.ClusterName] = endpoint
+	}
+}
+
+func (s *OptimizedStore) removeSpecEndpoint(endpoint *v1alpha1.Endpoint) {
+	if claV3, err := endpoint.UnmarshalV3(); err == nil && s.specEndpoints[claV3.ClusterName] == endpoint {
+		delete(s.specEndpoints, claV3.ClusterName)
+	}
+}
+
+// EndpointSlice operations
+
+func (s *OptimizedStore) SetEndpointSlice(slice *discoveryv1.EndpointSlice) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slice.Name = s.stringPool.Intern(slice.Name)
+	slice.Namespace = s.stringPool.InternNamespace(slice.Namespace)
+
+	key := helpers.NamespacedName{Namespace: slice.Namespace, Name: slice.Name}
+
+	if old := s.endpointSlices[key]; old != nil {
+		s.removeServiceEndpointSlice(old)
+	}
+
+	s.endpointSlices[key] = slice
+	if service, ok := EndpointSliceService(slice); ok {
+		slices := s.serviceEndpointSlices[service]
+		if slices == nil {
+			slices = make(map[helpers.NamespacedName]*discoveryv1.EndpointSlice)
+			s.serviceEndpointSlices[service] = slices
+		}
+		slices[key] = slice
+	}
+}
+
+func (s *OptimizedStore) GetEndpointSlice(name helpers.NamespacedName) *discoveryv1.EndpointSlice {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.endpointSlices[name]
+}
+
+func (s *OptimizedStore) DeleteEndpointSlice(name helpers.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if slice := s.endpointSlices[name]; slice != nil {
+		s.removeServiceEndpointSlice(slice)
+		delete(s.endpointSlices, name)
+	}
+}
+
+// GetEndpointSlicesForService returns the EndpointSlices of a Kubernetes Service, sorted by name
+func (s *OptimizedStore) GetEndpointSlicesForService(service helpers.NamespacedName) []*discoveryv1.EndpointSlice {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	slices := s.serviceEndpointSlices[service]
+	if len(slices) == 0 {
+		return nil
+	}
+	result := make([]*discoveryv1.EndpointSlice, 0, len(slices))
+	for _, slice := range slices {
+		result = append(result, slice)
+	}
+	sortEndpointSlices(result)
+	return result
+}
+
+func (s *OptimizedStore) removeServiceEndpointSlice(slice *discoveryv1.EndpointSlice) {
+	service, ok := EndpointSliceService(slice)
+	if !ok {
+		return
+	}
+	slices := s.serviceEndpointSlices[service]
+	delete(slices, helpers.NamespacedName{Namespace: slice.Namespace, Name: slice.Name})
+	if len(slices) == 0 {
+		delete(s.serviceEndpointSlices, service)
+	}
+}
+
+// EndpointSliceService returns the Service an EndpointSlice belongs to
+func EndpointSliceService(slice *discoveryv1.EndpointSlice) (helpers.NamespacedName, bool) {
+	name := slice.Labels[discoveryv1.LabelServiceName]
+	if name == "" {
+		return helpers.NamespacedName{}, false
+	}
+	return helpers.NamespacedName{Namespace: slice.Namespace, Name: name}, true
+}
+
+func sortEndpointSlices(slices []*discoveryv1.EndpointSlice) {
+	sort.Slice(slices, func(i, j int) bool {
+		return slices[i].Name < slices[j].Name
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -95,11 +96,51 @@ type Store interface {
 	IsExistingTracing(name helpers.NamespacedName) bool
 	MapTracings() map[helpers.NamespacedName]*v1alpha1.Tracing
 
+	// Endpoints
+	GetEndpoint(name helpers.NamespacedName) *v1alpha1.Endpoint
+	SetEndpoint(e *v1alpha1.Endpoint)
+	DeleteEndpoint(name helpers.NamespacedName)
+	IsExistingEndpoint(name helpers.NamespacedName) bool
+	MapEndpoints() map[helpers.NamespacedName]*v1alpha1.Endpoint
+	GetSpecEndpoint(clusterName string) *v1alpha1.Endpoint
+
+	// Runtimes
+	GetRuntime(name helpers.NamespacedName) *v1alpha1.Runtime
+	SetRuntime(r *v1alpha1.Runtime)
+	DeleteRuntime(name helpers.NamespacedName)
+	MapRuntimes() map[helpers.NamespacedName]*v1alpha1.Runtime
+
+	// TrafficSplits
+	GetTrafficSplit(name helpers.NamespacedName) *v1alpha1.TrafficSplit
+	SetTrafficSplit(ts *v1alpha1.TrafficSplit)
+	DeleteTrafficSplit(name helpers.NamespacedName)
+	MapTrafficSplits() map[helpers.NamespacedName]*v1alpha1.TrafficSplit
+	GetTrafficSplitsForVirtualService(vs helpers.NamespacedName) []*v1alpha1.TrafficSplit
+
+	// RateLimitPolicies
+	GetRateLimitPolicy(name helpers.NamespacedName) *v1alpha1.RateLimitPolicy
+	SetRateLimitPolicy(p *v1alpha1.RateLimitPolicy)
+	DeleteRateLimitPolicy(name helpers.NamespacedName)
+	MapRateLimitPolicies() map[helpers.NamespacedName]*v1alpha1.RateLimitPolicy
+	GetRateLimitPoliciesForVirtualService(vs helpers.NamespacedName) []*v1alpha1.RateLimitPolicy
+
+	// EndpointSlices
+	GetEndpointSlice(name helpers.NamespacedName) *discoveryv1.EndpointSlice
+	SetEndpointSlice(slice *discoveryv1.EndpointSlice)
+	DeleteEndpointSlice(name helpers.NamespacedName)
+	GetEndpointSlicesForService(service helpers.NamespacedName) []*discoveryv1.EndpointSlice
+
 	// Domain indices
 	ReplaceNodeDomainsIndex(idx map[string]map[string]struct{})
 	GetNodeDomainsIndex() map[string]map[string]struct{}
 	GetNodeDomainsForNodes(nodeIDs []string) (map[string]map[string]struct{}, []string)
 
+	// Dependency index
+	SetVirtualServiceDependencies(vs helpers.NamespacedName, deps map[Dependency]struct{})
+	GetVirtualServiceDependencies(vs helpers.NamespacedName) []Dependency
+	GetDependentVirtualServices(deps ...Dependency) []helpers.NamespacedName
+	GetReferencingVirtualServices(deps ...Dependency) []helpers.NamespacedName
+
 	// Snapshot operations - returns Store interface instead of concrete type
 	Copy() Store
 
@@ -13,6 +13,7 @@ import (
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/utils"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -59,13 +60,29 @@ type OptimizedStore struct {
 	tracings      map[helpers.NamespacedName]*v1alpha1.Tracing
 	tracingsByUID map[string]*v1alpha1.Tracing
 
+	endpoints      map[helpers.NamespacedName]*v1alpha1.Endpoint
+	endpointSlices map[helpers.NamespacedName]*discoveryv1.EndpointSlice
+
+	runtimes map[helpers.NamespacedName]*v1alpha1.Runtime
+
+	trafficSplits map[helpers.NamespacedName]*v1alpha1.TrafficSplit
+
+	rateLimitPolicies map[helpers.NamespacedName]*v1alpha1.RateLimitPolicy
+
 	// Additional indices
 	specClusters       map[string]*v1alpha1.Cluster
+	specEndpoints      map[string]*v1alpha1.Endpoint
 	domainSecretsIndex DomainSecretsIndex
 	nodeDomains        map[string]map[string]struct{}
 
+	// EndpointSlices by the Service they belong to
+	serviceEndpointSlices map[helpers.NamespacedName]map[helpers.NamespacedName]*discoveryv1.EndpointSlice
+
 	// Status storage - separate from VS objects for immutability
 	vsStatusStorage *StatusStorage
+
+	// Objects each VirtualService was built from, maintained by the updater
+	vsDependencies *DependencyIndex
 }
 
 // NewOptimizedStore creates a new truly optimized store
@@ -89,6 +106,9 @@ func NewOptimizedStore() Store {
 		// Status storage
 		vsStatusStorage: NewStatusStorage(),
 
+		// Dependency index
+		vsDependencies: NewDependencyIndex(),
+
 		// All resource types
 		virtualServiceTemplates:      make(map[helpers.NamespacedName]*v1alpha1.VirtualServiceTemplate, 100),
 		virtualServiceTemplatesByUID: make(map[string]*v1alpha1.VirtualServiceTemplate, 100),
@@ -110,10 +130,21 @@ func NewOptimizedStore() Store {
 		tracings:      make(map[helpers.NamespacedName]*v1alpha1.Tracing, 50),
 		tracingsByUID: make(map[string]*v1alpha1.Tracing, 50),
 
+		endpoints:      make(map[helpers.NamespacedName]*v1alpha1.Endpoint, 100),
+		endpointSlices: make(map[helpers.NamespacedName]*discoveryv1.EndpointSlice, 500),
+
+		runtimes: make(map[helpers.NamespacedName]*v1alpha1.Runtime, 50),
+
+		trafficSplits: make(map[helpers.NamespacedName]*v1alpha1.TrafficSplit, 50),
+
+		rateLimitPolicies: make(map[helpers.NamespacedName]*v1alpha1.RateLimitPolicy, 50),
+
 		// Additional indices
-		specClusters:       make(map[string]*v1alpha1.Cluster, 500),
-		domainSecretsIndex: NewDomainSecretsIndex(200),
-		nodeDomains:        make(map[string]map[string]struct{}, 100),
+		specClusters:          make(map[string]*v1alpha1.Cluster, 500),
+		specEndpoints:         make(map[string]*v1alpha1.Endpoint, 100),
+		domainSecretsIndex:    NewDomainSecretsIndex(200),
+		nodeDomains:           make(map[string]map[string]struct{}, 100),
+		serviceEndpointSlices: make(map[helpers.NamespacedName]map[helpers.NamespacedName]*discoveryv1.EndpointSlice, 200),
 	}
 }
 
@@ -172,6 +203,7 @@ func (s *OptimizedStore) DeleteVirtualService(name helpers.NamespacedName) {
 		s.removeFromTemplateIndex(vs)
 		// Clean up status storage
 		s.DeleteVirtualServiceStatus(name)
+		s.vsDependencies.Delete(name)
 	}
 }
 
@@ -271,13 +303,28 @@ func (s *OptimizedStore) Copy() Store {
 		tracings:      make(map[helpers.NamespacedName]*v1alpha1.Tracing, len(s.tracings)),
 		tracingsByUID: make(map[string]*v1alpha1.Tracing, len(s.tracingsByUID)),
 
+		endpoints:      make(map[helpers.NamespacedName]*v1alpha1.Endpoint, len(s.endpoints)),
+		endpointSlices: make(map[helpers.NamespacedName]*discoveryv1.EndpointSlice, len(s.endpointSlices)),
+
+		runtimes: make(map[helpers.NamespacedName]*v1alpha1.Runtime, len(s.runtimes)),
+
+		trafficSplits: make(map[helpers.NamespacedName]*v1alpha1.TrafficSplit, len(s.trafficSplits)),
+
+		rateLimitPolicies: make(map[helpers.NamespacedName]*v1alpha1.RateLimitPolicy, len(s.rateLimitPolicies)),
+
 		// Additional indices
 		specClusters:       make(map[string]*v1alpha1.Cluster, len(s.specClusters)),
+		specEndpoints:      make(map[string]*v1alpha1.Endpoint, len(s.specEndpoints)),
 		domainSecretsIndex: NewDomainSecretsIndex(len(s.domainSecretsIndex)),
 		nodeDomains:        make(map[string]map[string]struct{}, len(s.nodeDomains)),
+		serviceEndpointSlices: make(
+			map[helpers.NamespacedName]map[helpers.NamespacedName]*discoveryv1.EndpointSlice, len(s.serviceEndpointSlices)),
 
 		// Copy status storage
 		vsStatusStorage: s.vsStatusStorage.Copy(),
+
+		// Copy dependency index
+		vsDependencies: s.vsDependencies.Copy(),
 	}
 
 	// Copy VirtualServices - shallow copy is safe with immutable pattern
@@ -361,10 +408,43 @@ func (s *OptimizedStore) Copy() Store {
 		newStore.tracingsByUID[k] = v
 	}
 
+	// Copy Endpoints and EndpointSlices
+	for k, v := range s.endpoints {
+		newStore.endpoints[k] = v
+	}
+	for k, v := range s.endpointSlices {
+		newStore.endpointSlices[k] = v
+	}
+
+	// Copy Runtimes
+	for k, v := range s.runtimes {
+		newStore.runtimes[k] = v
+	}
+
+	// Copy TrafficSplits
+	for k, v := range s.trafficSplits {
+		newStore.trafficSplits[k] = v
+	}
+
+	// Copy RateLimitPolicies
+	for k, v := range s.rateLimitPolicies {
+		newStore.rateLimitPolicies[k] = v
+	}
+
 	// Copy additional indices
 	for k, v := range s.specClusters {
 		newStore.specClusters[k] = v
 	}
+	for k, v := range s.specEndpoints {
+		newStore.specEndpoints[k] = v
+	}
+	for service, slices := range s.serviceEndpointSlices {
+		newSlices := make(map[helpers.NamespacedName]*discoveryv1.EndpointSlice, len(slices))
+		for nn, slice := range slices {
+			newSlices[nn] = slice
+		}
+		newStore.serviceEndpointSlices[service] = newSlices
+	}
 
 	// Deep copy domainSecretsIndex
 	for domain, entries := range s.domainSecretsIndex {
@@ -464,6 +544,11 @@ type resourceResult struct {
 	policies    []v1alpha1.Policy
 	tracings    []v1alpha1.Tracing
 	secrets     []corev1.Secret
+	endpoints   []v1alpha1.Endpoint
+	slices      []discoveryv1.EndpointSlice
+	runtimes    []v1alpha1.Runtime
+	splits      []v1alpha1.TrafficSplit
+	rlPolicies  []v1alpha1.RateLimitPolicy
 }
 
 // loadResourcesConcurrently loads all resources from Kubernetes in parallel.
@@ -593,6 +678,61 @@ func (s *OptimizedStore) loadResourcesConcurrently(ctx context.Context, cl clien
 		return nil
 	})
 
+	g.Go(func() error {
+		var list v1alpha1.EndpointList
+		if err := cl.List(ctx, &list); err != nil {
+			return fmt.Errorf("loading Endpoints: %w", err)
+		}
+		result.mu.Lock()
+		result.endpoints = list.Items
+		result.mu.Unlock()
+		return nil
+	})
+
+	g.Go(func() error {
+		var list v1alpha1.RuntimeList
+		if err := cl.List(ctx, &list); err != nil {
+			return fmt.Errorf("loading Runtimes: %w", err)
+		}
+		result.mu.Lock()
+		result.runtimes = list.Items
+		result.mu.Unlock()
+		return nil
+	})
+
+	g.Go(func() error {
+		var list v1alpha1.TrafficSplitList
+		if err := cl.List(ctx, &list); err != nil {
+			return fmt.Errorf("loading TrafficSplits: %w", err)
+		}
+		result.mu.Lock()
+		result.splits = list.Items
+		result.mu.Unlock()
+		return nil
+	})
+
+	g.Go(func() error {
+		var list v1alpha1.RateLimitPolicyList
+		if err := cl.List(ctx, &list); err != nil {
+			return fmt.Errorf("loading RateLimitPolicies: %w", err)
+		}
+		result.mu.Lock()
+		result.rlPolicies = list.Items
+		result.mu.Unlock()
+		return nil
+	})
+
+	g.Go(func() error {
+		var list discoveryv1.EndpointSliceList
+		if err := cl.List(ctx, &list, client.HasLabels{discoveryv1.LabelServiceName}); err != nil {
+			return fmt.Errorf("loading EndpointSlices: %w", err)
+		}
+		result.mu.Lock()
+		result.slices = list.Items
+		result.mu.Unlock()
+		return nil
+	})
+
 	// Wait for all goroutines to complete
 	if err := g.Wait(); err != nil {
 		return nil, err
@@ -747,6 +887,63 @@ func (s *OptimizedStore) FillFromKubernetes(ctx context.Context, cl client.Clien
 	// Update domain secrets map after all secrets are loaded
 	s.updateDomainSecretsMap()
 
+	// Process Endpoints
+	for i := range aggregated.endpoints {
+		endpoint := &aggregated.endpoints[i]
+		endpoint.Name = s.stringPool.Intern(endpoint.Name)
+		endpoint.Namespace = s.stringPool.InternNamespace(endpoint.Namespace)
+
+		key := helpers.NamespacedName{Namespace: endpoint.Namespace, Name: endpoint.Name}
+		s.endpoints[key] = endpoint
+		s.addSpecEndpoint(endpoint)
+	}
+
+	// Process EndpointSlices
+	for i := range aggregated.slices {
+		slice := &aggregated.slices[i]
+		slice.Name = s.stringPool.Intern(slice.Name)
+		slice.Namespace = s.stringPool.InternNamespace(slice.Namespace)
+
+		key := helpers.NamespacedName{Namespace: slice.Namespace, Name: slice.Name}
+		s.endpointSlices[key] = slice
+		if service, ok := EndpointSliceService(slice); ok {
+			if s.serviceEndpointSlices[service] == nil {
+				s.serviceEndpointSlices[service] = make(map[helpers.NamespacedName]*discoveryv1.EndpointSlice)
+			}
+			s.serviceEndpointSlices[service][key] = slice
+		}
+	}
+
+	// Process Runtimes
+	for i := range aggregated.runtimes {
+		rt := &aggregated.runtimes[i]
+		rt.Name = s.stringPool.Intern(rt.Name)
+		rt.Namespace = s.stringPool.InternNamespace(rt.Namespace)
+
+		key := helpers.NamespacedName{Namespace: rt.Namespace, Name: rt.Name}
+		s.runtimes[key] = rt
+	}
+
+	// Process TrafficSplits
+	for i := range aggregated.splits {
+		ts := &aggregated.splits[i]
+		ts.Name = s.stringPool.Intern(ts.Name)
+		ts.Namespace = s.stringPool.InternNamespace(ts.Namespace)
+
+		key := helpers.NamespacedName{Namespace: ts.Namespace, Name: ts.Name}
+		s.trafficSplits[key] = ts
+	}
+
+	// Process RateLimitPolicies
+	for i := range aggregated.rlPolicies {
+		p := &aggregated.rlPolicies[i]
+		p.Name = s.stringPool.Intern(p.Name)
+		p.Namespace = s.stringPool.InternNamespace(p.Namespace)
+
+		key := helpers.NamespacedName{Namespace: p.Namespace, Name: p.Name}
+		s.rateLimitPolicies[key] = p
+	}
+
 	return nil
 }
 
@@ -1603,10 +1800,16 @@ func (s *OptimizedStore) updateDomainSecretsMap() {
 
 // VirtualService Status methods
 
-// SetVirtualServiceStatus sets the status for a VirtualService without mutating the VS object
-func (s *OptimizedStore) SetVirtualServiceStatus(nn helpers.NamespacedName, invalid bool, message string) {
+// SetVirtualServiceStatus sets the status for a VirtualService without mutating the VS object.
+// The observed generation is the generation of the VirtualService the status was built from.
+func (s *OptimizedStore) SetVirtualServiceStatus(
+	nn helpers.NamespacedName,
+	invalid bool,
+	message string,
+	observedGeneration int64,
+) {
 	// No need to lock - StatusStorage has its own mutex
-	s.vsStatusStorage.SetStatus(nn, invalid, message)
+	s.vsStatusStorage.SetStatus(nn, invalid, message, observedGeneration)
 }
 
 // GetVirtualServiceStatus retrieves the status for a VirtualService
@@ -1635,3 +1838,26 @@ func (s *OptimizedStore) GetVirtualServiceWithStatus(nn helpers.NamespacedName)
 func (s *OptimizedStore) DeleteVirtualServiceStatus(nn helpers.NamespacedName) {
 	s.vsStatusStorage.DeleteStatus(nn)
 }
+
+// Dependency index methods
+
+// SetVirtualServiceDependencies replaces the objects the VirtualService was built from
+func (s *OptimizedStore) SetVirtualServiceDependencies(vs helpers.NamespacedName, deps map[Dependency]struct{}) {
+	// No need to lock - DependencyIndex has its own mutex
+	s.vsDependencies.Set(vs, deps)
+}
+
+// GetVirtualServiceDependencies returns the objects the VirtualService was built from
+func (s *OptimizedStore) GetVirtualServiceDependencies(vs helpers.NamespacedName) []Dependency {
+	return s.vsDependencies.Get(vs)
+}
+
+// GetDependentVirtualServices returns VirtualServices built from any of the given objects
+func (s *OptimizedStore) GetDependentVirtualServices(deps ...Dependency) []helpers.NamespacedName {
+	return s.vsDependencies.Dependents(deps...)
+}
+
+// GetReferencingVirtualServices returns VirtualServices built from any of the given objects by name
+func (s *OptimizedStore) GetReferencingVirtualServices(deps ...Dependency) []helpers.NamespacedName {
+	return s.vsDependencies.References(deps...)
+}
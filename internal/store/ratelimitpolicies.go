@@ -0,0 +1,61 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+)
+
+// RateLimitPolicy operations
+
+func (s *OptimizedStore) SetRateLimitPolicy(p *v1alpha1.RateLimitPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p.Name = s.stringPool.Intern(p.Name)
+	p.Namespace = s.stringPool.InternNamespace(p.Namespace)
+
+	s.rateLimitPolicies[helpers.NamespacedName{Namespace: p.Namespace, Name: p.Name}] = p
+}
+
+func (s *OptimizedStore) GetRateLimitPolicy(name helpers.NamespacedName) *v1alpha1.RateLimitPolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rateLimitPolicies[name]
+}
+
+func (s *OptimizedStore) DeleteRateLimitPolicy(name helpers.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.rateLimitPolicies, name)
+}
+
+func (s *OptimizedStore) MapRateLimitPolicies() map[helpers.NamespacedName]*v1alpha1.RateLimitPolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[helpers.NamespacedName]*v1alpha1.RateLimitPolicy, len(s.rateLimitPolicies))
+	for k, v := range s.rateLimitPolicies {
+		result[k] = v
+	}
+	return result
+}
+
+// GetRateLimitPoliciesForVirtualService returns the RateLimitPolicies limiting requests of the
+// VirtualService, sorted by name.
+func (s *OptimizedStore) GetRateLimitPoliciesForVirtualService(vs helpers.NamespacedName) []*v1alpha1.RateLimitPolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*v1alpha1.RateLimitPolicy
+	for _, p := range s.rateLimitPolicies {
+		if p.GetVirtualServiceNamespacedName() == vs {
+			result = append(result, p)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
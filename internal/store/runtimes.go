@@ -0,0 +1,41 @@
+package store
+
+import (
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+)
+
+// Runtime operations
+
+func (s *OptimizedStore) SetRuntime(rt *v1alpha1.Runtime) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rt.Name = s.stringPool.Intern(rt.Name)
+	rt.Namespace = s.stringPool.InternNamespace(rt.Namespace)
+
+	s.runtimes[helpers.NamespacedName{Namespace: rt.Namespace, Name: rt.Name}] = rt
+}
+
+func (s *OptimizedStore) GetRuntime(name helpers.NamespacedName) *v1alpha1.Runtime {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.runtimes[name]
+}
+
+func (s *OptimizedStore) DeleteRuntime(name helpers.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.runtimes, name)
+}
+
+func (s *OptimizedStore) MapRuntimes() map[helpers.NamespacedName]*v1alpha1.Runtime {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[helpers.NamespacedName]*v1alpha1.Runtime, len(s.runtimes))
+	for k, v := range s.runtimes {
+		result[k] = v
+	}
+	return result
+}
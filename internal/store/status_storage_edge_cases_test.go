@@ -40,7 +40,7 @@ func TestOptimizedStore_FirstTimeVSCreation(t *testing.T) {
 
 	// Step 4: rebuildSnapshots() sets status
 	// This is what happens in rebuildSnapshots after processing
-	store.SetVirtualServiceStatus(nn, false, "")
+	store.SetVirtualServiceStatus(nn, false, "", 0)
 
 	// Step 5: Controller calls GetVirtualServiceWithStatus
 	// This is what happens at line 76 of virtualservice_controller.go
@@ -70,7 +70,7 @@ func TestOptimizedStore_FirstTimeVSCreationWithError(t *testing.T) {
 	store.SetVirtualService(vs)
 
 	// Step 3: rebuildSnapshots() finds validation error
-	store.SetVirtualServiceStatus(nn, true, "invalid configuration: missing listener")
+	store.SetVirtualServiceStatus(nn, true, "invalid configuration: missing listener", 0)
 
 	// Step 4: Controller gets VS with error status
 	vsWithStatus := store.GetVirtualServiceWithStatus(nn)
@@ -94,7 +94,7 @@ func TestOptimizedStore_GetVirtualServiceWithStatus_Nil(t *testing.T) {
 	assert.Nil(t, vs, "Should return nil for nonexistent VS")
 
 	// Case 2: Status exists but VS doesn't (orphaned status)
-	store.SetVirtualServiceStatus(nn, true, "orphaned error")
+	store.SetVirtualServiceStatus(nn, true, "orphaned error", 0)
 	vs = store.GetVirtualServiceWithStatus(nn)
 	assert.Nil(t, vs, "Should return nil when VS doesn't exist even if status exists")
 }
@@ -152,7 +152,7 @@ func TestOptimizedStore_ControllerFlowSimulation(t *testing.T) {
 
 	// rebuildSnapshots() processes VS and sets status
 	// Simulating successful validation
-	store.SetVirtualServiceStatus(nn, false, "")
+	store.SetVirtualServiceStatus(nn, false, "", 0)
 
 	// Controller gets status to sync back to K8s
 	vsWithStatus := store.GetVirtualServiceWithStatus(nn)
@@ -165,7 +165,7 @@ func TestOptimizedStore_ControllerFlowSimulation(t *testing.T) {
 
 	// Something changes (e.g., referenced listener deleted)
 	// rebuildSnapshots() runs again and finds error
-	store.SetVirtualServiceStatus(nn, true, "referenced listener not found")
+	store.SetVirtualServiceStatus(nn, true, "referenced listener not found", 0)
 
 	// Controller detects change and syncs to K8s
 	vsWithStatus = store.GetVirtualServiceWithStatus(nn)
@@ -210,7 +210,7 @@ func TestOptimizedStore_ConcurrentCreationAndStatus(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		store.SetVirtualServiceStatus(nn, false, "validated")
+		store.SetVirtualServiceStatus(nn, false, "validated", 0)
 	}()
 
 	// Goroutine 3: Read with status
@@ -240,7 +240,7 @@ func TestOptimizedStore_StatusBeforeVS(t *testing.T) {
 	nn := helpers.NamespacedName{Namespace: "default", Name: "test-vs"}
 
 	// Set status before VS exists (shouldn't happen in practice, but let's test)
-	store.SetVirtualServiceStatus(nn, true, "premature error")
+	store.SetVirtualServiceStatus(nn, true, "premature error", 0)
 
 	// Verify GetVirtualService returns nil
 	assert.Nil(t, store.GetVirtualService(nn))
@@ -280,7 +280,7 @@ func TestOptimizedStore_UpdateExistingVSStatus(t *testing.T) {
 
 	// Create VS with initial status
 	store.SetVirtualService(vs)
-	store.SetVirtualServiceStatus(nn, false, "initial: ok")
+	store.SetVirtualServiceStatus(nn, false, "initial: ok", 0)
 
 	vsWithStatus1 := store.GetVirtualServiceWithStatus(nn)
 	require.NotNil(t, vsWithStatus1)
@@ -288,7 +288,7 @@ func TestOptimizedStore_UpdateExistingVSStatus(t *testing.T) {
 	assert.Equal(t, "initial: ok", vsWithStatus1.Status.Message)
 
 	// Update status
-	store.SetVirtualServiceStatus(nn, true, "updated: error found")
+	store.SetVirtualServiceStatus(nn, true, "updated: error found", 0)
 
 	vsWithStatus2 := store.GetVirtualServiceWithStatus(nn)
 	require.NotNil(t, vsWithStatus2)
@@ -21,7 +21,7 @@ func TestStatusStorage_BasicOperations(t *testing.T) {
 	nn := helpers.NamespacedName{Namespace: "default", Name: "test-vs"}
 
 	// Set status
-	storage.SetStatus(nn, true, "error message")
+	storage.SetStatus(nn, true, "error message", 0)
 
 	// Get status
 	status := storage.GetStatus(nn)
@@ -42,17 +42,18 @@ func TestStatusStorage_ImmutabilityPattern(t *testing.T) {
 	// Add VirtualService
 	vs := &v1alpha1.VirtualService{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "test-vs",
-			Namespace: "default",
-			UID:       types.UID("test-uid"),
+			Name:       "test-vs",
+			Namespace:  "default",
+			UID:        types.UID("test-uid"),
+			Generation: 2,
 		},
 	}
 	store.SetVirtualService(vs)
 
 	nn := helpers.NamespacedName{Namespace: "default", Name: "test-vs"}
 
-	// Set status separately
-	store.SetVirtualServiceStatus(nn, true, "validation error")
+	// Set status separately, built from an older generation
+	store.SetVirtualServiceStatus(nn, true, "validation error", 1)
 
 	// Get VS - should NOT have status
 	vsFromStore := store.GetVirtualService(nn)
@@ -65,6 +66,7 @@ func TestStatusStorage_ImmutabilityPattern(t *testing.T) {
 	require.NotNil(t, vsWithStatus)
 	assert.True(t, vsWithStatus.Status.Invalid, "VS with status should have status")
 	assert.Equal(t, "validation error", vsWithStatus.Status.Message)
+	assert.Equal(t, int64(1), vsWithStatus.Status.ObservedGeneration, "status should keep the built generation")
 
 	// Verify they are different objects
 	assert.NotSame(t, vsFromStore, vsWithStatus, "GetVirtualServiceWithStatus should return a copy")
@@ -86,7 +88,7 @@ func TestStatusStorage_ConcurrentAccess(t *testing.T) {
 				Namespace: "default",
 				Name:      fmt.Sprintf("vs-%d", i%10),
 			}
-			storage.SetStatus(nn, i%2 == 0, fmt.Sprintf("message-%d", i))
+			storage.SetStatus(nn, i%2 == 0, fmt.Sprintf("message-%d", i), 0)
 		}
 	}()
 
@@ -113,7 +115,7 @@ func TestStatusStorage_CopyIsolation(t *testing.T) {
 	original := NewStatusStorage()
 
 	nn := helpers.NamespacedName{Namespace: "default", Name: "test-vs"}
-	original.SetStatus(nn, false, "initial")
+	original.SetStatus(nn, false, "initial", 0)
 
 	// Copy
 	copy := original.Copy()
@@ -122,7 +124,7 @@ func TestStatusStorage_CopyIsolation(t *testing.T) {
 	assert.Equal(t, "initial", copy.GetStatus(nn).Message)
 
 	// Modify original
-	original.SetStatus(nn, true, "modified")
+	original.SetStatus(nn, true, "modified", 0)
 
 	// Verify copy is not affected
 	copyStatus := copy.GetStatus(nn)
@@ -149,7 +151,7 @@ func TestOptimizedStore_StatusStorageInCopy(t *testing.T) {
 	store.SetVirtualService(vs)
 
 	nn := helpers.NamespacedName{Namespace: "default", Name: "test-vs"}
-	store.SetVirtualServiceStatus(nn, true, "error in original")
+	store.SetVirtualServiceStatus(nn, true, "error in original", 0)
 
 	// Copy store
 	storeCopy := store.Copy().(*OptimizedStore)
@@ -160,7 +162,7 @@ func TestOptimizedStore_StatusStorageInCopy(t *testing.T) {
 	assert.Equal(t, "error in original", copyStatus.Message)
 
 	// Modify original status
-	store.SetVirtualServiceStatus(nn, false, "fixed in original")
+	store.SetVirtualServiceStatus(nn, false, "fixed in original", 0)
 
 	// Verify copy is not affected
 	copyStatus = storeCopy.GetVirtualServiceStatus(nn)
@@ -198,7 +200,7 @@ func TestOptimizedStore_NoMutationWithStatusStorage(t *testing.T) {
 	initialMessage := vsRef1.Status.Message
 
 	// Set status separately
-	store.SetVirtualServiceStatus(nn, true, "new error")
+	store.SetVirtualServiceStatus(nn, true, "new error", 0)
 
 	// Get VS again
 	vsRef2 := store.GetVirtualService(nn)
@@ -276,7 +278,7 @@ func TestOptimizedStore_StatusStorageRealWorldFlow(t *testing.T) {
 					Name:      fmt.Sprintf("vs-%d", j),
 				}
 				// SetVirtualServiceStatus does NOT mutate VS objects
-				store.SetVirtualServiceStatus(nn, i%2 == 0, fmt.Sprintf("status-%d", i))
+				store.SetVirtualServiceStatus(nn, i%2 == 0, fmt.Sprintf("status-%d", i), 0)
 			}
 			time.Sleep(1 * time.Millisecond)
 		}
@@ -301,7 +303,7 @@ func TestOptimizedStore_StatusStorageDeleteCleanup(t *testing.T) {
 	nn := helpers.NamespacedName{Namespace: "default", Name: "test-vs"}
 
 	store.SetVirtualService(vs)
-	store.SetVirtualServiceStatus(nn, true, "error")
+	store.SetVirtualServiceStatus(nn, true, "error", 0)
 
 	// Verify status exists
 	status := store.GetVirtualServiceStatus(nn)
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+)
+
+// TrafficSplit operations
+
+func (s *OptimizedStore) SetTrafficSplit(ts *v1alpha1.TrafficSplit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ts.Name = s.stringPool.Intern(ts.Name)
+	ts.Namespace = s.stringPool.InternNamespace(ts.Namespace)
+
+	s.trafficSplits[helpers.NamespacedName{Namespace: ts.Namespace, Name: ts.Name}] = ts
+}
+
+func (s *OptimizedStore) GetTrafficSplit(name helpers.NamespacedName) *v1alpha1.TrafficSplit {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.trafficSplits[name]
+}
+
+func (s *OptimizedStore) DeleteTrafficSplit(name helpers.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.trafficSplits, name)
+}
+
+func (s *OptimizedStore) MapTrafficSplits() map[helpers.NamespacedName]*v1alpha1.TrafficSplit {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[helpers.NamespacedName]*v1alpha1.TrafficSplit, len(s.trafficSplits))
+	for k, v := range s.trafficSplits {
+		result[k] = v
+	}
+	return result
+}
+
+// GetTrafficSplitsForVirtualService returns the TrafficSplits of routes of the VirtualService,
+// sorted by name.
+func (s *OptimizedStore) GetTrafficSplitsForVirtualService(vs helpers.NamespacedName) []*v1alpha1.TrafficSplit {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*v1alpha1.TrafficSplit
+	for _, ts := range s.trafficSplits {
+		if ts.GetVirtualServiceNamespacedName() == vs {
+			result = append(result, ts)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
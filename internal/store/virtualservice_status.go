@@ -11,6 +11,8 @@ import (
 type VirtualServiceStatus struct {
 	Invalid bool
 	Message string
+	// ObservedGeneration is the generation of the VirtualService the status was built from
+	ObservedGeneration int64
 }
 
 // StatusStorage stores VirtualService statuses separately from the VS objects themselves.
@@ -27,13 +29,14 @@ func NewStatusStorage() *StatusStorage {
 	}
 }
 
-// SetStatus sets the status for a VirtualService
-func (s *StatusStorage) SetStatus(nn helpers.NamespacedName, invalid bool, message string) {
+// SetStatus sets the status for a VirtualService built from the observed generation
+func (s *StatusStorage) SetStatus(nn helpers.NamespacedName, invalid bool, message string, observedGeneration int64) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.statuses[nn] = VirtualServiceStatus{
-		Invalid: invalid,
-		Message: message,
+		Invalid:            invalid,
+		Message:            message,
+		ObservedGeneration: observedGeneration,
 	}
 }
 
@@ -55,6 +58,7 @@ func (s *StatusStorage) ApplyStatusToVS(vs *v1alpha1.VirtualService) {
 	status := s.GetStatus(nn)
 	vs.Status.Invalid = status.Invalid
 	vs.Status.Message = status.Message
+	vs.Status.ObservedGeneration = status.ObservedGeneration
 }
 
 // DeleteStatus removes status for a VirtualService
@@ -86,7 +90,7 @@ func (s *StatusStorage) GetAllStatuses() map[helpers.NamespacedName]VirtualServi
 }
 
 // Copy creates a shallow copy of the status storage
-// Statuses are value types (struct with bool+string), so shallow copy is safe
+// Statuses are value types, so shallow copy is safe
 func (s *StatusStorage) Copy() *StatusStorage {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
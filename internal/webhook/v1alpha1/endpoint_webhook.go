@@ -0,0 +1,118 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var endpointlog = logf.Log.WithName("endpoint-resource")
+
+// SetupEndpointWebhookWithManager registers the webhook for Endpoint in the manager.
+func SetupEndpointWebhookWithManager(mgr ctrl.Manager, cacheUpdater *updater.CacheUpdater) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&envoyv1alpha1.Endpoint{}).
+		WithValidator(&EndpointCustomValidator{cacheUpdater: cacheUpdater}).
+		Complete()
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+//nolint:lll // kubebuilder marker must be on single line
+// +kubebuilder:webhook:path=/validate-envoy-kaasops-io-v1alpha1-endpoint,mutating=false,failurePolicy=fail,sideEffects=None,groups=envoy.kaasops.io,resources=endpoints,verbs=create;update,versions=v1alpha1,name=vendpoint-v1alpha1.envoy.kaasops.io,admissionReviewVersions=v1
+
+// EndpointCustomValidator struct is responsible for validating the Endpoint resource
+// when it is created or updated.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+type EndpointCustomValidator struct {
+	cacheUpdater *updater.CacheUpdater
+}
+
+var _ webhook.CustomValidator = &EndpointCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Endpoint.
+func (v *EndpointCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	endpoint, ok := obj.(*envoyv1alpha1.Endpoint)
+	if !ok {
+		return nil, fmt.Errorf("expected an Endpoint object but got %T", obj)
+	}
+	endpointlog.Info("Validation for Endpoint upon creation", "name", endpoint.GetName())
+
+	if err := v.validate(endpoint); err != nil {
+		return nil, err
+	}
+
+	endpointlog.Info("Endpoint is valid", "name", endpoint.GetName())
+
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Endpoint.
+func (v *EndpointCustomValidator) ValidateUpdate(
+	_ context.Context,
+	_, newObj runtime.Object,
+) (admission.Warnings, error) {
+	endpoint, ok := newObj.(*envoyv1alpha1.Endpoint)
+	if !ok {
+		return nil, fmt.Errorf("expected an Endpoint object for the newObj but got %T", newObj)
+	}
+	endpointlog.Info("Validation for Endpoint upon update", "name", endpoint.GetName())
+
+	if err := v.validate(endpoint); err != nil {
+		return nil, err
+	}
+
+	endpointlog.Info("Endpoint is valid", "name", endpoint.GetName())
+
+	return nil, nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Endpoint.
+func (v *EndpointCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the load assignment and that its cluster name is not used by another Endpoint
+func (v *EndpointCustomValidator) validate(endpoint *envoyv1alpha1.Endpoint) error {
+	claV3, err := endpoint.UnmarshalV3AndValidate()
+	if err != nil {
+		return err
+	}
+	if v.cacheUpdater == nil {
+		return nil
+	}
+	if val := v.cacheUpdater.GetSpecEndpoint(claV3.ClusterName); val != nil &&
+		(val.Name != endpoint.Name || val.Namespace != endpoint.Namespace) {
+		return fmt.Errorf("load assignment for cluster %s already exists in Endpoint %s/%s",
+			claV3.ClusterName, val.Namespace, val.Name)
+	}
+	return nil
+}
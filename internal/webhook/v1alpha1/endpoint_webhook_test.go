@@ -0,0 +1,50 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+)
+
+var _ = Describe("Endpoint Webhook", func() {
+	var (
+		obj       *envoyv1alpha1.Endpoint
+		validator EndpointCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &envoyv1alpha1.Endpoint{}
+		validator = EndpointCustomValidator{}
+	})
+
+	Context("When creating or updating Endpoint under Validating Webhook", func() {
+		It("Should deny creation without a cluster name", func() {
+			obj.Spec = &runtime.RawExtension{Raw: []byte(`{"endpoints":[]}`)}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should admit a valid load assignment", func() {
+			obj.Spec = &runtime.RawExtension{Raw: []byte(`{"cluster_name":"backend","endpoints":[{"lb_endpoints":[` +
+				`{"endpoint":{"address":{"socket_address":{"address":"10.0.0.1","port_value":80}}}}]}]}`)}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,103 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var ratelimitpolicylog = logf.Log.WithName("ratelimitpolicy-resource")
+
+// SetupRateLimitPolicyWebhookWithManager registers the webhook for RateLimitPolicy in the manager.
+func SetupRateLimitPolicyWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&envoyv1alpha1.RateLimitPolicy{}).
+		WithValidator(&RateLimitPolicyCustomValidator{}).
+		Complete()
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+//nolint:lll // kubebuilder marker must be on single line
+// +kubebuilder:webhook:path=/validate-envoy-kaasops-io-v1alpha1-ratelimitpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=envoy.kaasops.io,resources=ratelimitpolicies,verbs=create;update,versions=v1alpha1,name=vratelimitpolicy-v1alpha1.envoy.kaasops.io,admissionReviewVersions=v1
+
+// RateLimitPolicyCustomValidator struct is responsible for validating the RateLimitPolicy resource
+// when it is created or updated.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+type RateLimitPolicyCustomValidator struct{}
+
+var _ webhook.CustomValidator = &RateLimitPolicyCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type RateLimitPolicy.
+func (v *RateLimitPolicyCustomValidator) ValidateCreate(
+	_ context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	policy, ok := obj.(*envoyv1alpha1.RateLimitPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a RateLimitPolicy object but got %T", obj)
+	}
+	ratelimitpolicylog.Info("Validation for RateLimitPolicy upon creation", "name", policy.GetName())
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	ratelimitpolicylog.Info("RateLimitPolicy is valid", "name", policy.GetName())
+
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type RateLimitPolicy.
+func (v *RateLimitPolicyCustomValidator) ValidateUpdate(
+	_ context.Context,
+	_, newObj runtime.Object,
+) (admission.Warnings, error) {
+	policy, ok := newObj.(*envoyv1alpha1.RateLimitPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a RateLimitPolicy object for the newObj but got %T", newObj)
+	}
+	ratelimitpolicylog.Info("Validation for RateLimitPolicy upon update", "name", policy.GetName())
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	ratelimitpolicylog.Info("RateLimitPolicy is valid", "name", policy.GetName())
+
+	return nil, nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type RateLimitPolicy.
+func (v *RateLimitPolicyCustomValidator) ValidateDelete(
+	_ context.Context,
+	_ runtime.Object,
+) (admission.Warnings, error) {
+	return nil, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+)
+
+var _ = Describe("RateLimitPolicy Webhook", func() {
+	var (
+		obj       *envoyv1alpha1.RateLimitPolicy
+		validator RateLimitPolicyCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &envoyv1alpha1.RateLimitPolicy{}
+		obj.Namespace = "default"
+		obj.Spec = envoyv1alpha1.RateLimitPolicySpec{
+			VirtualService: "vs",
+			Service: envoyv1alpha1.RateLimitService{
+				Cluster: envoyv1alpha1.ResourceRef{Name: "ratelimit"},
+			},
+			Limits: []envoyv1alpha1.RateLimit{
+				{Name: "global", Requests: 1000, Unit: envoyv1alpha1.RateLimitUnitSecond},
+				{
+					Name:     "per-client",
+					Requests: 10,
+					Unit:     envoyv1alpha1.RateLimitUnitMinute,
+					By:       &envoyv1alpha1.RateLimitKey{RemoteAddress: true},
+				},
+			},
+		}
+		validator = RateLimitPolicyCustomValidator{}
+	})
+
+	Context("When creating or updating RateLimitPolicy under Validating Webhook", func() {
+		It("Should deny creation with duplicate limit names", func() {
+			obj.Spec.Limits[1].Name = obj.Spec.Limits[0].Name
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny creation with a key by both remote address and header", func() {
+			obj.Spec.Limits[1].By.Header = "x-api-key"
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should admit valid limits", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+	})
+})
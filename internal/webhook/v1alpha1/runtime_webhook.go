@@ -0,0 +1,108 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var runtimelog = logf.Log.WithName("runtime-resource")
+
+// SetupRuntimeWebhookWithManager registers the webhook for Runtime in the manager.
+func SetupRuntimeWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&envoyv1alpha1.Runtime{}).
+		WithValidator(&RuntimeCustomValidator{}).
+		Complete()
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+//nolint:lll // kubebuilder marker must be on single line
+// +kubebuilder:webhook:path=/validate-envoy-kaasops-io-v1alpha1-runtime,mutating=false,failurePolicy=fail,sideEffects=None,groups=envoy.kaasops.io,resources=runtimes,verbs=create;update,versions=v1alpha1,name=vruntime-v1alpha1.envoy.kaasops.io,admissionReviewVersions=v1
+
+// RuntimeCustomValidator struct is responsible for validating the Runtime resource
+// when it is created or updated.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+type RuntimeCustomValidator struct{}
+
+var _ webhook.CustomValidator = &RuntimeCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Runtime.
+func (v *RuntimeCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	rt, ok := obj.(*envoyv1alpha1.Runtime)
+	if !ok {
+		return nil, fmt.Errorf("expected a Runtime object but got %T", obj)
+	}
+	runtimelog.Info("Validation for Runtime upon creation", "name", rt.GetName())
+
+	if err := v.validate(rt); err != nil {
+		return nil, err
+	}
+
+	runtimelog.Info("Runtime is valid", "name", rt.GetName())
+
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Runtime.
+func (v *RuntimeCustomValidator) ValidateUpdate(
+	_ context.Context,
+	_, newObj runtime.Object,
+) (admission.Warnings, error) {
+	rt, ok := newObj.(*envoyv1alpha1.Runtime)
+	if !ok {
+		return nil, fmt.Errorf("expected a Runtime object for the newObj but got %T", newObj)
+	}
+	runtimelog.Info("Validation for Runtime upon update", "name", rt.GetName())
+
+	if err := v.validate(rt); err != nil {
+		return nil, err
+	}
+
+	runtimelog.Info("Runtime is valid", "name", rt.GetName())
+
+	return nil, nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Runtime.
+func (v *RuntimeCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the runtime layer and that it is served to any node
+func (v *RuntimeCustomValidator) validate(rt *envoyv1alpha1.Runtime) error {
+	if _, err := rt.UnmarshalV3AndValidate(); err != nil {
+		return err
+	}
+	if len(rt.GetNodeIDs()) == 0 {
+		return fmt.Errorf("nodeIDs is required: set the %s annotation", envoyv1alpha1.AnnotationNodeIDs)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+)
+
+var _ = Describe("Runtime Webhook", func() {
+	var (
+		obj       *envoyv1alpha1.Runtime
+		validator RuntimeCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &envoyv1alpha1.Runtime{}
+		obj.Annotations = map[string]string{envoyv1alpha1.AnnotationNodeIDs: "node1"}
+		validator = RuntimeCustomValidator{}
+	})
+
+	Context("When creating or updating Runtime under Validating Webhook", func() {
+		It("Should deny creation without a layer name", func() {
+			obj.Spec = &runtime.RawExtension{Raw: []byte(`{"layer":{"upstream.use_http2":true}}`)}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny creation without node IDs", func() {
+			obj.Annotations = nil
+			obj.Spec = &runtime.RawExtension{Raw: []byte(`{"name":"rtds","layer":{"upstream.use_http2":true}}`)}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should admit a valid runtime layer", func() {
+			obj.Spec = &runtime.RawExtension{Raw: []byte(`{"name":"rtds","layer":{"upstream.use_http2":true}}`)}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,100 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var trafficsplitlog = logf.Log.WithName("trafficsplit-resource")
+
+// SetupTrafficSplitWebhookWithManager registers the webhook for TrafficSplit in the manager.
+func SetupTrafficSplitWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&envoyv1alpha1.TrafficSplit{}).
+		WithValidator(&TrafficSplitCustomValidator{}).
+		Complete()
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+//nolint:lll // kubebuilder marker must be on single line
+// +kubebuilder:webhook:path=/validate-envoy-kaasops-io-v1alpha1-trafficsplit,mutating=false,failurePolicy=fail,sideEffects=None,groups=envoy.kaasops.io,resources=trafficsplits,verbs=create;update,versions=v1alpha1,name=vtrafficsplit-v1alpha1.envoy.kaasops.io,admissionReviewVersions=v1
+
+// TrafficSplitCustomValidator struct is responsible for validating the TrafficSplit resource
+// when it is created or updated.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+type TrafficSplitCustomValidator struct{}
+
+var _ webhook.CustomValidator = &TrafficSplitCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type TrafficSplit.
+func (v *TrafficSplitCustomValidator) ValidateCreate(
+	_ context.Context,
+	obj runtime.Object,
+) (admission.Warnings, error) {
+	ts, ok := obj.(*envoyv1alpha1.TrafficSplit)
+	if !ok {
+		return nil, fmt.Errorf("expected a TrafficSplit object but got %T", obj)
+	}
+	trafficsplitlog.Info("Validation for TrafficSplit upon creation", "name", ts.GetName())
+
+	if err := ts.Validate(); err != nil {
+		return nil, err
+	}
+
+	trafficsplitlog.Info("TrafficSplit is valid", "name", ts.GetName())
+
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type TrafficSplit.
+func (v *TrafficSplitCustomValidator) ValidateUpdate(
+	_ context.Context,
+	_, newObj runtime.Object,
+) (admission.Warnings, error) {
+	ts, ok := newObj.(*envoyv1alpha1.TrafficSplit)
+	if !ok {
+		return nil, fmt.Errorf("expected a TrafficSplit object for the newObj but got %T", newObj)
+	}
+	trafficsplitlog.Info("Validation for TrafficSplit upon update", "name", ts.GetName())
+
+	if err := ts.Validate(); err != nil {
+		return nil, err
+	}
+
+	trafficsplitlog.Info("TrafficSplit is valid", "name", ts.GetName())
+
+	return nil, nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type TrafficSplit.
+func (v *TrafficSplitCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
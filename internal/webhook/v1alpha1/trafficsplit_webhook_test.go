@@ -0,0 +1,60 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+)
+
+var _ = Describe("TrafficSplit Webhook", func() {
+	var (
+		obj       *envoyv1alpha1.TrafficSplit
+		validator TrafficSplitCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &envoyv1alpha1.TrafficSplit{}
+		obj.Namespace = "default"
+		obj.Spec = envoyv1alpha1.TrafficSplitSpec{
+			VirtualService: "vs",
+			Route:          "api",
+			Stable:         envoyv1alpha1.ResourceRef{Name: "api-stable"},
+			Canary:         envoyv1alpha1.ResourceRef{Name: "api-canary"},
+			Steps:          []envoyv1alpha1.TrafficSplitStep{{Weight: 5}, {Weight: 25}, {Weight: 100}},
+		}
+		validator = TrafficSplitCustomValidator{}
+	})
+
+	Context("When creating or updating TrafficSplit under Validating Webhook", func() {
+		It("Should deny creation with the same stable and canary clusters", func() {
+			obj.Spec.Canary = obj.Spec.Stable
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny creation with a weight greater than 100", func() {
+			obj.Spec.Steps[1].Weight = 125
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should admit a valid schedule", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+	})
+})
@@ -178,8 +178,12 @@ func (v *VirtualServiceCustomValidator) validateVirtualService(
 	vs *envoyv1alpha1.VirtualService,
 	prevVS *envoyv1alpha1.VirtualService,
 ) error {
-	if len(vs.GetNodeIDs()) == 0 {
-		return fmt.Errorf("nodeIDs is required")
+	selector, err := vs.GetNodeSelector()
+	if err != nil {
+		return err
+	}
+	if len(vs.GetNodeIDs()) == 0 && selector == nil {
+		return fmt.Errorf("nodeIDs is required unless a node selector is set")
 	}
 
 	// Validate tracing fields using a pure helper (XOR + existence check)
@@ -187,6 +191,14 @@ func (v *VirtualServiceCustomValidator) validateVirtualService(
 		return err
 	}
 
+	// The local rate limit of a VirtualService built from a template is only complete once
+	// filled from it, which is validated by the dry-run
+	if vs.Spec.Template == nil {
+		if err := vs.Spec.LocalRateLimit.Validate(); err != nil {
+			return fmt.Errorf("spec.localRateLimit: %w", err)
+		}
+	}
+
 	// Apply timeout for dry-run path (light or heavy)
 	ctxTO, cancel := context.WithTimeout(ctx, v.getDryRunTimeout())
 	defer cancel()
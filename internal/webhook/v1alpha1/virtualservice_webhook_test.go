@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	envoyv1alpha1 "github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/updater"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // stubUpdater implements vsUpdater for tests.
@@ -132,6 +134,46 @@ func TestVirtualServiceWebhook_LightCoverageMiss_FallbackHeavyOK(t *testing.T) {
 	}
 }
 
+func TestVirtualServiceWebhook_LocalRateLimit(t *testing.T) {
+	v := &VirtualServiceCustomValidator{
+		Client:  nil,
+		updater: &stubUpdater{},
+		Config:  WebhookConfig{DryRunTimeoutMS: 800},
+	}
+	vs := makeVS([]string{"n"})
+	vs.Spec.LocalRateLimit = &envoyv1alpha1.LocalRateLimitSpec{
+		LocalRateLimitBucket: envoyv1alpha1.LocalRateLimitBucket{
+			Tokens:       100,
+			FillInterval: &metav1.Duration{Duration: time.Second},
+		},
+		Routes: map[string]envoyv1alpha1.LocalRateLimitBucket{"api": {Tokens: 10}},
+	}
+	if _, err := v.ValidateCreate(context.Background(), vs); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+
+	vs.Spec.LocalRateLimit.Routes["api"] = envoyv1alpha1.LocalRateLimitBucket{
+		FillInterval: &metav1.Duration{Duration: 10 * time.Millisecond},
+	}
+	_, err := v.ValidateCreate(context.Background(), vs)
+	if err == nil || !contains(err.Error(), "spec.localRateLimit: route api: fillInterval must be at least 50ms") {
+		t.Fatalf("unexpected: %v", err)
+	}
+
+	// Fields may be left to the template
+	vs.Spec.LocalRateLimit = &envoyv1alpha1.LocalRateLimitSpec{
+		Routes: map[string]envoyv1alpha1.LocalRateLimitBucket{"api": {Tokens: 10}},
+	}
+	_, err = v.ValidateCreate(context.Background(), vs)
+	if err == nil || !contains(err.Error(), "route api: fillInterval is required") {
+		t.Fatalf("unexpected: %v", err)
+	}
+	vs.Spec.Template = &envoyv1alpha1.ResourceRef{Name: "defaults"}
+	if _, err := v.ValidateCreate(context.Background(), vs); err != nil {
+		t.Fatalf("expected success with a template, got %v", err)
+	}
+}
+
 // local helpers (duplicated minimal versions to keep imports tidy)
 func containsAll(s string, subs []string) bool {
 	for _, sub := range subs {
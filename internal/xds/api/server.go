@@ -45,6 +45,7 @@ import (
 	gincors "github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	xdscache "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	xdsclients "github.com/kaasops/envoy-xds-controller/internal/xds/clients"
 
 	"github.com/kaasops/envoy-xds-controller/internal/xds/api/v1/handlers"
 
@@ -71,6 +72,7 @@ type Config struct {
 
 type Client struct {
 	Cache    *xdscache.SnapshotCache
+	Clients  *xdsclients.Registry
 	logger   *zap.Logger
 	devMode  bool
 	fWatcher *filewatcher.FileWatcher
@@ -80,6 +82,7 @@ type Client struct {
 
 func New(
 	cache *xdscache.SnapshotCache,
+	clients *xdsclients.Registry,
 	cfg *Config,
 	logger *zap.Logger,
 	devMode bool,
@@ -107,6 +110,7 @@ func New(
 
 	return &Client{
 		Cache:    cache,
+		Clients:  clients,
 		mu:       mu,
 		cfg:      cfg,
 		logger:   logger,
@@ -145,7 +149,7 @@ func (c *Client) Run(port int, cacheAPIScheme, cacheAPIAddr string) error {
 		server.Use(authMiddleware.HandlerFunc)
 	}
 
-	handlers.RegisterRoutes(server, c.Cache)
+	handlers.RegisterRoutes(server, c.Cache, c.Clients)
 
 	// Register swagger
 	docs.SwaggerInfo.Schemes = []string{cacheAPIScheme}
@@ -245,3 +249,8 @@ func (c *Client) GetAccessGroups() []string {
 	defer c.mu.RUnlock()
 	return c.cfg.StaticResources.AccessGroups
 }
+
+// ListClients returns the Envoy clients connected to this replica, compared with the current snapshots
+func (c *Client) ListClients() []xdsclients.Info {
+	return c.Clients.List(c.Cache.GetVersion)
+}
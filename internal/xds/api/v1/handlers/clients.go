@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/api/v1/middlewares"
+	xdsclients "github.com/kaasops/envoy-xds-controller/internal/xds/clients"
+)
+
+// getClients retrieves the Envoy clients connected to this replica with the state of their subscriptions.
+// Clients that have not acknowledged the current snapshot version of a subscribed type are stale.
+// @Summary Get connected Envoy clients
+// @Tags clients
+// @Accept json
+// @Produce json
+// @Param node_id query string false "Node ID"
+// @Param stale query bool false "Only stale clients"
+// @Success 200 {array} xdsclients.Info
+// @Router /api/v1/clients [get]
+func (h *handler) getClients(ctx *gin.Context) {
+	nodeID := ctx.Query(nodeIDParamName)
+	onlyStale := ctx.Query("stale") == "true"
+	available, restricted := ctx.Get(middlewares.AvailableNodeIDs)
+
+	result := make([]xdsclients.Info, 0)
+	for _, info := range h.clients.List(h.cache.GetVersion) {
+		if nodeID != "" && info.NodeID != nodeID {
+			continue
+		}
+		if onlyStale && !info.Stale {
+			continue
+		}
+		if restricted {
+			if _, ok := available.(map[string]struct{})[info.NodeID]; !ok {
+				continue
+			}
+		}
+		result = append(result, info)
+	}
+	ctx.JSON(200, result)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	xdscache "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	xdsclients "github.com/kaasops/envoy-xds-controller/internal/xds/clients"
 )
 
 // @version 1.0
@@ -19,6 +20,7 @@ const overviewCacheTTL = 30 * time.Second
 
 type handler struct {
 	cache         *xdscache.SnapshotCache
+	clients       *xdsclients.Registry
 	overviewCache *OverviewCache
 }
 
@@ -26,9 +28,10 @@ var (
 	version = "/api/v1"
 )
 
-func RegisterRoutes(r *gin.Engine, cache *xdscache.SnapshotCache) {
+func RegisterRoutes(r *gin.Engine, cache *xdscache.SnapshotCache, clients *xdsclients.Registry) {
 	h := &handler{
 		cache:         cache,
+		clients:       clients,
 		overviewCache: NewOverviewCache(overviewCacheTTL),
 	}
 
@@ -36,8 +39,12 @@ func RegisterRoutes(r *gin.Engine, cache *xdscache.SnapshotCache) {
 
 	routes.GET("/nodeIDs", h.getNodeIDs)
 	routes.GET("/nodeIDs/versions", h.getNodeIDsWithResourceVersions)
+	routes.GET("/nodeIDs/health", h.getNodeIDsHealth)
 	routes.GET("/resourceVersions", h.getResourceVersions)
 
+	// ********** Get connected clients **********
+	routes.GET("/clients", h.getClients)
+
 	// ********** Get Listeners **********
 	// Get Listeners
 	routes.GET("/listeners", h.getListeners)
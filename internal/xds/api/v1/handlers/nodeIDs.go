@@ -4,6 +4,8 @@ import (
 	"sort"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/api/v1/middlewares"
+	xdscache "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
 )
 
 type nodeIDWithVersions struct {
@@ -76,3 +78,31 @@ func (h *handler) getResourceVersions(ctx *gin.Context) {
 
 	ctx.JSON(200, versions)
 }
+
+// getNodeIDsHealth retrieves the outcome of the latest snapshot update per node ID.
+// Unhealthy nodes keep serving their previous snapshot.
+// @Summary Get snapshot health of node IDs
+// @Tags nodeid
+// @Accept json
+// @Produce json
+// @Param node_id query string false "Node ID"
+// @Success 200 {array} xdscache.NodeHealth
+// @Router /api/v1/nodeIDs/health [get]
+func (h *handler) getNodeIDsHealth(ctx *gin.Context) {
+	nodeID := ctx.Query(nodeIDParamName)
+	available, restricted := ctx.Get(middlewares.AvailableNodeIDs)
+
+	result := make([]xdscache.NodeHealth, 0)
+	for _, health := range h.cache.ListNodeHealth() {
+		if nodeID != "" && health.NodeID != nodeID {
+			continue
+		}
+		if restricted {
+			if _, ok := available.(map[string]struct{})[health.NodeID]; !ok {
+				continue
+			}
+		}
+		result = append(result, health)
+	}
+	ctx.JSON(200, result)
+}
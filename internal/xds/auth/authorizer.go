@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/kaasops/envoy-xds-controller/internal/filewatcher"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/yaml"
+)
+
+// Rule allows clients with a matching identity to request configuration of matching node IDs.
+// Patterns may contain '*', matching any sequence of characters including '/'.
+type Rule struct {
+	// Identities are matched against URI SANs, such as SPIFFE IDs, and DNS SANs of the client certificate
+	Identities []string `json:"identities"`
+	// NodeIDs are the node IDs the matching clients may use
+	NodeIDs []string `json:"nodeIDs"`
+}
+
+// Policy binds client certificate identities to node IDs. A node ID is authorised
+// if any rule matches both an identity of the client and the node ID.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Allows reports whether a client with the given identities may use the node ID
+func (p *Policy) Allows(identities []string, nodeID string) bool {
+	for _, rule := range p.Rules {
+		if matchAny(rule.NodeIDs, nodeID) && anyMatches(rule.Identities, identities) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadPolicy reads a policy from a YAML or JSON file
+func LoadPolicy(file string) (*Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read xDS authorization policy: %w", err)
+	}
+	var policy Policy
+	if err := yaml.UnmarshalStrict(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse xDS authorization policy: %w", err)
+	}
+	for i, rule := range policy.Rules {
+		if len(rule.Identities) == 0 || len(rule.NodeIDs) == 0 {
+			return nil, fmt.Errorf("rule %d of xDS authorization policy must have identities and nodeIDs", i)
+		}
+	}
+	return &policy, nil
+}
+
+type streamAuth struct {
+	identities []string
+	// nodeID is the last node ID authorised on the stream
+	nodeID string
+}
+
+// Authorizer authenticates xDS streams by their verified client certificates and
+// authorises the node IDs they request. Without a policy, any client with a verified
+// certificate may use any node ID.
+type Authorizer struct {
+	log logr.Logger
+
+	mu      sync.RWMutex
+	policy  *Policy
+	streams map[int64]*streamAuth
+}
+
+// NewAuthorizer returns an authorizer without a policy
+func NewAuthorizer(log logr.Logger) *Authorizer {
+	return &Authorizer{
+		log:     log,
+		streams: make(map[int64]*streamAuth),
+	}
+}
+
+// SetPolicy replaces the policy. Node IDs already authorised on open streams are checked again.
+func (a *Authorizer) SetPolicy(policy *Policy) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.policy = policy
+	for _, stream := range a.streams {
+		stream.nodeID = ""
+	}
+}
+
+// WatchPolicy loads the policy from the file and reloads it when the file changes.
+// A policy that fails to load on reload keeps the previous one.
+func (a *Authorizer) WatchPolicy(file string, fw *filewatcher.FileWatcher) error {
+	policy, err := LoadPolicy(file)
+	if err != nil {
+		return err
+	}
+	a.SetPolicy(policy)
+	return fw.Add(file, func(string) {
+		policy, err := LoadPolicy(file)
+		if err != nil {
+			a.log.Error(err, "failed to reload xDS authorization policy, keeping previous one", "file", file)
+			return
+		}
+		a.SetPolicy(policy)
+		a.log.Info("xDS authorization policy reloaded", "file", file, "rules", len(policy.Rules))
+	})
+}
+
+// OnStreamOpen records the identities of the client. Streams without a verified client certificate are rejected.
+func (a *Authorizer) OnStreamOpen(ctx context.Context, streamID int64) error {
+	identities, err := peerIdentities(ctx)
+	if err != nil {
+		unauthorizedStreams.WithLabelValues(reasonUnauthenticated).Inc()
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	a.mu.Lock()
+	a.streams[streamID] = &streamAuth{identities: identities}
+	a.mu.Unlock()
+	return nil
+}
+
+// OnStreamClosed forgets the stream
+func (a *Authorizer) OnStreamClosed(streamID int64) {
+	a.mu.Lock()
+	delete(a.streams, streamID)
+	a.mu.Unlock()
+}
+
+// Authorize checks that the client of the stream may use the node ID
+func (a *Authorizer) Authorize(streamID int64, nodeID string) error {
+	a.mu.RLock()
+	stream, ok := a.streams[streamID]
+	authorised := ok && stream.nodeID != "" && stream.nodeID == nodeID
+	a.mu.RUnlock()
+	if authorised {
+		return nil
+	}
+	if !ok {
+		unauthorizedStreams.WithLabelValues(reasonUnauthenticated).Inc()
+		return status.Error(codes.Unauthenticated, "stream is not authenticated")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.authorize(stream.identities, nodeID); err != nil {
+		return err
+	}
+	stream.nodeID = nodeID
+	return nil
+}
+
+// AuthorizeContext checks that the client of a single request may use the node ID
+func (a *Authorizer) AuthorizeContext(ctx context.Context, nodeID string) error {
+	identities, err := peerIdentities(ctx)
+	if err != nil {
+		unauthorizedStreams.WithLabelValues(reasonUnauthenticated).Inc()
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.authorize(identities, nodeID)
+}
+
+func (a *Authorizer) authorize(identities []string, nodeID string) error {
+	if a.policy == nil || a.policy.Allows(identities, nodeID) {
+		return nil
+	}
+	unauthorizedStreams.WithLabelValues(reasonNodeID).Inc()
+	return status.Errorf(codes.PermissionDenied, "client %v is not allowed to use node ID %q", identities, nodeID)
+}
+
+// peerIdentities returns the URI and DNS SANs of the verified client certificate
+func peerIdentities(ctx context.Context) ([]string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return nil, errors.New("client certificate required")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil, errors.New("client certificate required")
+	}
+	return certificateIdentities(tlsInfo.State.VerifiedChains[0][0]), nil
+}
+
+func certificateIdentities(cert *x509.Certificate) []string {
+	identities := make([]string, 0, len(cert.URIs)+len(cert.DNSNames))
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+	identities = append(identities, cert.DNSNames...)
+	return identities
+}
+
+func anyMatches(patterns, values []string) bool {
+	for _, value := range values {
+		if matchAny(patterns, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if match(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// match matches a value against a pattern where '*' matches any sequence of characters
+func match(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+	return len(value) >= len(last) && strings.HasSuffix(value, last)
+}
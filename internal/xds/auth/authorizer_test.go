@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func peerContext(identities ...string) context.Context {
+	cert := &x509.Certificate{}
+	for _, identity := range identities {
+		if u, err := url.Parse(identity); err == nil && u.Scheme != "" {
+			cert.URIs = append(cert.URIs, u)
+		} else {
+			cert.DNSNames = append(cert.DNSNames, identity)
+		}
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+// TestAuthorizer_PolicyBindsIdentitiesToNodeIDs verifies that a stream may only use node IDs
+// bound to its certificate identities, and that streams without a certificate are rejected.
+func TestAuthorizer_PolicyBindsIdentitiesToNodeIDs(t *testing.T) {
+	a := NewAuthorizer(logr.Discard())
+	a.SetPolicy(&Policy{Rules: []Rule{{
+		Identities: []string{"spiffe://cluster.local/ns/team-a/*"},
+		NodeIDs:    []string{"team-a-*"},
+	}}})
+
+	if err := a.OnStreamOpen(context.Background(), 1); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected stream without certificate to be unauthenticated, got %v", err)
+	}
+
+	if err := a.OnStreamOpen(peerContext("spiffe://cluster.local/ns/team-a/sa/envoy"), 2); err != nil {
+		t.Fatalf("expected stream to open, got %v", err)
+	}
+	if err := a.Authorize(2, "team-a-gateway"); err != nil {
+		t.Errorf("expected node ID to be allowed, got %v", err)
+	}
+	if err := a.Authorize(2, "team-b-gateway"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected node ID of another team to be denied, got %v", err)
+	}
+
+	// A reloaded policy applies to open streams
+	a.SetPolicy(&Policy{Rules: []Rule{{Identities: []string{"other"}, NodeIDs: []string{"*"}}}})
+	if err := a.Authorize(2, "team-a-gateway"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected node ID to be denied after policy change, got %v", err)
+	}
+
+	a.OnStreamClosed(2)
+	if err := a.Authorize(2, "team-a-gateway"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected closed stream to be unauthenticated, got %v", err)
+	}
+
+	// Without a policy any verified client may use any node ID
+	a.SetPolicy(nil)
+	if err := a.AuthorizeContext(peerContext("envoy.example.com"), "any"); err != nil {
+		t.Errorf("expected verified client to be allowed without policy, got %v", err)
+	}
+}
+
+// TestLoadPolicy verifies parsing of policy files and rejection of incomplete rules
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+
+	policy, err := LoadPolicy(write("valid.yaml", `
+rules:
+  - identities: ["spiffe://cluster.local/ns/edge/*"]
+    nodeIDs: ["edge", "edge-*"]
+`))
+	if err != nil {
+		t.Fatalf("expected policy to load, got %v", err)
+	}
+	if !policy.Allows([]string{"spiffe://cluster.local/ns/edge/sa/envoy"}, "edge-1") {
+		t.Errorf("expected loaded policy to allow edge-1")
+	}
+
+	if _, err := LoadPolicy(write("incomplete.yaml", "rules:\n  - identities: [\"a\"]\n")); err == nil {
+		t.Errorf("expected rule without node IDs to be rejected")
+	}
+	if _, err := LoadPolicy(write("unknown.yaml", "rules:\n  - identity: a\n")); err == nil {
+		t.Errorf("expected unknown fields to be rejected")
+	}
+}
+
+// TestMatch verifies glob matching of identities and node IDs
+func TestMatch(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, value string
+		expected       bool
+	}{
+		{"node", "node", true},
+		{"node", "node-1", false},
+		{"node-*", "node-1", true},
+		{"*", "", true},
+		{"spiffe://td/ns/*/sa/envoy", "spiffe://td/ns/a/b/sa/envoy", true},
+		{"a*b*c", "abc", true},
+		{"a*b*c", "acb", false},
+		{"ab*ba", "aba", false},
+	} {
+		if got := match(tc.pattern, tc.value); got != tc.expected {
+			t.Errorf("match(%q, %q) = %v, expected %v", tc.pattern, tc.value, got, tc.expected)
+		}
+	}
+}
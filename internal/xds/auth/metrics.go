@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	reasonUnauthenticated = "unauthenticated"
+	reasonNodeID          = "node_id"
+)
+
+var unauthorizedStreams = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "exc",
+		Subsystem: "xds",
+		Name:      "unauthorized_requests_total",
+		Help:      "Total number of xDS streams and requests rejected by client authentication or node ID authorization.",
+	},
+	[]string{"reason"},
+)
+
+func init() {
+	ctrmetrics.Registry.MustRegister(unauthorizedStreams)
+}
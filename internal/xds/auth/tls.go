@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/kaasops/envoy-xds-controller/internal/filewatcher"
+)
+
+// TLSConfig configures TLS of the xDS server
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile enables client certificate verification against the given CA bundle
+	ClientCAFile string
+}
+
+// CertificateReloader serves the xDS server certificate and client CA bundle,
+// reloading them when the files change. A failed reload keeps the previous ones.
+type CertificateReloader struct {
+	cfg TLSConfig
+	log logr.Logger
+
+	mu        sync.RWMutex
+	tlsConfig *tls.Config
+}
+
+// NewCertificateReloader loads the certificates and watches their files for changes
+func NewCertificateReloader(cfg TLSConfig, fw *filewatcher.FileWatcher, log logr.Logger) (*CertificateReloader, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("certificate and key files are required")
+	}
+	r := &CertificateReloader{cfg: cfg, log: log}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	for _, file := range []string{cfg.CertFile, cfg.KeyFile, cfg.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		if err := fw.Add(file, r.onChange); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// TLSConfig returns a server TLS configuration always using the latest loaded certificates
+func (r *CertificateReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return r.tlsConfig, nil
+		},
+	}
+}
+
+func (r *CertificateReloader) onChange(file string) {
+	if err := r.reload(); err != nil {
+		r.log.Error(err, "failed to reload xDS server certificates, keeping previous ones", "file", file)
+		return
+	}
+	r.log.Info("xDS server certificates reloaded", "file", file)
+}
+
+func (r *CertificateReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load xDS server certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2"},
+	}
+	if r.cfg.ClientCAFile != "" {
+		data, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("no certificates found in client CA %s", r.cfg.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	r.mu.Lock()
+	r.tlsConfig = tlsConfig
+	r.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+)
+
+// generationHistory is the number of snapshots per node kept to resolve acknowledged versions
+const generationHistory = 32
+
+// Generation identifies a snapshot of a node: generations grow with every changed snapshot.
+type Generation struct {
+	Generation uint64
+	// Version of the snapshot, derived from the versions of all its resource types
+	Version  string
+	versions map[string]string
+}
+
+// recordGeneration adds the snapshot to the history of the node if any version changed.
+// Must be called with c.mu held.
+func (c *SnapshotCache) recordGeneration(nodeID string, snapshot cache.ResourceSnapshot) {
+	versions := make(map[string]string, types.UnknownType)
+	for i := 0; i < int(types.UnknownType); i++ {
+		typeURL, err := cache.GetResponseTypeURL(types.ResponseType(i))
+		if err != nil {
+			continue
+		}
+		if version := snapshot.GetVersion(typeURL); version != "" {
+			versions[typeURL] = version
+		}
+	}
+	version := SnapshotVersion(versions)
+
+	history := c.generations[nodeID]
+	var generation uint64 = 1
+	if len(history) > 0 {
+		last := history[len(history)-1]
+		if last.Version == version {
+			return
+		}
+		generation = last.Generation + 1
+	}
+	history = append(history, Generation{Generation: generation, Version: version, versions: versions})
+	if len(history) > generationHistory {
+		history = history[len(history)-generationHistory:]
+	}
+	c.generations[nodeID] = history
+}
+
+// GetGeneration returns the current snapshot generation of the node.
+func (c *SnapshotCache) GetGeneration(nodeID string) (Generation, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	history := c.generations[nodeID]
+	if len(history) == 0 {
+		return Generation{}, false
+	}
+	return history[len(history)-1], true
+}
+
+// GetAcknowledgedGeneration returns the latest generation of the node a client has all
+// resources of, given the versions it acknowledged per subscribed type URL. It returns
+// zero if an acknowledged version is unknown or older than the kept history.
+func (c *SnapshotCache) GetAcknowledgedGeneration(nodeID string, acked map[string]string) uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	history := c.generations[nodeID]
+	if len(history) == 0 {
+		return 0
+	}
+	result := history[len(history)-1].Generation
+	for typeURL, version := range acked {
+		// the client has the resources of the type as of the latest generation serving its version
+		var generation uint64
+		for i := len(history) - 1; i >= 0; i-- {
+			if history[i].versions[typeURL] == version {
+				generation = history[i].Generation
+				break
+			}
+		}
+		result = min(result, generation)
+	}
+	return result
+}
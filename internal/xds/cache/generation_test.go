@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+)
+
+func snapshotWithVersions(listeners, clusters string) *cachev3.Snapshot {
+	snapshot := &cachev3.Snapshot{}
+	snapshot.Resources[types.Listener] = cachev3.NewResources(listeners, nil)
+	snapshot.Resources[types.Cluster] = cachev3.NewResources(clusters, nil)
+	return snapshot
+}
+
+// TestAcknowledgedGeneration verifies that generations grow only with changed snapshots and
+// that a client is at the latest generation serving every version it acknowledged.
+func TestAcknowledgedGeneration(t *testing.T) {
+	ctx := context.Background()
+	c := NewSnapshotCache()
+	const nodeID = "node"
+
+	for _, snapshot := range []*cachev3.Snapshot{
+		snapshotWithVersions("l1", "c1"),
+		snapshotWithVersions("l1", "c1"), // unchanged, same generation
+		snapshotWithVersions("l2", "c1"),
+		snapshotWithVersions("l2", "c2"),
+	} {
+		if err := c.SetSnapshot(ctx, nodeID, snapshot); err != nil {
+			t.Fatalf("failed to set snapshot: %v", err)
+		}
+	}
+	current, ok := c.GetGeneration(nodeID)
+	if !ok || current.Generation != 3 {
+		t.Fatalf("expected generation 3, got %+v", current)
+	}
+
+	tests := []struct {
+		name     string
+		acked    map[string]string
+		expected uint64
+	}{
+		{"current", map[string]string{resourcev3.ListenerType: "l2", resourcev3.ClusterType: "c2"}, 3},
+		{"previous cluster", map[string]string{resourcev3.ListenerType: "l2", resourcev3.ClusterType: "c1"}, 2},
+		{"first", map[string]string{resourcev3.ListenerType: "l1", resourcev3.ClusterType: "c1"}, 1},
+		{"not acknowledged", map[string]string{resourcev3.ListenerType: "", resourcev3.ClusterType: "c2"}, 0},
+		{"unknown version", map[string]string{resourcev3.ListenerType: "l0"}, 0},
+		{"single type", map[string]string{resourcev3.ClusterType: "c2"}, 3},
+	}
+	for _, tt := range tests {
+		if got := c.GetAcknowledgedGeneration(nodeID, tt.acked); got != tt.expected {
+			t.Errorf("%s: expected generation %d, got %d", tt.name, tt.expected, got)
+		}
+	}
+
+	c.ClearSnapshot(nodeID)
+	if _, ok := c.GetGeneration(nodeID); ok {
+		t.Errorf("expected no generation after the snapshot was cleared")
+	}
+}
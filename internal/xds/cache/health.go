@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"sort"
+	"time"
+)
+
+// NodeHealth is the outcome of the latest snapshot update of a node.
+// A failing node keeps serving its previous snapshot.
+type NodeHealth struct {
+	NodeID              string     `json:"node_id"`
+	Healthy             bool       `json:"healthy"`
+	Error               string     `json:"error,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastAttempt         time.Time  `json:"last_attempt"`
+	LastSuccess         *time.Time `json:"last_success,omitempty"`
+}
+
+// SetNodeHealthy records a successful snapshot update of the node
+func (c *SnapshotCache) SetNodeHealthy(nodeID string) {
+	now := time.Now()
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	c.health[nodeID] = &NodeHealth{
+		NodeID:      nodeID,
+		Healthy:     true,
+		LastAttempt: now,
+		LastSuccess: &now,
+	}
+}
+
+// SetNodeFailed records a failed snapshot update of the node
+func (c *SnapshotCache) SetNodeFailed(nodeID string, err error) {
+	now := time.Now()
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+	h, ok := c.health[nodeID]
+	if !ok {
+		h = &NodeHealth{NodeID: nodeID}
+		c.health[nodeID] = h
+	}
+	h.Healthy = false
+	h.Error = err.Error()
+	h.ConsecutiveFailures++
+	h.LastAttempt = now
+}
+
+// GetNodeHealth returns the health of the node
+func (c *SnapshotCache) GetNodeHealth(nodeID string) (NodeHealth, bool) {
+	c.healthMu.RLock()
+	defer c.healthMu.RUnlock()
+	h, ok := c.health[nodeID]
+	if !ok {
+		return NodeHealth{}, false
+	}
+	return *h, true
+}
+
+// ListNodeHealth returns the health of all nodes sorted by node ID
+func (c *SnapshotCache) ListNodeHealth() []NodeHealth {
+	c.healthMu.RLock()
+	defer c.healthMu.RUnlock()
+	result := make([]NodeHealth, 0, len(c.health))
+	for _, h := range c.health {
+		result = append(result, *h)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].NodeID < result[j].NodeID
+	})
+	return result
+}
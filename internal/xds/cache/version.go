@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+// NewSnapshot creates a snapshot with versions derived from resource content. Every resource
+// gets its own version, which delta xDS uses to push only changed resources, and the version
+// of each type is derived from the versions of its resources. Versions do not depend on the
+// history of the controller: a restarted controller, or another replica, serves the same
+// versions for the same resources, so reconnecting Envoys are not sent them again.
+// Versions of resources and types equal to those of the previous snapshot, if any, are reused.
+func NewSnapshot(resources map[resourcev3.Type][]types.Resource, prev cache.ResourceSnapshot) (*cache.Snapshot, error) {
+	for typ := range resources {
+		if cache.GetResponseType(typ) == types.UnknownType {
+			return nil, errors.New("unknown resource type: " + typ)
+		}
+	}
+
+	snapshot := cache.Snapshot{VersionMap: make(map[string]map[string]string)}
+	for i := range snapshot.Resources {
+		typ, err := cache.GetResponseTypeURL(types.ResponseType(i))
+		if err != nil {
+			return nil, err
+		}
+		res := resources[typ]
+		versions := make(map[string]string, len(res))
+		snapshot.VersionMap[typ] = versions
+
+		var prevResources map[string]types.Resource
+		var prevVersions map[string]string
+		if prev != nil {
+			prevResources = prev.GetResources(typ)
+			prevVersions = prev.GetVersionMap(typ)
+		}
+		unchanged := prev != nil
+		for _, r := range res {
+			name := cache.GetResourceName(r)
+			if prevRes, ok := prevResources[name]; ok && proto.Equal(prevRes, r) {
+				if version, ok := prevVersions[name]; ok {
+					versions[name] = version
+					continue
+				}
+			} else {
+				unchanged = false
+			}
+			versions[name] = computeResourceHash(r)
+		}
+		// Resources shared by several VirtualServices are listed once per VirtualService
+		if len(versions) != len(prevResources) {
+			unchanged = false
+		}
+
+		// Types without resources are versioned too: Envoys only get a response to their
+		// initial request, sent without version, once the type has one
+		version := SnapshotVersion(versions)
+		if unchanged {
+			// keep the version of an unchanged type, even if the previous snapshot was versioned differently
+			version = prev.GetVersion(typ)
+		}
+		snapshot.Resources[i] = cache.NewResources(version, res)
+	}
+	return &snapshot, nil
+}
+
+// NewEmptySnapshot creates a snapshot without resources. Unlike an unversioned snapshot,
+// its types are versioned, so Envoys waiting for their first response are answered.
+func NewEmptySnapshot() *cache.Snapshot {
+	snapshot := cache.Snapshot{VersionMap: make(map[string]map[string]string)}
+	version := SnapshotVersion(nil)
+	for i := range snapshot.Resources {
+		typ, _ := cache.GetResponseTypeURL(types.ResponseType(i))
+		snapshot.VersionMap[typ] = make(map[string]string)
+		snapshot.Resources[i] = cache.NewResources(version, nil)
+	}
+	return &snapshot
+}
+
+// SnapshotVersion returns the version of a resource type from the versions of its resources
+func SnapshotVersion(versions map[string]string) string {
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(versions[name]))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"testing"
+
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// structBytes encodes a google.protobuf.Struct of string values with fields in the given order
+func structBytes(fields ...[2]string) []byte {
+	var b []byte
+	for _, f := range fields {
+		var value []byte
+		value = protowire.AppendTag(value, 3, protowire.BytesType) // string_value
+		value = protowire.AppendString(value, f[1])
+
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, f[0])
+		entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+		entry = protowire.AppendBytes(entry, value)
+
+		b = protowire.AppendTag(b, 1, protowire.BytesType) // fields
+		b = protowire.AppendBytes(b, entry)
+	}
+	return b
+}
+
+func listenerWithConfig(config []byte) *listenerv3.Listener {
+	return &listenerv3.Listener{
+		Name: "listener",
+		FilterChains: []*listenerv3.FilterChain{{
+			Filters: []*listenerv3.Filter{{
+				Name: "filter",
+				ConfigType: &listenerv3.Filter_TypedConfig{TypedConfig: &anypb.Any{
+					TypeUrl: "type.googleapis.com/google.protobuf.Struct",
+					Value:   config,
+				}},
+			}},
+		}},
+	}
+}
+
+// TestComputeResourceHash_CanonicalizesAny verifies that resources with equal Any values
+// encoded differently get the same hash, and different values a different one.
+func TestComputeResourceHash_CanonicalizesAny(t *testing.T) {
+	a := listenerWithConfig(structBytes([2]string{"a", "1"}, [2]string{"b", "2"}))
+	b := listenerWithConfig(structBytes([2]string{"b", "2"}, [2]string{"a", "1"}))
+	c := listenerWithConfig(structBytes([2]string{"a", "1"}, [2]string{"b", "3"}))
+
+	if computeResourceHash(a) != computeResourceHash(b) {
+		t.Errorf("expected equal hashes for differently encoded Any values")
+	}
+	if computeResourceHash(a) == computeResourceHash(c) {
+		t.Errorf("expected different hashes for different Any values")
+	}
+	if got := a.FilterChains[0].Filters[0].GetTypedConfig().Value; string(got) != string(structBytes([2]string{"a", "1"}, [2]string{"b", "2"})) {
+		t.Errorf("expected hashing to leave the resource unchanged")
+	}
+}
+
+// TestNewSnapshot_PerResourceVersions verifies that a change of one resource changes only
+// its version and the version of its type, keeping versions of the other resources.
+func TestNewSnapshot_PerResourceVersions(t *testing.T) {
+	resources := func(config []byte) map[resourcev3.Type][]types.Resource {
+		other := listenerWithConfig(structBytes([2]string{"a", "1"}))
+		other.Name = "other"
+		return map[resourcev3.Type][]types.Resource{
+			resourcev3.ListenerType: {listenerWithConfig(config), other},
+		}
+	}
+
+	prev, err := NewSnapshot(resources(structBytes([2]string{"a", "1"})), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	same, err := NewSnapshot(resources(structBytes([2]string{"a", "1"})), prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same.GetVersion(resourcev3.ListenerType) != prev.GetVersion(resourcev3.ListenerType) {
+		t.Errorf("expected version of unchanged listeners to stay")
+	}
+
+	changed, err := NewSnapshot(resources(structBytes([2]string{"a", "2"})), prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed.GetVersion(resourcev3.ListenerType) == prev.GetVersion(resourcev3.ListenerType) {
+		t.Errorf("expected version of changed listeners to change")
+	}
+	prevVersions, versions := prev.GetVersionMap(resourcev3.ListenerType), changed.GetVersionMap(resourcev3.ListenerType)
+	if versions["listener"] == prevVersions["listener"] {
+		t.Errorf("expected version of the changed listener to change")
+	}
+	if versions["other"] != prevVersions["other"] {
+		t.Errorf("expected version of the other listener %s to stay, got %s", prevVersions["other"], versions["other"])
+	}
+	// Removing a resource changes the version, even if another one is listed twice
+	listener := listenerWithConfig(structBytes([2]string{"a", "1"}))
+	removed, err := NewSnapshot(map[resourcev3.Type][]types.Resource{
+		resourcev3.ListenerType: {listener, listener},
+	}, prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed.GetVersion(resourcev3.ListenerType) == prev.GetVersion(resourcev3.ListenerType) {
+		t.Errorf("expected version of listeners to change when one is removed")
+	}
+}
+
+// TestNewSnapshot_VersionsEmptyTypes verifies that types without resources are versioned, so
+// initial requests of Envoys, which have no version, are answered.
+func TestNewSnapshot_VersionsEmptyTypes(t *testing.T) {
+	snapshot, err := NewSnapshot(map[resourcev3.Type][]types.Resource{
+		resourcev3.ListenerType: {listenerWithConfig(structBytes([2]string{"a", "1"}))},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range snapshot.Resources {
+		typeURL, err := cache.GetResponseTypeURL(types.ResponseType(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snapshot.GetVersion(typeURL) == "" {
+			t.Errorf("expected a version for %s", typeURL)
+		}
+	}
+	if snapshot.GetVersion(resourcev3.ClusterType) != NewEmptySnapshot().GetVersion(resourcev3.ClusterType) {
+		t.Errorf("expected empty types to be versioned like an empty snapshot")
+	}
+}
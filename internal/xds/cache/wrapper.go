@@ -8,8 +8,11 @@ import (
 	"sync"
 
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
 
 	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
@@ -23,12 +26,19 @@ type SnapshotCache struct {
 	cache.SnapshotCache
 	mu      sync.RWMutex
 	nodeIDs map[string]struct{}
+	// generations keeps the latest snapshots of each node to resolve acknowledged versions
+	generations map[string][]Generation
+
+	healthMu sync.RWMutex
+	health   map[string]*NodeHealth
 }
 
 func NewSnapshotCache() *SnapshotCache {
 	return &SnapshotCache{
 		SnapshotCache: cache.NewSnapshotCache(false, cache.IDHash{}, nil),
 		nodeIDs:       make(map[string]struct{}),
+		generations:   make(map[string][]Generation),
+		health:        make(map[string]*NodeHealth),
 	}
 }
 
@@ -39,7 +49,11 @@ func (c *SnapshotCache) SetSnapshot(ctx context.Context, nodeID string, snapshot
 	if err := c.validateCache(snapshot); err != nil {
 		return fmt.Errorf("snapshot is invalid: %w", err)
 	}
-	return c.SnapshotCache.SetSnapshot(ctx, nodeID, snapshot)
+	if err := c.SnapshotCache.SetSnapshot(ctx, nodeID, snapshot); err != nil {
+		return err
+	}
+	c.recordGeneration(nodeID, snapshot)
+	return nil
 }
 
 func (c *SnapshotCache) GetSnapshot(nodeID string) (cache.ResourceSnapshot, error) {
@@ -52,7 +66,12 @@ func (c *SnapshotCache) ClearSnapshot(nodeID string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	delete(c.nodeIDs, nodeID)
+	delete(c.generations, nodeID)
 	c.SnapshotCache.ClearSnapshot(nodeID)
+
+	c.healthMu.Lock()
+	delete(c.health, nodeID)
+	c.healthMu.Unlock()
 }
 
 func (c *SnapshotCache) GetNodeIDsAsMap() map[string]struct{} {
@@ -105,6 +124,21 @@ func (c *SnapshotCache) GetSecrets(nodeID string) ([]*tlsv3.Secret, error) {
 	return secrets, nil
 }
 
+func (c *SnapshotCache) GetEndpoints(nodeID string) ([]*endpointv3.ClusterLoadAssignment, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	snapshot, err := c.SnapshotCache.GetSnapshot(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	data := snapshot.GetResources(resourcev3.EndpointType)
+	las := make([]*endpointv3.ClusterLoadAssignment, 0, len(data))
+	for _, la := range data {
+		las = append(las, la.(*endpointv3.ClusterLoadAssignment))
+	}
+	return las, nil
+}
+
 func (c *SnapshotCache) GetVersions(nodeID string) (map[string]string, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -118,12 +152,25 @@ func (c *SnapshotCache) GetVersions(nodeID string) (map[string]string, error) {
 		"routes":    resourcev3.RouteType,
 		"listeners": resourcev3.ListenerType,
 		"secrets":   resourcev3.SecretType,
+		"endpoints": resourcev3.EndpointType,
 	} {
 		m[typ] = snapshot.GetVersion(typeURL)
 	}
 	return m, nil
 }
 
+// GetVersion returns the version of a resource type in the snapshot of the node,
+// or an empty string if the node has no snapshot.
+func (c *SnapshotCache) GetVersion(nodeID, typeURL string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	snapshot, err := c.SnapshotCache.GetSnapshot(nodeID)
+	if err != nil {
+		return ""
+	}
+	return snapshot.GetVersion(typeURL)
+}
+
 func (c *SnapshotCache) GetRouteConfigurations(nodeID string) ([]*routev3.RouteConfiguration, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -189,6 +236,7 @@ type ResourceVersions struct {
 	Listeners []ResourceVersion `json:"listeners"`
 	Routes    []ResourceVersion `json:"routes"`
 	Secrets   []ResourceVersion `json:"secrets"`
+	Endpoints []ResourceVersion `json:"endpoints"`
 }
 
 // GetResourceVersions returns hash-based versions for each individual resource in the snapshot.
@@ -207,6 +255,7 @@ func (c *SnapshotCache) GetResourceVersions(nodeID string) (*ResourceVersions, e
 		Listeners: computeResourceVersions(snapshot.GetResources(resourcev3.ListenerType)),
 		Routes:    computeResourceVersions(snapshot.GetResources(resourcev3.RouteType)),
 		Secrets:   computeResourceVersions(snapshot.GetResources(resourcev3.SecretType)),
+		Endpoints: computeResourceVersions(snapshot.GetResources(resourcev3.EndpointType)),
 	}
 
 	return result, nil
@@ -228,12 +277,52 @@ func computeResourceVersions(resources map[string]types.Resource) []ResourceVers
 	return versions
 }
 
-// computeResourceHash computes a SHA256 hash of a protobuf message
+// computeResourceHash computes a SHA256 hash of a protobuf message. Messages packed into Any
+// fields are hashed by content: their bytes depend on how they were marshaled (e.g. the order
+// of map entries), so equal resources built twice would otherwise get different hashes.
 func computeResourceHash(msg types.Resource) string {
-	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	canonical := proto.Clone(msg)
+	canonicalizeAny(canonical.ProtoReflect())
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(canonical)
 	if err != nil {
 		return "error"
 	}
 	hash := sha256.Sum256(data)
 	return hex.EncodeToString(hash[:])
 }
+
+// canonicalizeAny re-marshals all Any values nested in the message deterministically.
+// Values of unknown types are kept as they are.
+func canonicalizeAny(m protoreflect.Message) {
+	if a, ok := m.Interface().(*anypb.Any); ok {
+		inner, err := a.UnmarshalNew()
+		if err != nil {
+			return
+		}
+		canonicalizeAny(inner.ProtoReflect())
+		if value, err := (proto.MarshalOptions{Deterministic: true}).Marshal(inner); err == nil {
+			a.Value = value
+		}
+		return
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			if fd.Message() != nil {
+				for i := 0; i < v.List().Len(); i++ {
+					canonicalizeAny(v.List().Get(i).Message())
+				}
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					canonicalizeAny(mv.Message())
+					return true
+				})
+			}
+		case fd.Message() != nil:
+			canonicalizeAny(v.Message())
+		}
+		return true
+	})
+}
@@ -2,16 +2,17 @@ package xds
 
 import (
 	"context"
-	"fmt"
 	"sync"
 
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
-	v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/server/v3"
 	"github.com/go-logr/logr"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/auth"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/clients"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/nack"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
 )
 
 type Callbacks struct {
@@ -24,17 +25,26 @@ type Callbacks struct {
 	DeltaResponses   int
 	mu               sync.Mutex
 	connectedClients *clients.Registry
+	nackTracker      *nack.Tracker
+	authorizer       *auth.Authorizer
 }
 
-func NewCallbacks(logger logr.Logger, registry *clients.Registry) *Callbacks {
+func NewCallbacks(logger logr.Logger, registry *clients.Registry, tracker *nack.Tracker) *Callbacks {
 	return &Callbacks{
 		log:              logger,
 		connectedClients: registry,
+		nackTracker:      tracker,
 	}
 }
 
 var _ server.Callbacks = &Callbacks{}
 
+// SetAuthorizer enables client authentication and node ID authorization of xDS streams.
+// It must be set before the xDS server is started.
+func (cb *Callbacks) SetAuthorizer(authorizer *auth.Authorizer) {
+	cb.authorizer = authorizer
+}
+
 func (cb *Callbacks) Report() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
@@ -45,31 +55,44 @@ func (cb *Callbacks) Report() {
 	)
 }
 
-func (cb *Callbacks) OnStreamOpen(_ context.Context, id int64, typ string) error {
+func (cb *Callbacks) OnStreamOpen(ctx context.Context, id int64, typ string) error {
+	if err := cb.authenticate(ctx, id); err != nil {
+		return err
+	}
+	cb.connectedClients.Open(id, peerAddress(ctx), clients.ProtocolSotW)
 	cb.log.Info("stream open", "id", id, "typ", typ)
 	return nil
 }
 func (cb *Callbacks) OnStreamClosed(id int64, node *core.Node) {
 	cb.log.Info("stream closed", "id", id, "nodeId", node.Id)
 	cb.connectedClients.Delete(id)
+	cb.nackTracker.OnStreamClosed(id)
+	if cb.authorizer != nil {
+		cb.authorizer.OnStreamClosed(id)
+	}
 }
 
 func (cb *Callbacks) OnDeltaStreamOpen(ctx context.Context, id int64, typ string) error {
-	p, ok := peer.FromContext(ctx)
-	clientInfo := &clients.Info{ID: id}
-	if ok && p != nil && p.Addr != nil {
-		clientInfo.Address = p.Addr.String()
+	if err := cb.authenticate(ctx, id); err != nil {
+		return err
 	}
-	cb.connectedClients.Set(id, clientInfo)
+	cb.connectedClients.Open(id, peerAddress(ctx), clients.ProtocolDelta)
 	cb.log.Info("delta stream open", "id", id, "typ", typ)
 	return nil
 }
 func (cb *Callbacks) OnDeltaStreamClosed(id int64, node *core.Node) {
 	cb.log.Info("delta stream closed", "id", id, "nodeId", node.Id)
 	cb.connectedClients.Delete(id)
+	cb.nackTracker.OnStreamClosed(id)
+	if cb.authorizer != nil {
+		cb.authorizer.OnStreamClosed(id)
+	}
 }
 
 func (cb *Callbacks) OnStreamRequest(id int64, req *discovery.DiscoveryRequest) error {
+	if err := cb.authorize(id, req.GetNode().GetId()); err != nil {
+		return err
+	}
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 	cb.Requests++
@@ -84,11 +107,18 @@ func (cb *Callbacks) OnStreamRequest(id int64, req *discovery.DiscoveryRequest)
 		"resourceNames", req.ResourceNames,
 		"nodeId", req.Node.Id,
 	)
-	cb.connectedClients.Update(id, &clients.Info{
-		ID:      id,
-		NodeID:  req.Node.Id,
-		Version: semver(req.Node.GetUserAgentBuildVersion().GetVersion()),
-	})
+	cb.connectedClients.OnRequest(id, req.GetNode(), req.GetTypeUrl(), req.GetResourceNames(),
+		req.GetResponseNonce(), req.GetErrorDetail())
+	if req.ErrorDetail != nil {
+		cb.log.Info("envoy rejected configuration",
+			"typeUrl", req.GetTypeUrl(),
+			"id", id,
+			"nodeId", req.Node.Id,
+			"responseNonce", req.ResponseNonce,
+			"error", req.ErrorDetail.GetMessage(),
+		)
+	}
+	cb.nackTracker.OnRequest(id, req.Node.GetId(), req.GetTypeUrl(), req.GetResponseNonce(), req.GetErrorDetail())
 	return nil
 }
 
@@ -96,25 +126,40 @@ func (cb *Callbacks) OnStreamResponse(
 	_ context.Context,
 	id int64,
 	req *discovery.DiscoveryRequest,
-	_ *discovery.DiscoveryResponse,
+	resp *discovery.DiscoveryResponse,
 ) {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 	cb.Responses++
 	cb.log.Info("responding to stream request", "typeUrl", req.GetTypeUrl(), "id", id, "nodeId", req.Node.Id)
+	cb.connectedClients.OnResponse(id, resp.GetTypeUrl(), resp.GetVersionInfo(), resp.GetNonce())
+	cb.nackTracker.OnResponse(id, resp.GetTypeUrl(), resp.GetVersionInfo(), resp.GetNonce())
+	observePush(resp.GetTypeUrl(), protocolSotW, len(resp.GetResources()), proto.Size(resp))
 }
 
 func (cb *Callbacks) OnStreamDeltaResponse(
 	id int64,
 	req *discovery.DeltaDiscoveryRequest,
-	_ *discovery.DeltaDiscoveryResponse,
+	resp *discovery.DeltaDiscoveryResponse,
 ) {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 	cb.DeltaResponses++
-	cb.log.Info("responding to stream delta request", "typeUrl", req.GetTypeUrl(), "id", id)
+	cb.log.Info("responding to stream delta request",
+		"typeUrl", req.GetTypeUrl(),
+		"id", id,
+		"resources", len(resp.GetResources()),
+		"removedResources", len(resp.GetRemovedResources()),
+	)
+	cb.connectedClients.OnResponse(id, resp.GetTypeUrl(), resp.GetSystemVersionInfo(), resp.GetNonce())
+	cb.nackTracker.OnResponse(id, resp.GetTypeUrl(), resp.GetSystemVersionInfo(), resp.GetNonce())
+	observePush(resp.GetTypeUrl(), protocolDelta, len(resp.GetResources()), proto.Size(resp))
+	removedResources.WithLabelValues(resp.GetTypeUrl()).Add(float64(len(resp.GetRemovedResources())))
 }
 func (cb *Callbacks) OnStreamDeltaRequest(id int64, req *discovery.DeltaDiscoveryRequest) error {
+	if err := cb.authorize(id, req.GetNode().GetId()); err != nil {
+		return err
+	}
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 	cb.DeltaRequests++
@@ -127,14 +172,27 @@ func (cb *Callbacks) OnStreamDeltaRequest(id int64, req *discovery.DeltaDiscover
 		"id", id,
 		"nodeId", req.Node.Id,
 	)
-	cb.connectedClients.Update(id, &clients.Info{
-		ID:      id,
-		NodeID:  req.Node.Id,
-		Version: semver(req.Node.GetUserAgentBuildVersion().GetVersion()),
-	})
+	cb.connectedClients.OnDeltaRequest(id, req.GetNode(), req.GetTypeUrl(), req.GetResourceNamesSubscribe(),
+		req.GetResourceNamesUnsubscribe(), req.GetResponseNonce(), req.GetErrorDetail())
+	if req.ErrorDetail != nil {
+		cb.log.Info("envoy rejected configuration",
+			"typeUrl", req.GetTypeUrl(),
+			"id", id,
+			"nodeId", req.Node.Id,
+			"responseNonce", req.ResponseNonce,
+			"error", req.ErrorDetail.GetMessage(),
+		)
+	}
+	cb.nackTracker.OnRequest(id, req.Node.GetId(), req.GetTypeUrl(), req.GetResponseNonce(), req.GetErrorDetail())
 	return nil
 }
-func (cb *Callbacks) OnFetchRequest(_ context.Context, req *discovery.DiscoveryRequest) error {
+func (cb *Callbacks) OnFetchRequest(ctx context.Context, req *discovery.DiscoveryRequest) error {
+	if cb.authorizer != nil {
+		if err := cb.authorizer.AuthorizeContext(ctx, req.GetNode().GetId()); err != nil {
+			cb.log.Info("rejected unauthorized fetch request", "nodeId", req.GetNode().GetId(), "error", err.Error())
+			return err
+		}
+	}
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 	cb.Fetches++
@@ -151,9 +209,33 @@ func (cb *Callbacks) OnFetchResponse(*discovery.DiscoveryRequest, *discovery.Dis
 	cb.log.Info("responding to fetch request")
 }
 
-func semver(ver *v3.SemanticVersion) string {
-	if ver == nil {
-		return "-"
+// authenticate rejects streams without a verified client certificate when authorization is enabled
+func (cb *Callbacks) authenticate(ctx context.Context, id int64) error {
+	if cb.authorizer == nil {
+		return nil
+	}
+	if err := cb.authorizer.OnStreamOpen(ctx, id); err != nil {
+		cb.log.Info("rejected unauthenticated stream", "id", id, "address", peerAddress(ctx), "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// authorize rejects requests for node IDs the client of the stream is not allowed to use
+func (cb *Callbacks) authorize(id int64, nodeID string) error {
+	if cb.authorizer == nil {
+		return nil
+	}
+	if err := cb.authorizer.Authorize(id, nodeID); err != nil {
+		cb.log.Info("rejected unauthorized stream", "id", id, "nodeId", nodeID, "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func peerAddress(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
 	}
-	return fmt.Sprintf("v%d.%d.%d", ver.MajorNumber, ver.MinorNumber, ver.Patch)
+	return ""
 }
@@ -1,8 +1,81 @@
 package clients
 
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	ProtocolSotW  = "sotw"
+	ProtocolDelta = "delta"
+)
+
+// Info describes an xDS stream of a connected Envoy.
 type Info struct {
-	ID      int64
-	NodeID  string
-	Address string
-	Version string
+	ID          int64          `json:"id"`
+	NodeID      string         `json:"node_id"`
+	Cluster     string         `json:"cluster,omitempty"`
+	Address     string         `json:"address"`
+	Protocol    string         `json:"protocol"`
+	Version     string         `json:"version"`
+	ConnectedAt time.Time      `json:"connected_at"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	Locality    *Locality      `json:"locality,omitempty"`
+	// Subscriptions of the stream sorted by type URL
+	Subscriptions []Subscription `json:"subscriptions"`
+	// Stale is set when any subscription has not acknowledged the current snapshot version
+	Stale bool `json:"stale"`
+}
+
+// Labels returns the labels node selectors are matched against: scalar top-level metadata
+// fields, and the cluster, region, zone and sub_zone of the node, which take precedence.
+func (i Info) Labels() map[string]string {
+	result := make(map[string]string, len(i.Metadata)+4)
+	for key, value := range i.Metadata {
+		switch value.(type) {
+		case string, bool, float64:
+			result[key] = fmt.Sprint(value)
+		}
+	}
+	set := func(key, value string) {
+		if value != "" {
+			result[key] = value
+		}
+	}
+	set("cluster", i.Cluster)
+	if i.Locality != nil {
+		set("region", i.Locality.Region)
+		set("zone", i.Locality.Zone)
+		set("sub_zone", i.Locality.SubZone)
+	}
+	return result
+}
+
+// Locality is the locality reported by Envoy in its node information.
+type Locality struct {
+	Region  string `json:"region,omitempty"`
+	Zone    string `json:"zone,omitempty"`
+	SubZone string `json:"sub_zone,omitempty"`
+}
+
+// Subscription describes the state of a resource type requested on a stream.
+type Subscription struct {
+	TypeURL string `json:"type_url"`
+	// ResourceNames requested by Envoy, empty for wildcard subscriptions
+	ResourceNames []string `json:"resource_names,omitempty"`
+	// SentVersion is the version of the last response sent to Envoy
+	SentVersion  string     `json:"sent_version"`
+	AckedVersion string     `json:"acked_version"`
+	AckedAt      *time.Time `json:"acked_at,omitempty"`
+	LastNack     *Nack      `json:"last_nack,omitempty"`
+	// CurrentVersion is the version of the type in the current snapshot of the node
+	CurrentVersion string `json:"current_version"`
+	Synced         bool   `json:"synced"`
+}
+
+// Nack describes the latest response rejected by Envoy.
+type Nack struct {
+	Version   string    `json:"version"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
 }
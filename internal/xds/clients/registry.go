@@ -1,54 +1,330 @@
 package clients
 
 import (
+	"fmt"
 	"sort"
 	"sync"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/genproto/googleapis/rpc/status"
 )
 
+// VersionLookup returns the version of a resource type in the current snapshot of a node.
+type VersionLookup func(nodeID, typeURL string) string
+
+type subscription struct {
+	Subscription
+	names map[string]struct{}
+	nonce string
+}
+
+type stream struct {
+	info          Info
+	subscriptions map[string]*subscription
+}
+
+// Registry keeps track of connected Envoys per xDS stream: their node information,
+// subscribed resources and the versions they acknowledged or rejected.
 type Registry struct {
-	mu   sync.RWMutex
-	data map[int64]*Info
+	mu       sync.RWMutex
+	data     map[int64]*stream
+	now      func() time.Time
+	onChange func(nodeID string)
+	// streams counts the identified streams per node ID
+	streams        map[string]int
+	onConnected    func(Info)
+	onDisconnected func(nodeID string)
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
-		data: make(map[int64]*Info),
+		data:    make(map[int64]*stream),
+		streams: make(map[string]int),
+		now:     time.Now,
 	}
 }
 
-func (r *Registry) Set(id int64, ci *Info) {
+// SetOnChange registers a function called with the node ID of a stream that identified itself,
+// acknowledged or rejected a response, or was closed. It must be set before the xDS server is
+// started and must not block.
+func (r *Registry) SetOnChange(fn func(nodeID string)) {
+	r.onChange = fn
+}
+
+// SetOnNode registers functions called when a stream identifies itself with its node
+// information, and when the last stream of a node is closed. They must be set before
+// the xDS server is started and must not block.
+func (r *Registry) SetOnNode(connected func(Info), disconnected func(nodeID string)) {
+	r.onConnected = connected
+	r.onDisconnected = disconnected
+}
+
+// Open records a new stream.
+func (r *Registry) Open(id int64, address, protocol string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.data[id] = ci
+	r.data[id] = &stream{
+		info: Info{
+			ID:          id,
+			Address:     address,
+			Protocol:    protocol,
+			ConnectedAt: r.now(),
+		},
+		subscriptions: make(map[string]*subscription),
+	}
+}
+
+// OnRequest handles a state-of-the-world request, which replaces the subscribed resource names.
+func (r *Registry) OnRequest(
+	id int64,
+	node *core.Node,
+	typeURL string,
+	resourceNames []string,
+	responseNonce string,
+	errorDetail *status.Status,
+) {
+	r.mu.Lock()
+	sub, connected, disconnected := r.subscription(id, node, typeURL)
+	changed := false
+	if sub != nil {
+		sub.names = make(map[string]struct{}, len(resourceNames))
+		for _, name := range resourceNames {
+			sub.names[name] = struct{}{}
+		}
+		changed = r.handleNonce(sub, responseNonce, errorDetail)
+	}
+	nodeID := r.data[id].nodeID()
+	r.mu.Unlock()
+	r.notifyNode(connected, disconnected)
+	if changed || connected != nil {
+		r.notify(nodeID)
+	}
 }
 
-func (r *Registry) Update(id int64, ci *Info) {
+// OnDeltaRequest handles a delta request, which changes the subscribed resource names.
+func (r *Registry) OnDeltaRequest(
+	id int64,
+	node *core.Node,
+	typeURL string,
+	subscribe, unsubscribe []string,
+	responseNonce string,
+	errorDetail *status.Status,
+) {
+	r.mu.Lock()
+	sub, connected, disconnected := r.subscription(id, node, typeURL)
+	changed := false
+	if sub != nil {
+		for _, name := range subscribe {
+			sub.names[name] = struct{}{}
+		}
+		for _, name := range unsubscribe {
+			delete(sub.names, name)
+		}
+		changed = r.handleNonce(sub, responseNonce, errorDetail)
+	}
+	nodeID := r.data[id].nodeID()
+	r.mu.Unlock()
+	r.notifyNode(connected, disconnected)
+	if changed || connected != nil {
+		r.notify(nodeID)
+	}
+}
+
+// OnResponse records the version and nonce of a response sent on the stream.
+func (r *Registry) OnResponse(id int64, typeURL, version, nonce string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	prev, ok := r.data[id]
+	s, ok := r.data[id]
 	if !ok {
-		r.data[id] = ci
 		return
 	}
-	prev.Version = ci.Version
-	prev.NodeID = ci.NodeID
+	sub, ok := s.subscriptions[typeURL]
+	if !ok {
+		return
+	}
+	sub.SentVersion = version
+	sub.nonce = nonce
 }
 
+// Delete forgets a closed stream.
 func (r *Registry) Delete(id int64) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	s, ok := r.data[id]
 	delete(r.data, id)
+	disconnected := ""
+	if ok {
+		disconnected = r.release(s.info.NodeID)
+	}
+	r.mu.Unlock()
+	r.notifyNode(nil, disconnected)
+	if ok {
+		r.notify(s.info.NodeID)
+	}
+}
+
+// AcknowledgedVersions returns, for every stream of the node, the versions it acknowledged
+// per subscribed type URL. Types not acknowledged yet have an empty version.
+func (r *Registry) AcknowledgedVersions(nodeID string) []map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []map[string]string
+	for _, s := range r.data {
+		if s.info.NodeID != nodeID {
+			continue
+		}
+		acked := make(map[string]string, len(s.subscriptions))
+		for typeURL, sub := range s.subscriptions {
+			acked[typeURL] = sub.AckedVersion
+		}
+		result = append(result, acked)
+	}
+	return result
 }
 
-func (r *Registry) List() []Info {
+// List returns connected clients sorted by stream ID. With a version lookup, subscriptions
+// are compared with the current snapshots of their nodes and clients lagging behind are stale.
+func (r *Registry) List(lookup VersionLookup) []Info {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	result := make([]Info, 0, len(r.data))
-	for _, c := range r.data {
-		result = append(result, *c)
+	for _, s := range r.data {
+		info := s.info
+		info.Subscriptions = make([]Subscription, 0, len(s.subscriptions))
+		for _, sub := range s.subscriptions {
+			subscription := sub.Subscription
+			subscription.ResourceNames = make([]string, 0, len(sub.names))
+			for name := range sub.names {
+				subscription.ResourceNames = append(subscription.ResourceNames, name)
+			}
+			sort.Strings(subscription.ResourceNames)
+			if lookup != nil {
+				subscription.CurrentVersion = lookup(info.NodeID, sub.TypeURL)
+				subscription.Synced = subscription.AckedVersion == subscription.CurrentVersion
+				info.Stale = info.Stale || !subscription.Synced
+			}
+			info.Subscriptions = append(info.Subscriptions, subscription)
+		}
+		sort.Slice(info.Subscriptions, func(i, j int) bool {
+			return info.Subscriptions[i].TypeURL < info.Subscriptions[j].TypeURL
+		})
+		result = append(result, info)
 	}
 	sort.Slice(result, func(i, j int) bool {
 		return result[i].ID < result[j].ID
 	})
 	return result
 }
+
+// subscription returns the subscription of the stream to the type, recording the node
+// information sent with the request. If the stream identified itself with a new node ID,
+// it also returns its information and the node ID it left if that node has no streams left.
+// Must be called with r.mu held.
+func (r *Registry) subscription(id int64, node *core.Node, typeURL string) (*subscription, *Info, string) {
+	s, ok := r.data[id]
+	if !ok {
+		return nil, nil, ""
+	}
+	var connected *Info
+	disconnected := ""
+	if node.GetId() != "" {
+		identified := node.GetId() != s.info.NodeID
+		if identified {
+			disconnected = r.release(s.info.NodeID)
+			r.streams[node.GetId()]++
+		}
+		setNode(&s.info, node)
+		if identified {
+			info := s.info
+			connected = &info
+		}
+	}
+	sub, ok := s.subscriptions[typeURL]
+	if !ok {
+		sub = &subscription{
+			Subscription: Subscription{TypeURL: typeURL},
+			names:        make(map[string]struct{}),
+		}
+		s.subscriptions[typeURL] = sub
+	}
+	return sub, connected, disconnected
+}
+
+// release forgets a stream of the node and returns the node ID if it was its last stream.
+// Must be called with r.mu held.
+func (r *Registry) release(nodeID string) string {
+	if nodeID == "" {
+		return ""
+	}
+	r.streams[nodeID]--
+	if r.streams[nodeID] > 0 {
+		return ""
+	}
+	delete(r.streams, nodeID)
+	return nodeID
+}
+
+// handleNonce records an ACK or NACK of the last response sent for the subscription.
+// Requests with a stale or unknown nonce do not refer to the last response.
+// It returns true if a response was acknowledged or rejected.
+func (r *Registry) handleNonce(sub *subscription, responseNonce string, errorDetail *status.Status) bool {
+	if responseNonce == "" || responseNonce != sub.nonce {
+		return false
+	}
+	// a response is acknowledged or rejected once
+	sub.nonce = ""
+	now := r.now()
+	if errorDetail != nil {
+		sub.LastNack = &Nack{Version: sub.SentVersion, Message: errorDetail.GetMessage(), Timestamp: now}
+		return true
+	}
+	sub.AckedVersion = sub.SentVersion
+	sub.AckedAt = &now
+	return true
+}
+
+func (r *Registry) notifyNode(connected *Info, disconnected string) {
+	if disconnected != "" && r.onDisconnected != nil {
+		r.onDisconnected(disconnected)
+	}
+	if connected != nil && r.onConnected != nil {
+		r.onConnected(*connected)
+	}
+}
+
+func (r *Registry) notify(nodeID string) {
+	if r.onChange != nil && nodeID != "" {
+		r.onChange(nodeID)
+	}
+}
+
+func (s *stream) nodeID() string {
+	if s == nil {
+		return ""
+	}
+	return s.info.NodeID
+}
+
+func setNode(info *Info, node *core.Node) {
+	info.NodeID = node.GetId()
+	info.Cluster = node.GetCluster()
+	info.Version = semver(node.GetUserAgentBuildVersion().GetVersion())
+	if metadata := node.GetMetadata(); metadata != nil {
+		info.Metadata = metadata.AsMap()
+	}
+	if locality := node.GetLocality(); locality != nil {
+		info.Locality = &Locality{
+			Region:  locality.GetRegion(),
+			Zone:    locality.GetZone(),
+			SubZone: locality.GetSubZone(),
+		}
+	}
+}
+
+func semver(ver *typev3.SemanticVersion) string {
+	if ver == nil {
+		return "-"
+	}
+	return fmt.Sprintf("v%d.%d.%d", ver.MajorNumber, ver.MinorNumber, ver.Patch)
+}
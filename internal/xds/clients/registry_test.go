@@ -0,0 +1,181 @@
+package clients
+
+import (
+	"slices"
+	"testing"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func testNode() *core.Node {
+	metadata, _ := structpb.NewStruct(map[string]any{"role": "edge"})
+	return &core.Node{
+		Id:       "node",
+		Cluster:  "edge",
+		Metadata: metadata,
+		Locality: &core.Locality{Region: "eu", Zone: "eu-1"},
+		UserAgentVersionType: &core.Node_UserAgentBuildVersion{UserAgentBuildVersion: &core.BuildVersion{
+			Version: &typev3.SemanticVersion{MajorNumber: 1, MinorNumber: 34, Patch: 2},
+		}},
+	}
+}
+
+// TestRegistryTracksAcknowledgedVersions verifies that the registry records the node information
+// of a stream and the versions its subscriptions acknowledged or rejected, and that clients
+// which have not acknowledged the current version are stale.
+func TestRegistryTracksAcknowledgedVersions(t *testing.T) {
+	r := NewRegistry()
+	current := map[string]string{resource.ClusterType: "v1"}
+	lookup := func(nodeID, typeURL string) string {
+		if nodeID != "node" {
+			t.Errorf("unexpected node ID %q", nodeID)
+		}
+		return current[typeURL]
+	}
+
+	r.Open(1, "10.0.0.1:5000", ProtocolSotW)
+	r.OnRequest(1, testNode(), resource.ClusterType, nil, "", nil)
+	r.OnResponse(1, resource.ClusterType, "v1", "nonce-1")
+	// Envoy sends the node information only with the first request
+	r.OnRequest(1, nil, resource.ClusterType, nil, "nonce-1", nil)
+
+	list := r.List(lookup)
+	if len(list) != 1 {
+		t.Fatalf("expected 1 client, got %d", len(list))
+	}
+	info := list[0]
+	if info.NodeID != "node" || info.Cluster != "edge" || info.Version != "v1.34.2" ||
+		info.Metadata["role"] != "edge" || info.Locality == nil || info.Locality.Zone != "eu-1" {
+		t.Errorf("unexpected client info: %+v", info)
+	}
+	if info.Stale || len(info.Subscriptions) != 1 || info.Subscriptions[0].AckedVersion != "v1" {
+		t.Errorf("expected client to be synced, got %+v", info)
+	}
+
+	// A new version is stale until acknowledged, a rejection keeps the acknowledged version
+	current[resource.ClusterType] = "v2"
+	r.OnResponse(1, resource.ClusterType, "v2", "nonce-2")
+	r.OnRequest(1, nil, resource.ClusterType, nil, "nonce-2", &status.Status{Message: "bad cluster"})
+	info = r.List(lookup)[0]
+	sub := info.Subscriptions[0]
+	if !info.Stale || sub.Synced || sub.AckedVersion != "v1" || sub.CurrentVersion != "v2" {
+		t.Errorf("expected client to be stale on v1, got %+v", sub)
+	}
+	if sub.LastNack == nil || sub.LastNack.Version != "v2" || sub.LastNack.Message != "bad cluster" {
+		t.Errorf("expected rejection of v2, got %+v", sub.LastNack)
+	}
+
+	// Requests with a stale nonce do not acknowledge the last response
+	r.OnResponse(1, resource.ClusterType, "v2", "nonce-3")
+	r.OnRequest(1, nil, resource.ClusterType, nil, "nonce-2", nil)
+	if r.List(lookup)[0].Subscriptions[0].AckedVersion != "v1" {
+		t.Errorf("expected stale nonce to be ignored")
+	}
+	r.OnRequest(1, nil, resource.ClusterType, nil, "nonce-3", nil)
+	if info := r.List(lookup)[0]; info.Stale {
+		t.Errorf("expected client to be synced after acknowledging v2, got %+v", info.Subscriptions)
+	}
+
+	r.Delete(1)
+	if list := r.List(lookup); len(list) != 0 {
+		t.Errorf("expected no clients after the stream closed, got %+v", list)
+	}
+}
+
+// TestRegistryDeltaSubscriptions verifies that delta requests change subscribed resource names
+func TestRegistryDeltaSubscriptions(t *testing.T) {
+	r := NewRegistry()
+	r.Open(1, "", ProtocolDelta)
+	r.OnDeltaRequest(1, testNode(), resource.EndpointType, []string{"b", "a", "c"}, nil, "", nil)
+	r.OnDeltaRequest(1, nil, resource.EndpointType, []string{"d"}, []string{"c"}, "", nil)
+
+	sub := r.List(nil)[0].Subscriptions[0]
+	if !slices.Equal(sub.ResourceNames, []string{"a", "b", "d"}) {
+		t.Errorf("expected subscribed names [a b d], got %v", sub.ResourceNames)
+	}
+
+	// Requests of unknown streams are ignored
+	r.OnDeltaRequest(2, testNode(), resource.EndpointType, []string{"a"}, nil, "", nil)
+	if list := r.List(nil); len(list) != 1 {
+		t.Errorf("expected 1 client, got %d", len(list))
+	}
+}
+
+// TestRegistryNotifiesChanges verifies that identifying, acknowledging and closing streams
+// notify about their node, and that acknowledged versions are reported per stream.
+func TestRegistryNotifiesChanges(t *testing.T) {
+	r := NewRegistry()
+	var notified []string
+	r.SetOnChange(func(nodeID string) { notified = append(notified, nodeID) })
+
+	r.Open(1, "", ProtocolSotW)
+	r.Open(2, "", ProtocolSotW)
+	r.OnRequest(1, testNode(), resource.ClusterType, nil, "", nil)
+	r.OnRequest(2, testNode(), resource.ClusterType, nil, "", nil)
+	r.OnRequest(2, nil, resource.ListenerType, nil, "", nil)
+	r.OnResponse(1, resource.ClusterType, "v1", "nonce-1")
+	r.OnRequest(1, nil, resource.ClusterType, nil, "nonce-1", nil)
+	// a repeated request with the same nonce does not acknowledge again
+	r.OnRequest(1, nil, resource.ClusterType, nil, "nonce-1", nil)
+	if !slices.Equal(notified, []string{"node", "node", "node"}) {
+		t.Errorf("expected 3 notifications, got %v", notified)
+	}
+
+	acked := r.AcknowledgedVersions("node")
+	if len(acked) != 2 {
+		t.Fatalf("expected versions of 2 streams, got %v", acked)
+	}
+	slices.SortFunc(acked, func(a, b map[string]string) int { return len(a) - len(b) })
+	if acked[0][resource.ClusterType] != "v1" || acked[1][resource.ClusterType] != "" || len(acked[1]) != 2 {
+		t.Errorf("unexpected acknowledged versions %v", acked)
+	}
+
+	r.Delete(1)
+	if len(notified) != 4 || len(r.AcknowledgedVersions("node")) != 1 {
+		t.Errorf("expected closed stream to notify and be forgotten, got %v", notified)
+	}
+}
+
+// TestRegistryNodeLifecycle verifies that a node is reported connected with its labels once
+// its first stream identifies itself, and disconnected when its last stream is closed.
+func TestRegistryNodeLifecycle(t *testing.T) {
+	r := NewRegistry()
+	var connected []Info
+	var disconnected []string
+	r.SetOnNode(
+		func(info Info) { connected = append(connected, info) },
+		func(nodeID string) { disconnected = append(disconnected, nodeID) },
+	)
+
+	r.Open(1, "", ProtocolSotW)
+	r.Open(2, "", ProtocolSotW)
+	r.OnRequest(1, testNode(), resource.ClusterType, nil, "", nil)
+	r.OnRequest(1, testNode(), resource.ListenerType, nil, "", nil)
+	r.OnRequest(2, testNode(), resource.ClusterType, nil, "", nil)
+	if len(connected) != 2 {
+		t.Fatalf("expected every identified stream to be reported, got %d", len(connected))
+	}
+	labels := connected[0].Labels()
+	expected := map[string]string{"role": "edge", "cluster": "edge", "region": "eu", "zone": "eu-1"}
+	if len(labels) != len(expected) {
+		t.Errorf("expected labels %v, got %v", expected, labels)
+	}
+	for key, value := range expected {
+		if labels[key] != value {
+			t.Errorf("expected label %s=%s, got %v", key, value, labels)
+		}
+	}
+
+	r.Delete(1)
+	if len(disconnected) != 0 {
+		t.Errorf("expected node to stay connected with a stream left, got %v", disconnected)
+	}
+	r.Delete(2)
+	if !slices.Equal(disconnected, []string{"node"}) {
+		t.Errorf("expected node to be disconnected, got %v", disconnected)
+	}
+}
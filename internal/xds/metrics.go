@@ -0,0 +1,53 @@
+package xds
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	protocolSotW  = "sotw"
+	protocolDelta = "delta"
+)
+
+var (
+	removedResources = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "exc",
+			Subsystem: "xds",
+			Name:      "removed_resources_total",
+			Help:      "Total number of resource removals pushed to Envoy over delta xDS, by type.",
+		},
+		[]string{"type_url"},
+	)
+	pushResources = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "exc",
+			Subsystem: "xds",
+			Name:      "push_resources",
+			Help:      "Number of resources pushed to Envoy per response, by type and protocol (sotw or delta).",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+		},
+		[]string{"type_url", "protocol"},
+	)
+	pushBytes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "exc",
+			Subsystem: "xds",
+			Name:      "push_bytes",
+			Help:      "Size in bytes of the responses pushed to Envoy, by type and protocol (sotw or delta).",
+			Buckets:   prometheus.ExponentialBuckets(256, 4, 10),
+		},
+		[]string{"type_url", "protocol"},
+	)
+)
+
+func init() {
+	ctrmetrics.Registry.MustRegister(pushResources, pushBytes, removedResources)
+}
+
+// observePush records the resources and bytes of a response pushed to Envoy
+func observePush(typeURL, protocol string, resources, size int) {
+	pushResources.WithLabelValues(typeURL, protocol).Observe(float64(resources))
+	pushBytes.WithLabelValues(typeURL, protocol).Observe(float64(size))
+}
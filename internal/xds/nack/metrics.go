@@ -0,0 +1,32 @@
+package nack
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	nackTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "exc",
+			Subsystem: "xds",
+			Name:      "nack_total",
+			Help:      "Total number of configuration updates rejected by Envoy.",
+		},
+		[]string{"node_id", "type_url"},
+	)
+
+	nackActive = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "exc",
+			Subsystem: "xds",
+			Name:      "nack_active",
+			Help:      "Whether the latest configuration version for the node and type is rejected by Envoy (0/1).",
+		},
+		[]string{"node_id", "type_url"},
+	)
+)
+
+func init() {
+	ctrmetrics.Registry.MustRegister(nackTotal, nackActive)
+}
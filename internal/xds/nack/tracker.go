@@ -0,0 +1,231 @@
+package nack
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"google.golang.org/genproto/googleapis/rpc/status"
+)
+
+// Rejection describes the latest configuration version rejected by Envoy for a node and resource type.
+type Rejection struct {
+	NodeID    string
+	TypeURL   string
+	Version   string
+	Message   string
+	Timestamp time.Time
+	Owners    []helpers.NamespacedName
+}
+
+// OwnerResolver maps a rejected resource type of a node back to the VirtualServices that produced it.
+type OwnerResolver interface {
+	ResolveRejectedOwners(nodeID, typeURL, message string) []helpers.NamespacedName
+}
+
+type sentResponse struct {
+	version string
+	nonce   string
+}
+
+// Tracker matches xDS requests with previously sent responses by nonce and keeps
+// track of configuration versions rejected (NACKed) by Envoy.
+type Tracker struct {
+	mu         sync.RWMutex
+	resolver   OwnerResolver
+	onChange   func([]helpers.NamespacedName)
+	sent       map[int64]map[string]sentResponse
+	rejections map[string]map[string]*Rejection
+	now        func() time.Time
+}
+
+func NewTracker(resolver OwnerResolver) *Tracker {
+	return &Tracker{
+		resolver:   resolver,
+		sent:       make(map[int64]map[string]sentResponse),
+		rejections: make(map[string]map[string]*Rejection),
+		now:        time.Now,
+	}
+}
+
+// SetOnChange registers a function called with the VirtualServices whose rejection state changed.
+// It must be set before the xDS server is started.
+func (t *Tracker) SetOnChange(fn func([]helpers.NamespacedName)) {
+	t.onChange = fn
+}
+
+// OnResponse records the version and nonce of a response sent on the stream.
+func (t *Tracker) OnResponse(streamID int64, typeURL, version, nonce string) {
+	if nonce == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	byType, ok := t.sent[streamID]
+	if !ok {
+		byType = make(map[string]sentResponse)
+		t.sent[streamID] = byType
+	}
+	byType[typeURL] = sentResponse{version: version, nonce: nonce}
+}
+
+// OnRequest handles a request received on the stream. Requests acknowledging the last sent
+// response clear the rejection of the node, requests carrying error details record a NACK.
+func (t *Tracker) OnRequest(streamID int64, nodeID, typeURL, responseNonce string, errorDetail *status.Status) {
+	if responseNonce == "" {
+		return
+	}
+
+	t.mu.RLock()
+	sent, ok := t.sent[streamID][typeURL]
+	t.mu.RUnlock()
+	if !ok || sent.nonce != responseNonce {
+		// Stale or unknown nonce, the request does not refer to the latest response.
+		return
+	}
+
+	if errorDetail != nil {
+		t.reject(nodeID, typeURL, sent.version, errorDetail.GetMessage())
+		return
+	}
+	t.accept(nodeID, typeURL, sent.version)
+}
+
+// OnStreamClosed forgets responses sent on the stream.
+func (t *Tracker) OnStreamClosed(streamID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.sent, streamID)
+}
+
+func (t *Tracker) reject(nodeID, typeURL, version, message string) {
+	var owners []helpers.NamespacedName
+	if t.resolver != nil {
+		owners = t.resolver.ResolveRejectedOwners(nodeID, typeURL, message)
+	}
+
+	t.mu.Lock()
+	byType, ok := t.rejections[nodeID]
+	if !ok {
+		byType = make(map[string]*Rejection)
+		t.rejections[nodeID] = byType
+	}
+	prev := byType[typeURL]
+	byType[typeURL] = &Rejection{
+		NodeID:    nodeID,
+		TypeURL:   typeURL,
+		Version:   version,
+		Message:   message,
+		Timestamp: t.now(),
+		Owners:    owners,
+	}
+	t.mu.Unlock()
+
+	nackTotal.WithLabelValues(nodeID, typeURL).Inc()
+	nackActive.WithLabelValues(nodeID, typeURL).Set(1)
+
+	if prev != nil && prev.Message == message && equalOwners(prev.Owners, owners) {
+		return
+	}
+	changed := owners
+	if prev != nil {
+		changed = mergeOwners(prev.Owners, owners)
+	}
+	t.notify(changed)
+}
+
+func (t *Tracker) accept(nodeID, typeURL, version string) {
+	t.mu.Lock()
+	prev, ok := t.rejections[nodeID][typeURL]
+	// Another proxy of the same node may acknowledge the version rejected by its peer,
+	// the rejection is kept until a different version is accepted.
+	if !ok || prev.Version == version {
+		t.mu.Unlock()
+		return
+	}
+	delete(t.rejections[nodeID], typeURL)
+	if len(t.rejections[nodeID]) == 0 {
+		delete(t.rejections, nodeID)
+	}
+	t.mu.Unlock()
+
+	nackActive.DeleteLabelValues(nodeID, typeURL)
+	t.notify(prev.Owners)
+}
+
+func (t *Tracker) notify(owners []helpers.NamespacedName) {
+	if t.onChange == nil || len(owners) == 0 {
+		return
+	}
+	t.onChange(owners)
+}
+
+// List returns all active rejections sorted by node ID and type URL.
+func (t *Tracker) List() []Rejection {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	result := make([]Rejection, 0)
+	for _, byType := range t.rejections {
+		for _, r := range byType {
+			result = append(result, *r)
+		}
+	}
+	sortRejections(result)
+	return result
+}
+
+// GetRejectionsForVirtualService returns active rejections caused by resources of the VirtualService.
+func (t *Tracker) GetRejectionsForVirtualService(nn helpers.NamespacedName) []Rejection {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var result []Rejection
+	for _, byType := range t.rejections {
+		for _, r := range byType {
+			for _, owner := range r.Owners {
+				if owner == nn {
+					result = append(result, *r)
+					break
+				}
+			}
+		}
+	}
+	sortRejections(result)
+	return result
+}
+
+func sortRejections(rejections []Rejection) {
+	sort.Slice(rejections, func(i, j int) bool {
+		if rejections[i].NodeID != rejections[j].NodeID {
+			return rejections[i].NodeID < rejections[j].NodeID
+		}
+		return rejections[i].TypeURL < rejections[j].TypeURL
+	})
+}
+
+func equalOwners(a, b []helpers.NamespacedName) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeOwners(a, b []helpers.NamespacedName) []helpers.NamespacedName {
+	seen := make(map[helpers.NamespacedName]struct{}, len(a)+len(b))
+	result := make([]helpers.NamespacedName, 0, len(a)+len(b))
+	for _, list := range [][]helpers.NamespacedName{a, b} {
+		for _, nn := range list {
+			if _, ok := seen[nn]; ok {
+				continue
+			}
+			seen[nn] = struct{}{}
+			result = append(result, nn)
+		}
+	}
+	return result
+}
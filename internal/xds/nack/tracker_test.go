@@ -0,0 +1,97 @@
+package nack
+
+import (
+	"testing"
+
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"google.golang.org/genproto/googleapis/rpc/status"
+)
+
+type staticResolver []helpers.NamespacedName
+
+func (r staticResolver) ResolveRejectedOwners(_, _, _ string) []helpers.NamespacedName {
+	return r
+}
+
+var testVS = helpers.NamespacedName{Namespace: "default", Name: "vs"}
+
+// TestTrackerRejectAndAccept verifies that a NACK is recorded for the sent version
+// and cleared when a newer version is acknowledged.
+func TestTrackerRejectAndAccept(t *testing.T) {
+	tracker := NewTracker(staticResolver{testVS})
+	var notified [][]helpers.NamespacedName
+	tracker.SetOnChange(func(nns []helpers.NamespacedName) {
+		notified = append(notified, nns)
+	})
+
+	tracker.OnResponse(1, resource.ListenerType, "2", "nonce-1")
+	tracker.OnRequest(1, "node", resource.ListenerType, "nonce-1", &status.Status{Message: "bad listener"})
+
+	rejections := tracker.GetRejectionsForVirtualService(testVS)
+	if len(rejections) != 1 {
+		t.Fatalf("expected 1 rejection, got %d", len(rejections))
+	}
+	if rejections[0].Version != "2" || rejections[0].Message != "bad listener" || rejections[0].NodeID != "node" {
+		t.Errorf("unexpected rejection: %+v", rejections[0])
+	}
+	if len(notified) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notified))
+	}
+
+	// Repeated NACK with the same error must not trigger another notification
+	tracker.OnResponse(1, resource.ListenerType, "2", "nonce-2")
+	tracker.OnRequest(1, "node", resource.ListenerType, "nonce-2", &status.Status{Message: "bad listener"})
+	if len(notified) != 1 {
+		t.Errorf("expected no new notification, got %d", len(notified))
+	}
+
+	tracker.OnResponse(1, resource.ListenerType, "3", "nonce-3")
+	tracker.OnRequest(1, "node", resource.ListenerType, "nonce-3", nil)
+	if got := tracker.List(); len(got) != 0 {
+		t.Errorf("expected rejection to be cleared, got %+v", got)
+	}
+	if len(notified) != 2 {
+		t.Errorf("expected notification on recovery, got %d", len(notified))
+	}
+}
+
+// TestTrackerIgnoresStaleNonce verifies that requests referring to an outdated
+// response do not change the rejection state.
+func TestTrackerIgnoresStaleNonce(t *testing.T) {
+	tracker := NewTracker(staticResolver{testVS})
+
+	tracker.OnResponse(1, resource.ClusterType, "1", "nonce-1")
+	tracker.OnResponse(1, resource.ClusterType, "2", "nonce-2")
+	tracker.OnRequest(1, "node", resource.ClusterType, "nonce-1", &status.Status{Message: "bad cluster"})
+	if got := tracker.List(); len(got) != 0 {
+		t.Errorf("expected stale NACK to be ignored, got %+v", got)
+	}
+
+	// Initial requests carry no nonce
+	tracker.OnRequest(2, "node", resource.ClusterType, "", &status.Status{Message: "bad cluster"})
+	if got := tracker.List(); len(got) != 0 {
+		t.Errorf("expected request without nonce to be ignored, got %+v", got)
+	}
+}
+
+// TestTrackerKeepsRejectionOnSameVersionAck verifies that an ACK of the rejected
+// version from another proxy of the same node keeps the rejection.
+func TestTrackerKeepsRejectionOnSameVersionAck(t *testing.T) {
+	tracker := NewTracker(staticResolver{testVS})
+
+	tracker.OnResponse(1, resource.RouteType, "5", "a")
+	tracker.OnResponse(2, resource.RouteType, "5", "b")
+	tracker.OnRequest(1, "node", resource.RouteType, "a", &status.Status{Message: "bad route"})
+	tracker.OnRequest(2, "node", resource.RouteType, "b", nil)
+
+	if got := tracker.List(); len(got) != 1 {
+		t.Errorf("expected rejection to be kept, got %+v", got)
+	}
+
+	tracker.OnStreamClosed(1)
+	tracker.OnRequest(1, "node", resource.RouteType, "a", nil)
+	if got := tracker.List(); len(got) != 1 {
+		t.Errorf("expected requests on closed stream to be ignored, got %+v", got)
+	}
+}
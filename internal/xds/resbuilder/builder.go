@@ -99,26 +99,30 @@ func (rb *ResourceBuilder) BuildResources(vs *v1alpha1.VirtualService) (*Resourc
 
 	// Convert to Resources
 	resources := &Resources{
-		Listener:    mainResources.Listener,
-		FilterChain: mainResources.FilterChain,
-		RouteConfig: mainResources.RouteConfig,
-		Clusters:    mainResources.Clusters,
-		Secrets:     mainResources.Secrets,
-		UsedSecrets: mainResources.UsedSecrets,
-		Domains:     mainResources.Domains,
+		Listener:         mainResources.Listener,
+		FilterChain:      mainResources.FilterChain,
+		RouteConfig:      mainResources.RouteConfig,
+		ScopedRoutes:     mainResources.ScopedRoutes,
+		Clusters:         mainResources.Clusters,
+		Secrets:          mainResources.Secrets,
+		UsedSecrets:      mainResources.UsedSecrets,
+		Domains:          mainResources.Domains,
+		ExtensionConfigs: mainResources.ExtensionConfigs,
 	}
 
 	return resources, nil
 }
 
 type Resources struct {
-	Listener    helpers.NamespacedName
-	FilterChain []*listenerv3.FilterChain
-	RouteConfig *routev3.RouteConfiguration
-	Clusters    []*cluster.Cluster
-	Secrets     []*tlsv3.Secret
-	UsedSecrets []helpers.NamespacedName
-	Domains     []string
+	Listener         helpers.NamespacedName
+	FilterChain      []*listenerv3.FilterChain
+	RouteConfig      *routev3.RouteConfiguration
+	ScopedRoutes     []*routev3.ScopedRouteConfiguration
+	Clusters         []*cluster.Cluster
+	Secrets          []*tlsv3.Secret
+	UsedSecrets      []helpers.NamespacedName
+	Domains          []string
+	ExtensionConfigs []filters.ExtensionConfigRef
 }
 
 // BuildResources is the main entry point for building Envoy resources using the modular architecture
@@ -2,8 +2,11 @@ package resbuilder
 
 import (
 	"testing"
+	"time"
 
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	localratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	tcpProxyv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 	"github.com/kaasops/envoy-xds-controller/internal/store"
@@ -489,3 +492,151 @@ func TestGetTLSType(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildResources_ScopedRoutes(t *testing.T) {
+	s := createBaseStore()
+	listener := createListenerCR("http-listener", 8080, false)
+	listener.Annotations = map[string]string{v1alpha1.AnnotationRouteDiscovery: v1alpha1.RouteDiscoveryScopedRDS}
+	s.SetListener(listener)
+
+	newVS := func(domains ...string) *v1alpha1.VirtualService {
+		return &v1alpha1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Name: "scoped-vs", Namespace: "default"},
+			Spec: v1alpha1.VirtualServiceSpec{
+				VirtualServiceCommonSpec: v1alpha1.VirtualServiceCommonSpec{
+					Listener:    &v1alpha1.ResourceRef{Name: "http-listener"},
+					VirtualHost: &runtime.RawExtension{Raw: createVirtualHostRaw(domains)},
+				},
+			},
+		}
+	}
+
+	result, err := BuildResources(newVS("example.com", "example.com:8080", "api.example.com"), s)
+	require.NoError(t, err)
+
+	// Scopes are keyed by hosts without port and select the route configuration on demand
+	require.Len(t, result.ScopedRoutes, 2)
+	for i, host := range []string{"example.com", "api.example.com"} {
+		scope := result.ScopedRoutes[i]
+		assert.Equal(t, "default/scoped-vs/"+host, scope.GetName())
+		assert.Equal(t, result.RouteConfig.GetName(), scope.GetRouteConfigurationName())
+		assert.True(t, scope.GetOnDemand())
+		assert.Equal(t, host, scope.GetKey().GetFragments()[0].GetStringKey())
+	}
+
+	require.Len(t, result.FilterChain, 1)
+	hcm := &hcmv3.HttpConnectionManager{}
+	require.NoError(t, result.FilterChain[0].GetFilters()[0].GetTypedConfig().UnmarshalTo(hcm))
+	assert.Nil(t, hcm.GetRds())
+	assert.Equal(t, "default/http-listener", hcm.GetScopedRoutes().GetName())
+	assert.NotNil(t, hcm.GetScopedRoutes().GetScopedRds())
+	require.NotEmpty(t, hcm.GetHttpFilters())
+	assert.Equal(t, "envoy.filters.http.on_demand", hcm.GetHttpFilters()[0].GetName())
+
+	_, err = BuildResources(newVS("*.example.com"), s)
+	assert.ErrorContains(t, err, "wildcard domain *.example.com is not supported with scoped routes")
+}
+
+func TestBuildResources_RateLimitPolicy(t *testing.T) {
+	s := createBaseStore()
+	s.SetCluster(createClusterCR("ratelimit", "ratelimit.local", 9001))
+	s.SetRateLimitPolicy(&v1alpha1.RateLimitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "default"},
+		Spec: v1alpha1.RateLimitPolicySpec{
+			VirtualService: "ratelimit-vs",
+			Service:        v1alpha1.RateLimitService{Cluster: v1alpha1.ResourceRef{Name: "ratelimit"}},
+			Limits:         []v1alpha1.RateLimit{{Name: "global", Requests: 10, Unit: v1alpha1.RateLimitUnitSecond}},
+		},
+	})
+	vs := &v1alpha1.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Name: "ratelimit-vs", Namespace: "default"},
+		Spec: v1alpha1.VirtualServiceSpec{
+			VirtualServiceCommonSpec: v1alpha1.VirtualServiceCommonSpec{
+				Listener:    &v1alpha1.ResourceRef{Name: "http-listener"},
+				VirtualHost: &runtime.RawExtension{Raw: createVirtualHostRaw([]string{"ratelimit.example.com"})},
+				HTTPFilters: []*runtime.RawExtension{{Raw: []byte(`{"name":"envoy.filters.http.router",` +
+					`"typed_config":{"@type":"type.googleapis.com/envoy.extensions.filters.http.router.v3.Router"}}`)}},
+			},
+		},
+	}
+
+	result, err := BuildResources(vs, s)
+	require.NoError(t, err)
+
+	// The filter is served with the cluster of the service, ahead of the router
+	clusterNames := make([]string, 0, len(result.Clusters))
+	for _, cl := range result.Clusters {
+		clusterNames = append(clusterNames, cl.GetName())
+	}
+	assert.Contains(t, clusterNames, "ratelimit")
+
+	require.Len(t, result.FilterChain, 1)
+	hcm := &hcmv3.HttpConnectionManager{}
+	require.NoError(t, result.FilterChain[0].GetFilters()[0].GetTypedConfig().UnmarshalTo(hcm))
+	filterNames := make([]string, 0, len(hcm.GetHttpFilters()))
+	for _, filter := range hcm.GetHttpFilters() {
+		filterNames = append(filterNames, filter.GetName())
+	}
+	assert.Equal(t, []string{"envoy.filters.http.ratelimit", "envoy.filters.http.router"}, filterNames)
+
+	rateLimits := result.RouteConfig.GetVirtualHosts()[0].GetRoutes()[0].GetRoute().GetRateLimits()
+	require.Len(t, rateLimits, 1)
+	assert.Equal(t, "default/limits/global", rateLimits[0].GetActions()[0].GetGenericKey().GetDescriptorValue())
+
+	// Policies of a VirtualService must share their service
+	s.SetRateLimitPolicy(&v1alpha1.RateLimitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		Spec: v1alpha1.RateLimitPolicySpec{
+			VirtualService: "ratelimit-vs",
+			Service: v1alpha1.RateLimitService{
+				Cluster: v1alpha1.ResourceRef{Name: "ratelimit"},
+				Domain:  "other",
+			},
+			Limits: []v1alpha1.RateLimit{{Name: "global", Requests: 10, Unit: v1alpha1.RateLimitUnitSecond}},
+		},
+	})
+	_, err = BuildResources(vs, s)
+	assert.ErrorContains(t, err, "rate limit policies limits and other use different services")
+}
+
+func TestBuildResources_LocalRateLimit(t *testing.T) {
+	s := createBaseStore()
+	router := &runtime.RawExtension{Raw: []byte(`{"name":"envoy.filters.http.router",` +
+		`"typed_config":{"@type":"type.googleapis.com/envoy.extensions.filters.http.router.v3.Router"}}`)}
+	vs := &v1alpha1.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Name: "local-ratelimit-vs", Namespace: "default"},
+		Spec: v1alpha1.VirtualServiceSpec{
+			VirtualServiceCommonSpec: v1alpha1.VirtualServiceCommonSpec{
+				Listener:    &v1alpha1.ResourceRef{Name: "http-listener"},
+				VirtualHost: &runtime.RawExtension{Raw: createVirtualHostRaw([]string{"local.example.com"})},
+				HTTPFilters: []*runtime.RawExtension{router},
+				LocalRateLimit: &v1alpha1.LocalRateLimitSpec{
+					LocalRateLimitBucket: v1alpha1.LocalRateLimitBucket{
+						Tokens:       50,
+						FillInterval: &metav1.Duration{Duration: time.Second},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := BuildResources(vs, s)
+	require.NoError(t, err)
+	require.Len(t, result.FilterChain, 1)
+	hcm := &hcmv3.HttpConnectionManager{}
+	require.NoError(t, result.FilterChain[0].GetFilters()[0].GetTypedConfig().UnmarshalTo(hcm))
+	require.Len(t, hcm.GetHttpFilters(), 2)
+	assert.Equal(t, "envoy.filters.http.local_ratelimit", hcm.GetHttpFilters()[0].GetName())
+	config := &localratelimitv3.LocalRateLimit{}
+	require.NoError(t, hcm.GetHttpFilters()[0].GetTypedConfig().UnmarshalTo(config))
+	assert.Equal(t, uint32(50), config.GetTokenBucket().GetMaxTokens())
+	assert.Equal(t, uint32(100), config.GetFilterEnabled().GetDefaultValue().GetNumerator())
+
+	// The filter cannot also be set by http filters
+	localRateLimit := &runtime.RawExtension{Raw: []byte(`{"name":"envoy.filters.http.local_ratelimit",` +
+		`"typed_config":{"stat_prefix":"custom",` +
+		`"@type":"type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit"}}`)}
+	vs.Spec.HTTPFilters = []*runtime.RawExtension{localRateLimit, router}
+	_, err = BuildResources(vs, s)
+	assert.ErrorContains(t, err, "envoy.filters.http.local_ratelimit is set by both localRateLimit and http filters")
+}
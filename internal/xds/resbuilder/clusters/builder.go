@@ -9,6 +9,7 @@ import (
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	oauth2v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/oauth2/v3"
+	ratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
 	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
@@ -33,7 +34,7 @@ func NewBuilder(store store.Store) *Builder {
 
 // FromVirtualHostRoutes extracts clusters referenced by routes inside the given VirtualHost
 func (b *Builder) FromVirtualHostRoutes(virtualHost *routev3.VirtualHost) ([]*cluster.Cluster, error) {
-	var clusterNames []string
+	clusterNames := extractClusterNamesFromMirrorPolicies(virtualHost.RequestMirrorPolicies)
 
 	// Direct traversal of route structures instead of JSON marshaling
 	for _, route := range virtualHost.Routes {
@@ -48,7 +49,8 @@ func (b *Builder) FromVirtualHostRoutes(virtualHost *routev3.VirtualHost) ([]*cl
 	return b.getClustersByNames(clusterNames)
 }
 
-// extractClusterNamesFromRoute directly extracts cluster names from route configuration
+// extractClusterNamesFromRoute directly extracts cluster names from route configuration.
+// Clusters selected by cluster_header are only known at request time and are not extracted.
 func extractClusterNamesFromRoute(route *routev3.Route) []string {
 	var names []string
 
@@ -74,7 +76,10 @@ func extractClusterNamesFromRoute(route *routev3.Route) []string {
 					}
 				}
 			}
+		case *routev3.RouteAction_ClusterHeader:
+			// Resolved by Envoy from the request header
 		}
+		names = append(names, extractClusterNamesFromMirrorPolicies(action.Route.RequestMirrorPolicies)...)
 	case *routev3.Route_DirectResponse:
 		// Direct responses don't reference clusters
 	case *routev3.Route_Redirect:
@@ -84,6 +89,18 @@ func extractClusterNamesFromRoute(route *routev3.Route) []string {
 	return names
 }
 
+// extractClusterNamesFromMirrorPolicies extracts the clusters requests are mirrored to,
+// skipping policies selecting the cluster by cluster_header
+func extractClusterNamesFromMirrorPolicies(policies []*routev3.RouteAction_RequestMirrorPolicy) []string {
+	var names []string
+	for _, policy := range policies {
+		if policy.GetCluster() != "" {
+			names = append(names, policy.GetCluster())
+		}
+	}
+	return names
+}
+
 // FromOAuth2HTTPFilters extracts clusters referenced by OAuth2 HTTP filters (token/authorize/etc)
 func (b *Builder) FromOAuth2HTTPFilters(httpFilters []*hcmv3.HttpFilter) ([]*cluster.Cluster, error) {
 	// Check cache first
@@ -124,6 +141,28 @@ func (b *Builder) FromOAuth2HTTPFilters(httpFilters []*hcmv3.HttpFilter) ([]*clu
 	return clusters, nil
 }
 
+// FromRateLimitHTTPFilters extracts clusters of rate limit services of rate limit HTTP filters
+func (b *Builder) FromRateLimitHTTPFilters(httpFilters []*hcmv3.HttpFilter) ([]*cluster.Cluster, error) {
+	var names []string
+	for _, httpFilter := range httpFilters {
+		tc := httpFilter.GetTypedConfig()
+		if tc == nil || tc.TypeUrl != utils.TypeURLRateLimit {
+			continue
+		}
+		var rateLimitCfg ratelimitv3.RateLimit
+		if err := tc.UnmarshalTo(&rateLimitCfg); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal rate limit config: %w", err)
+		}
+		if name := rateLimitCfg.GetRateLimitService().GetGrpcService().GetEnvoyGrpc().GetClusterName(); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return b.getClustersByNames(names)
+}
+
 // FromTracingRaw extracts clusters referenced by inline tracing configuration
 func (b *Builder) FromTracingRaw(tr *runtime.RawExtension) ([]*cluster.Cluster, error) {
 	if tr == nil {
@@ -250,7 +289,7 @@ func (b *Builder) getClustersByNames(names []string) ([]*cluster.Cluster, error)
 	for _, clusterName := range names {
 		cl := b.store.GetSpecCluster(clusterName)
 		if cl == nil {
-			return nil, fmt.Errorf("cluster %s not found", clusterName)
+			return nil, utils.NewNotFoundError(utils.KindCluster, "cluster %s not found", clusterName)
 		}
 		xdsCluster, err := cl.UnmarshalV3AndValidate()
 		if err != nil {
@@ -406,7 +445,21 @@ func (b *Builder) ExtractClustersFromVirtualHost(virtualHost *routev3.VirtualHos
 
 // ExtractClustersFromHTTPFilters extracts clusters from HTTP filters
 func (b *Builder) ExtractClustersFromHTTPFilters(httpFilters []*hcmv3.HttpFilter) ([]*cluster.Cluster, error) {
-	return b.FromOAuth2HTTPFilters(httpFilters)
+	clusters, err := b.FromOAuth2HTTPFilters(httpFilters)
+	if err != nil {
+		return nil, err
+	}
+	rateLimitClusters, err := b.FromRateLimitHTTPFilters(httpFilters)
+	if err != nil {
+		return nil, err
+	}
+	if len(rateLimitClusters) == 0 {
+		return clusters, nil
+	}
+	// OAuth2 clusters may be cached, they are not appended to
+	result := make([]*cluster.Cluster, 0, len(clusters)+len(rateLimitClusters))
+	result = append(result, clusters...)
+	return append(result, rateLimitClusters...), nil
 }
 
 // ExtractClustersFromTracingRaw extracts clusters from inline tracing configuration
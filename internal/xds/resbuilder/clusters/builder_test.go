@@ -0,0 +1,50 @@
+package clusters
+
+import (
+	"testing"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromVirtualHostRoutes_MirrorPolicies(t *testing.T) {
+	mockStore := store.New()
+	for _, name := range []string{"backend", "route-mirror", "vhost-mirror"} {
+		createClusterInStore(mockStore, name)
+	}
+	builder := NewBuilder(mockStore)
+
+	virtualHost := &routev3.VirtualHost{
+		RequestMirrorPolicies: []*routev3.RouteAction_RequestMirrorPolicy{{Cluster: "vhost-mirror"}},
+		Routes: []*routev3.Route{
+			{
+				Action: &routev3.Route_Route{Route: &routev3.RouteAction{
+					ClusterSpecifier: &routev3.RouteAction_Cluster{Cluster: "backend"},
+					RequestMirrorPolicies: []*routev3.RouteAction_RequestMirrorPolicy{
+						{Cluster: "route-mirror"},
+						{ClusterHeader: "x-mirror-cluster"},
+					},
+				}},
+			},
+			{
+				Action: &routev3.Route_Route{Route: &routev3.RouteAction{
+					ClusterSpecifier: &routev3.RouteAction_ClusterHeader{ClusterHeader: "x-cluster"},
+				}},
+			},
+		},
+	}
+
+	clusters, err := builder.FromVirtualHostRoutes(virtualHost)
+	require.NoError(t, err)
+	names := make([]string, 0, len(clusters))
+	for _, cl := range clusters {
+		names = append(names, cl.Name)
+	}
+	assert.Equal(t, []string{"vhost-mirror", "backend", "route-mirror"}, names)
+
+	virtualHost.Routes[0].GetRoute().RequestMirrorPolicies[0].Cluster = "missing"
+	_, err = builder.FromVirtualHostRoutes(virtualHost)
+	assert.EqualError(t, err, "cluster missing not found")
+}
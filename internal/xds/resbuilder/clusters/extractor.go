@@ -61,7 +61,7 @@ func ExtractClustersFromFilterChains(
 		cl := store.GetSpecCluster(result.name)
 		if cl == nil {
 			if result.isRequired {
-				return nil, fmt.Errorf("cluster %s not found", result.name)
+				return nil, utils.NewNotFoundError(utils.KindCluster, "cluster %s not found", result.name)
 			}
 			// For optional clusters (from generic extraction), skip with warning
 			// This handles false positives where a field named "cluster" doesn't
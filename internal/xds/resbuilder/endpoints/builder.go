@@ -0,0 +1,262 @@
+package endpoints
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"go.uber.org/multierr"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+// ServiceRef is a port of a Kubernetes Service referenced by an EDS cluster
+// with the service name "<namespace>/<service>:<port>". Port is the name of
+// a Service port or the port number of its endpoints, and may be omitted for
+// Services with a single port.
+type ServiceRef struct {
+	Service helpers.NamespacedName
+	Port    string
+}
+
+// ParseServiceRef parses an EDS service name referencing a Kubernetes Service
+func ParseServiceRef(name string) (ServiceRef, bool) {
+	namespace, rest, ok := strings.Cut(name, "/")
+	if !ok || namespace == "" || rest == "" {
+		return ServiceRef{}, false
+	}
+	service, port, _ := strings.Cut(rest, ":")
+	if service == "" || strings.Contains(service, "/") {
+		return ServiceRef{}, false
+	}
+	return ServiceRef{Service: helpers.NamespacedName{Namespace: namespace, Name: service}, Port: port}, true
+}
+
+// ServiceName returns the name of the load assignment of an EDS cluster:
+// eds_cluster_config.service_name, or the cluster name if it is not set.
+func ServiceName(cl *clusterv3.Cluster) (string, bool) {
+	if cl.GetType() != clusterv3.Cluster_EDS {
+		return "", false
+	}
+	if name := cl.GetEdsClusterConfig().GetServiceName(); name != "" {
+		return name, true
+	}
+	return cl.GetName(), true
+}
+
+// Builder resolves load assignments of EDS clusters from Endpoint resources
+// and Kubernetes EndpointSlices. Results are cached, so a Builder should be
+// used for a single snapshot rebuild only.
+type Builder struct {
+	store  store.Store
+	cache  map[string]*endpointv3.ClusterLoadAssignment
+	errors map[string]error
+}
+
+// NewBuilder creates a new load assignment builder
+func NewBuilder(store store.Store) *Builder {
+	return &Builder{
+		store:  store,
+		cache:  make(map[string]*endpointv3.ClusterLoadAssignment),
+		errors: make(map[string]error),
+	}
+}
+
+// Build returns a load assignment for every EDS cluster of the given clusters, sorted by name.
+// Load assignments that can not be resolved are empty, so the snapshot stays consistent
+// and Envoy keeps the cluster without hosts; Errors reports them.
+func (b *Builder) Build(clusters []types.Resource) []types.Resource {
+	var result []types.Resource
+	seen := make(map[string]struct{})
+	for _, res := range clusters {
+		cl, ok := res.(*clusterv3.Cluster)
+		if !ok {
+			continue
+		}
+		name, ok := ServiceName(cl)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		cla, _ := b.LoadAssignment(name)
+		result = append(result, cla)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].(*endpointv3.ClusterLoadAssignment).ClusterName <
+			result[j].(*endpointv3.ClusterLoadAssignment).ClusterName
+	})
+	return result
+}
+
+// Errors returns the errors of all load assignments resolved so far
+func (b *Builder) Errors() error {
+	names := make([]string, 0, len(b.errors))
+	for name, err := range b.errors {
+		if err != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	errs := make([]error, 0, len(names))
+	for _, name := range names {
+		errs = append(errs, b.errors[name])
+	}
+	return multierr.Combine(errs...)
+}
+
+// LoadAssignment resolves the load assignment with the given name. An Endpoint resource
+// with a matching cluster_name takes precedence over a Kubernetes Service reference.
+func (b *Builder) LoadAssignment(name string) (*endpointv3.ClusterLoadAssignment, error) {
+	if cla, ok := b.cache[name]; ok {
+		return cla, b.errors[name]
+	}
+	cla, err := b.loadAssignment(name)
+	if err != nil {
+		cla = &endpointv3.ClusterLoadAssignment{ClusterName: name}
+	}
+	b.cache[name] = cla
+	b.errors[name] = err
+	return cla, err
+}
+
+func (b *Builder) loadAssignment(name string) (*endpointv3.ClusterLoadAssignment, error) {
+	if endpoint := b.store.GetSpecEndpoint(name); endpoint != nil {
+		cla, err := endpoint.UnmarshalV3AndValidate()
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s/%s: %w", endpoint.Namespace, endpoint.Name, err)
+		}
+		return cla, nil
+	}
+	if ref, ok := ParseServiceRef(name); ok {
+		return FromEndpointSlices(name, ref, b.store.GetEndpointSlicesForService(ref.Service))
+	}
+	return nil, fmt.Errorf("load assignment %s not found", name)
+}
+
+// FromEndpointSlices builds a load assignment from the ready endpoints of a Service,
+// grouped by zone. Terminating endpoints that are still serving are draining.
+func FromEndpointSlices(
+	name string,
+	ref ServiceRef,
+	slices []*discoveryv1.EndpointSlice,
+) (*endpointv3.ClusterLoadAssignment, error) {
+	byZone := make(map[string][]*endpointv3.LbEndpoint)
+	seen := make(map[string]struct{})
+	portFound := false
+
+	for _, slice := range slices {
+		if slice.AddressType != discoveryv1.AddressTypeIPv4 && slice.AddressType != discoveryv1.AddressTypeIPv6 {
+			continue
+		}
+		port, ok := slicePort(slice, ref.Port)
+		if !ok {
+			continue
+		}
+		portFound = true
+		for _, ep := range slice.Endpoints {
+			health, ok := endpointHealth(ep.Conditions)
+			if !ok {
+				continue
+			}
+			zone := ""
+			if ep.Zone != nil {
+				zone = *ep.Zone
+			}
+			for _, address := range ep.Addresses {
+				key := address + ":" + strconv.Itoa(int(port))
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+				byZone[zone] = append(byZone[zone], &endpointv3.LbEndpoint{
+					HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+						Endpoint: &endpointv3.Endpoint{
+							Address: &corev3.Address{
+								Address: &corev3.Address_SocketAddress{
+									SocketAddress: &corev3.SocketAddress{
+										Address:       address,
+										PortSpecifier: &corev3.SocketAddress_PortValue{PortValue: uint32(port)},
+									},
+								},
+							},
+						},
+					},
+					HealthStatus: health,
+				})
+			}
+		}
+	}
+
+	if len(slices) > 0 && !portFound {
+		return nil, fmt.Errorf("service %s has no port %q", ref.Service.String(), ref.Port)
+	}
+
+	zones := make([]string, 0, len(byZone))
+	for zone := range byZone {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+
+	cla := &endpointv3.ClusterLoadAssignment{ClusterName: name}
+	for _, zone := range zones {
+		lbEndpoints := byZone[zone]
+		sort.Slice(lbEndpoints, func(i, j int) bool {
+			return lbEndpointKey(lbEndpoints[i]) < lbEndpointKey(lbEndpoints[j])
+		})
+		locality := &endpointv3.LocalityLbEndpoints{LbEndpoints: lbEndpoints}
+		if zone != "" {
+			locality.Locality = &corev3.Locality{Zone: zone}
+		}
+		cla.Endpoints = append(cla.Endpoints, locality)
+	}
+	return cla, nil
+}
+
+// slicePort returns the port number of the slice matching the referenced port
+func slicePort(slice *discoveryv1.EndpointSlice, ref string) (int32, bool) {
+	if ref == "" {
+		if len(slice.Ports) == 1 && slice.Ports[0].Port != nil {
+			return *slice.Ports[0].Port, true
+		}
+		return 0, false
+	}
+	number, err := strconv.Atoi(ref)
+	for _, p := range slice.Ports {
+		if p.Port == nil {
+			continue
+		}
+		if p.Name != nil && *p.Name == ref {
+			return *p.Port, true
+		}
+		if err == nil && int(*p.Port) == number {
+			return *p.Port, true
+		}
+	}
+	return 0, false
+}
+
+// endpointHealth maps EndpointSlice conditions to an Envoy health status.
+// Endpoints which are neither ready nor serving are skipped.
+func endpointHealth(conditions discoveryv1.EndpointConditions) (corev3.HealthStatus, bool) {
+	if conditions.Ready == nil || *conditions.Ready {
+		return corev3.HealthStatus_HEALTHY, true
+	}
+	if conditions.Serving != nil && *conditions.Serving {
+		return corev3.HealthStatus_DRAINING, true
+	}
+	return corev3.HealthStatus_UNKNOWN, false
+}
+
+func lbEndpointKey(ep *endpointv3.LbEndpoint) string {
+	address := ep.GetEndpoint().GetAddress().GetSocketAddress()
+	return address.GetAddress() + ":" + strconv.Itoa(int(address.GetPortValue()))
+}
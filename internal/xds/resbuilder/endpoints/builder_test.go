@@ -0,0 +1,90 @@
+package endpoints
+
+import (
+	"testing"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// TestParseServiceRef verifies parsing of Kubernetes Service references in EDS service names.
+func TestParseServiceRef(t *testing.T) {
+	tests := []struct {
+		name string
+		want ServiceRef
+		ok   bool
+	}{
+		{name: "ns/backend:http", want: ServiceRef{Service: helpers.NamespacedName{Namespace: "ns", Name: "backend"}, Port: "http"}, ok: true},
+		{name: "ns/backend", want: ServiceRef{Service: helpers.NamespacedName{Namespace: "ns", Name: "backend"}}, ok: true},
+		{name: "backend"},
+		{name: "/backend:80"},
+		{name: "ns/:80"},
+		{name: "ns/a/b:80"},
+	}
+	for _, tt := range tests {
+		got, ok := ParseServiceRef(tt.name)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseServiceRef(%q) = %+v, %v; want %+v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// TestFromEndpointSlices verifies that ready and serving endpoints of the referenced port
+// are grouped by zone, and endpoints duplicated across slices are included once.
+func TestFromEndpointSlices(t *testing.T) {
+	ref, _ := ParseServiceRef("ns/backend:http")
+	slices := []*discoveryv1.EndpointSlice{
+		{
+			ObjectMeta:  metav1.ObjectMeta{Namespace: "ns", Name: "backend-a"},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports: []discoveryv1.EndpointPort{
+				{Name: ptr.To("metrics"), Port: ptr.To[int32](9090)},
+				{Name: ptr.To("http"), Port: ptr.To[int32](8080)},
+			},
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.2"}, Zone: ptr.To("zone-b")},
+				{Addresses: []string{"10.0.0.1"}, Zone: ptr.To("zone-a"), Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)}},
+				{Addresses: []string{"10.0.0.3"}, Zone: ptr.To("zone-a"), Conditions: discoveryv1.EndpointConditions{
+					Ready: ptr.To(false), Serving: ptr.To(true), Terminating: ptr.To(true),
+				}},
+				{Addresses: []string{"10.0.0.4"}, Zone: ptr.To("zone-a"), Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)}},
+			},
+		},
+		{
+			ObjectMeta:  metav1.ObjectMeta{Namespace: "ns", Name: "backend-b"},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports:       []discoveryv1.EndpointPort{{Name: ptr.To("http"), Port: ptr.To[int32](8080)}},
+			Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}, Zone: ptr.To("zone-a")}},
+		},
+	}
+
+	cla, err := FromEndpointSlices("ns/backend:http", ref, slices)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cla.Endpoints) != 2 || cla.Endpoints[0].Locality.GetZone() != "zone-a" || cla.Endpoints[1].Locality.GetZone() != "zone-b" {
+		t.Fatalf("expected localities zone-a and zone-b, got %v", cla.Endpoints)
+	}
+	zoneA := cla.Endpoints[0].LbEndpoints
+	if len(zoneA) != 2 || lbEndpointKey(zoneA[0]) != "10.0.0.1:8080" || lbEndpointKey(zoneA[1]) != "10.0.0.3:8080" {
+		t.Fatalf("unexpected endpoints in zone-a: %v", zoneA)
+	}
+	if zoneA[0].HealthStatus != corev3.HealthStatus_HEALTHY || zoneA[1].HealthStatus != corev3.HealthStatus_DRAINING {
+		t.Errorf("unexpected health statuses: %v, %v", zoneA[0].HealthStatus, zoneA[1].HealthStatus)
+	}
+
+	// A port the Service does not have is an error
+	missing, _ := ParseServiceRef("ns/backend:grpc")
+	if _, err := FromEndpointSlices("ns/backend:grpc", missing, slices); err == nil {
+		t.Errorf("expected error for unknown port")
+	}
+	// Port numbers refer to the ports of the endpoints
+	byNumber, _ := ParseServiceRef("ns/backend:9090")
+	if cla, err := FromEndpointSlices("ns/backend:9090", byNumber, slices[:1]); err != nil ||
+		lbEndpointKey(cla.Endpoints[0].LbEndpoints[0]) != "10.0.0.1:9090" {
+		t.Errorf("expected endpoints on port 9090, got %v (err %v)", cla, err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	ondemandv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/on_demand/v3"
 	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
@@ -93,6 +94,13 @@ func (b *Builder) buildFilterChain(params *FilterChainsParams) (*listenerv3.Filt
 		HttpFilters:      params.HTTPFilters,
 	}
 
+	// Scoped routes select the route configuration by the host of the request
+	if params.ScopedRoutesName != "" {
+		if err := configureScopedRoutes(httpConnectionManager, params.ScopedRoutesName); err != nil {
+			return nil, err
+		}
+	}
+
 	// Add HTTP/2 protocol options: use custom if provided, otherwise use defaults for TLS listeners
 	// See: https://www.envoyproxy.io/docs/envoy/latest/configuration/best_practices/edge
 	if params.Http2ProtocolOptions != nil {
@@ -172,6 +180,51 @@ func (b *Builder) buildFilterChain(params *FilterChainsParams) (*listenerv3.Filt
 	return fc, nil
 }
 
+// configureScopedRoutes replaces the RDS route specifier of the HCM by scoped routes discovered
+// over SRDS. Scopes are keyed by the host of the request without port, and their route
+// configurations are fetched on demand by the on_demand filter, which goes first.
+func configureScopedRoutes(hcm *hcmv3.HttpConnectionManager, name string) error {
+	adsConfigSource := &corev3.ConfigSource{
+		ResourceApiVersion:    corev3.ApiVersion_V3,
+		ConfigSourceSpecifier: &corev3.ConfigSource_Ads{},
+	}
+	hcm.RouteSpecifier = &hcmv3.HttpConnectionManager_ScopedRoutes{
+		ScopedRoutes: &hcmv3.ScopedRoutes{
+			Name: name,
+			ScopeKeyBuilder: &hcmv3.ScopedRoutes_ScopeKeyBuilder{
+				Fragments: []*hcmv3.ScopedRoutes_ScopeKeyBuilder_FragmentBuilder{{
+					Type: &hcmv3.ScopedRoutes_ScopeKeyBuilder_FragmentBuilder_HeaderValueExtractor_{
+						HeaderValueExtractor: &hcmv3.ScopedRoutes_ScopeKeyBuilder_FragmentBuilder_HeaderValueExtractor{
+							Name:             ":authority",
+							ElementSeparator: ":",
+							ExtractType: &hcmv3.ScopedRoutes_ScopeKeyBuilder_FragmentBuilder_HeaderValueExtractor_Index{
+								Index: 0,
+							},
+						},
+					},
+				}},
+			},
+			RdsConfigSource: adsConfigSource,
+			ConfigSpecifier: &hcmv3.ScopedRoutes_ScopedRds{
+				ScopedRds: &hcmv3.ScopedRds{ScopedRdsConfigSource: adsConfigSource},
+			},
+		},
+	}
+
+	onDemand, err := anypb.New(&ondemandv3.OnDemand{})
+	if err != nil {
+		return fmt.Errorf("failed to marshal on demand filter to Any: %w", err)
+	}
+	// HTTP filters may be shared with other filter chains, so they are copied
+	httpFilters := make([]*hcmv3.HttpFilter, 0, len(hcm.HttpFilters)+1)
+	httpFilters = append(httpFilters, &hcmv3.HttpFilter{
+		Name:       "envoy.filters.http.on_demand",
+		ConfigType: &hcmv3.HttpFilter_TypedConfig{TypedConfig: onDemand},
+	})
+	hcm.HttpFilters = append(httpFilters, hcm.HttpFilters...)
+	return nil
+}
+
 // FilterChainBuilder interface implementation
 // These methods implement the interfaces.FilterChainBuilder interface
 
@@ -14,6 +14,7 @@ type FilterChainsParams struct {
 	UseRemoteAddress     bool
 	XFFNumTrustedHops    *uint32
 	RouteConfigName      string
+	ScopedRoutesName     string
 	StatPrefix           string
 	HTTPFilters          []*hcmv3.HttpFilter
 	UpgradeConfigs       []*hcmv3.HttpConnectionManager_UpgradeConfig
@@ -53,12 +53,31 @@ func (b *Builder) generateHTTPFiltersCacheKey(vs *v1alpha1.VirtualService) strin
 
 		// Include the actual filter content from store
 		if hf := b.store.GetHTTPFilter(helpers.NamespacedName{Namespace: refNs, Name: filterRef.Name}); hf != nil {
+			hasher.Write([]byte(hf.GetAnnotations()[v1alpha1.AnnotationConfigDiscovery]))
 			for _, spec := range hf.Spec {
 				hasher.Write(spec.Raw)
 			}
 		}
 	}
 
+	// Include the local rate limit
+	if vs.Spec.LocalRateLimit != nil {
+		if localRateLimitData, err := json.Marshal(vs.Spec.LocalRateLimit); err == nil {
+			hasher.Write(localRateLimitData)
+		}
+	}
+
+	// Include RateLimitPolicies and the Clusters of their services
+	for _, policy := range b.rateLimitPolicies(vs) {
+		if policyData, err := json.Marshal(policy.Spec.Service); err == nil {
+			hasher.Write([]byte(policy.Name))
+			hasher.Write(policyData)
+		}
+		if cl := b.store.GetCluster(policy.GetServiceClusterNamespacedName()); cl != nil && cl.Spec != nil {
+			hasher.Write(cl.Spec.Raw)
+		}
+	}
+
 	return fmt.Sprintf("%x", hasher.Sum(nil))
 }
 
@@ -111,19 +130,35 @@ func (b *Builder) BuildHTTPFilters(vs *v1alpha1.VirtualService) ([]*hcmv3.HttpFi
 			if hf == nil {
 				return nil, fmt.Errorf("http filter %s/%s not found", httpFilterRefNs, httpFilterRef.Name)
 			}
-			for _, filter := range hf.Spec {
-				xdsHttpFilter := &hcmv3.HttpFilter{}
-				if err := protoutil.Unmarshaler.Unmarshal(filter.Raw, xdsHttpFilter); err != nil {
-					return nil, err
-				}
-				if err := xdsHttpFilter.ValidateAll(); err != nil {
-					return nil, err
-				}
-				httpFilters = append(httpFilters, xdsHttpFilter)
+			xdsHttpFilters, err := HTTPFilters(hf)
+			if err != nil {
+				return nil, err
 			}
+			httpFilters = append(httpFilters, xdsHttpFilters...)
 		}
 	}
 
+	localRateLimitF, err := b.buildLocalRateLimitFilter(vs)
+	if err != nil {
+		return nil, err
+	}
+	if localRateLimitF != nil {
+		for _, hf := range httpFilters {
+			if hf.GetName() == LocalRateLimitFilterName {
+				return nil, fmt.Errorf("http filter %s is set by both localRateLimit and http filters", hf.GetName())
+			}
+		}
+		httpFilters = append(httpFilters, localRateLimitF)
+	}
+
+	rateLimitF, err := b.buildRateLimitFilter(vs)
+	if err != nil {
+		return nil, err
+	}
+	if rateLimitF != nil {
+		httpFilters = append(httpFilters, rateLimitF)
+	}
+
 	// filter with Router type must be in the end
 	var routerIdxs []int
 	for i, f := range httpFilters {
@@ -0,0 +1,160 @@
+package filters
+
+import (
+	"fmt"
+	"sort"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/utils"
+)
+
+// ExtensionConfigRef references a filter of an HttpFilter served over ECDS. The extension
+// config is named after the filter, as HCMs and per-route configs reference it by name.
+type ExtensionConfigRef struct {
+	HTTPFilter helpers.NamespacedName
+	Name       string
+}
+
+// HTTPFilters returns the filters of an HttpFilter as they are referenced by HCMs. Filters of an
+// HttpFilter served over ECDS reference their extension configs by config_discovery, except
+// the router filter, which terminates the chain and stays inline.
+func HTTPFilters(hf *v1alpha1.HttpFilter) ([]*hcmv3.HttpFilter, error) {
+	httpFilters, err := hf.UnmarshalV3AndValidate()
+	if err != nil {
+		return nil, err
+	}
+	if !hf.IsConfigDiscovery() {
+		return httpFilters, nil
+	}
+	for i, httpFilter := range httpFilters {
+		if isDiscovered(httpFilter) {
+			httpFilters[i] = configDiscoveryFilter(httpFilter)
+		}
+	}
+	return httpFilters, nil
+}
+
+// ExtensionConfigRefs returns the filters of HttpFilters referenced by the VirtualService
+// that are served over ECDS. HttpFilters that are missing or invalid are skipped, building
+// the HTTP filters of the VirtualService reports them.
+func ExtensionConfigRefs(vs *v1alpha1.VirtualService, store store.Store) []ExtensionConfigRef {
+	var refs []ExtensionConfigRef
+	for _, httpFilterRef := range vs.Spec.AdditionalHttpFilters {
+		nn := helpers.NamespacedName{
+			Namespace: helpers.GetNamespace(httpFilterRef.Namespace, vs.Namespace),
+			Name:      httpFilterRef.Name,
+		}
+		hf := store.GetHTTPFilter(nn)
+		if hf == nil || !hf.IsConfigDiscovery() {
+			continue
+		}
+		httpFilters, err := hf.UnmarshalV3()
+		if err != nil {
+			continue
+		}
+		for _, httpFilter := range httpFilters {
+			if isDiscovered(httpFilter) {
+				refs = append(refs, ExtensionConfigRef{HTTPFilter: nn, Name: httpFilter.GetName()})
+			}
+		}
+	}
+	return refs
+}
+
+// ExtensionConfigBuilder resolves extension configs of filters served over ECDS from
+// HttpFilter resources. Results are cached, so a Builder should be used for a single
+// snapshot rebuild only.
+type ExtensionConfigBuilder struct {
+	store store.Store
+	cache map[ExtensionConfigRef]*corev3.TypedExtensionConfig
+}
+
+// NewExtensionConfigBuilder creates a new extension config builder
+func NewExtensionConfigBuilder(store store.Store) *ExtensionConfigBuilder {
+	return &ExtensionConfigBuilder{
+		store: store,
+		cache: make(map[ExtensionConfigRef]*corev3.TypedExtensionConfig),
+	}
+}
+
+// Build returns the extension configs of the references, sorted by name. A node is served
+// one extension config per name, so references of different HttpFilters to filters of the
+// same name fail the build.
+func (b *ExtensionConfigBuilder) Build(refs []ExtensionConfigRef) ([]types.Resource, error) {
+	byName := make(map[string]ExtensionConfigRef, len(refs))
+	for _, ref := range refs {
+		if prev, ok := byName[ref.Name]; ok && prev.HTTPFilter != ref.HTTPFilter {
+			return nil, fmt.Errorf("extension config %s is served by http filters %s and %s",
+				ref.Name, prev.HTTPFilter.String(), ref.HTTPFilter.String())
+		}
+		byName[ref.Name] = ref
+	}
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]types.Resource, 0, len(names))
+	for _, name := range names {
+		ref := byName[name]
+		tec, ok := b.cache[ref]
+		if !ok {
+			var err error
+			if tec, err = b.build(ref); err != nil {
+				return nil, err
+			}
+			b.cache[ref] = tec
+		}
+		result = append(result, tec)
+	}
+	return result, nil
+}
+
+func (b *ExtensionConfigBuilder) build(ref ExtensionConfigRef) (*corev3.TypedExtensionConfig, error) {
+	hf := b.store.GetHTTPFilter(ref.HTTPFilter)
+	if hf == nil {
+		return nil, fmt.Errorf("http filter %s not found", ref.HTTPFilter.String())
+	}
+	if !hf.IsConfigDiscovery() {
+		return nil, fmt.Errorf("http filter %s is not served with config discovery", ref.HTTPFilter.String())
+	}
+	httpFilters, err := hf.UnmarshalV3AndValidate()
+	if err != nil {
+		return nil, fmt.Errorf("http filter %s: %w", ref.HTTPFilter.String(), err)
+	}
+	for _, httpFilter := range httpFilters {
+		if httpFilter.GetName() == ref.Name && isDiscovered(httpFilter) {
+			return &corev3.TypedExtensionConfig{Name: ref.Name, TypedConfig: httpFilter.GetTypedConfig()}, nil
+		}
+	}
+	return nil, fmt.Errorf("http filter %s has no filter %s", ref.HTTPFilter.String(), ref.Name)
+}
+
+// isDiscovered reports whether a filter of an HttpFilter served over ECDS is discovered
+func isDiscovered(httpFilter *hcmv3.HttpFilter) bool {
+	return httpFilter.GetTypedConfig().GetTypeUrl() != utils.TypeURLRouter
+}
+
+// configDiscoveryFilter returns the filter referencing its extension config over ADS
+func configDiscoveryFilter(httpFilter *hcmv3.HttpFilter) *hcmv3.HttpFilter {
+	return &hcmv3.HttpFilter{
+		Name:       httpFilter.GetName(),
+		IsOptional: httpFilter.GetIsOptional(),
+		Disabled:   httpFilter.GetDisabled(),
+		ConfigType: &hcmv3.HttpFilter_ConfigDiscovery{
+			ConfigDiscovery: &corev3.ExtensionConfigSource{
+				ConfigSource: &corev3.ConfigSource{
+					ResourceApiVersion:    corev3.ApiVersion_V3,
+					ConfigSourceSpecifier: &corev3.ConfigSource_Ads{Ads: &corev3.AggregatedConfigSource{}},
+				},
+				TypeUrls: []string{httpFilter.GetTypedConfig().GetTypeUrl()},
+			},
+		},
+	}
+}
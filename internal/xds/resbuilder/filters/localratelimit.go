@@ -0,0 +1,76 @@
+package filters
+
+import (
+	"fmt"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	localratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	// LocalRateLimitFilterName is the name of the local rate limit filter of VirtualServices
+	// with a localRateLimit, also used for the typed_per_filter_config of routes
+	LocalRateLimitFilterName = "envoy.filters.http.local_ratelimit"
+
+	localRateLimitStatPrefix = "local_rate_limit"
+	// Runtime keys turning off the filter, or only the rejection of requests
+	localRateLimitEnabledRuntimeKey  = "local_rate_limit_enabled"
+	localRateLimitEnforcedRuntimeKey = "local_rate_limit_enforced"
+)
+
+// LocalRateLimitConfig returns the local rate limit config of the bucket, without token bucket
+// if it is not set.
+func LocalRateLimitConfig(bucket v1alpha1.LocalRateLimitBucket) (*anypb.Any, error) {
+	config := &localratelimitv3.LocalRateLimit{StatPrefix: localRateLimitStatPrefix}
+	if bucket.IsSet() {
+		maxTokens := bucket.MaxTokens
+		if maxTokens == 0 {
+			maxTokens = bucket.Tokens
+		}
+		config.TokenBucket = &typev3.TokenBucket{
+			MaxTokens:     maxTokens,
+			TokensPerFill: wrapperspb.UInt32(bucket.Tokens),
+			FillInterval:  durationpb.New(bucket.FillInterval.Duration),
+		}
+		config.FilterEnabled = fullRuntimeFractionalPercent(localRateLimitEnabledRuntimeKey)
+		config.FilterEnforced = fullRuntimeFractionalPercent(localRateLimitEnforcedRuntimeKey)
+	}
+	if err := config.ValidateAll(); err != nil {
+		return nil, fmt.Errorf("failed to validate local rate limit: %w", err)
+	}
+	return anypb.New(config)
+}
+
+func fullRuntimeFractionalPercent(runtimeKey string) *corev3.RuntimeFractionalPercent {
+	return &corev3.RuntimeFractionalPercent{
+		DefaultValue: &typev3.FractionalPercent{Numerator: 100, Denominator: typev3.FractionalPercent_HUNDRED},
+		RuntimeKey:   runtimeKey,
+	}
+}
+
+// buildLocalRateLimitFilter builds the filter limiting requests of the VirtualService with the
+// bucket of its localRateLimit, nil if it has none. Overrides of routes are added to them by
+// the routes builder.
+func (b *Builder) buildLocalRateLimitFilter(vs *v1alpha1.VirtualService) (*hcmv3.HttpFilter, error) {
+	spec := vs.Spec.LocalRateLimit
+	if spec == nil {
+		return nil, nil
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("local rate limit: %w", err)
+	}
+	typedConfig, err := LocalRateLimitConfig(spec.LocalRateLimitBucket)
+	if err != nil {
+		return nil, err
+	}
+	return &hcmv3.HttpFilter{
+		Name:       LocalRateLimitFilterName,
+		ConfigType: &hcmv3.HttpFilter_TypedConfig{TypedConfig: typedConfig},
+	}, nil
+}
@@ -0,0 +1,83 @@
+package filters
+
+import (
+	"fmt"
+	"reflect"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	ratelimitconfv3 "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v3"
+	ratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/utils"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RateLimitFilterName is the name of the rate limit filter of VirtualServices with RateLimitPolicies
+const RateLimitFilterName = "envoy.filters.http.ratelimit"
+
+// rateLimitPolicies returns the RateLimitPolicies of the VirtualService
+func (b *Builder) rateLimitPolicies(vs *v1alpha1.VirtualService) []*v1alpha1.RateLimitPolicy {
+	return b.store.GetRateLimitPoliciesForVirtualService(helpers.NamespacedName{Namespace: vs.Namespace, Name: vs.Name})
+}
+
+// buildRateLimitFilter builds the filter checking requests of the VirtualService against the
+// rate limit service of its RateLimitPolicies, nil if it has none. Descriptors of the limits
+// are added to its routes by the routes builder.
+func (b *Builder) buildRateLimitFilter(vs *v1alpha1.VirtualService) (*hcmv3.HttpFilter, error) {
+	policies := b.rateLimitPolicies(vs)
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	first := policies[0]
+	for _, policy := range policies[1:] {
+		if policy.GetServiceClusterNamespacedName() != first.GetServiceClusterNamespacedName() ||
+			policy.GetDomain() != first.GetDomain() ||
+			policy.Spec.Service.FailureModeDeny != first.Spec.Service.FailureModeDeny ||
+			!reflect.DeepEqual(policy.Spec.Service.Timeout, first.Spec.Service.Timeout) {
+			return nil, fmt.Errorf("rate limit policies %s and %s use different services", first.Name, policy.Name)
+		}
+	}
+
+	clusterNN := first.GetServiceClusterNamespacedName()
+	cl := b.store.GetCluster(clusterNN)
+	if cl == nil {
+		return nil, utils.NewNotFoundError(utils.KindCluster, "rate limit policy %s/%s: cluster %s not found",
+			first.Namespace, first.Name, clusterNN.String())
+	}
+	xdsCluster, err := cl.UnmarshalV3()
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cluster %s: %w", clusterNN.String(), err)
+	}
+
+	config := &ratelimitv3.RateLimit{
+		Domain:          first.GetDomain(),
+		FailureModeDeny: first.Spec.Service.FailureModeDeny,
+		RateLimitService: &ratelimitconfv3.RateLimitServiceConfig{
+			GrpcService: &corev3.GrpcService{
+				TargetSpecifier: &corev3.GrpcService_EnvoyGrpc_{
+					EnvoyGrpc: &corev3.GrpcService_EnvoyGrpc{ClusterName: xdsCluster.Name},
+				},
+			},
+			TransportApiVersion: corev3.ApiVersion_V3,
+		},
+	}
+	if first.Spec.Service.Timeout != nil {
+		config.Timeout = durationpb.New(first.Spec.Service.Timeout.Duration)
+	}
+	if err := config.ValidateAll(); err != nil {
+		return nil, fmt.Errorf("failed to validate rate limit filter: %w", err)
+	}
+
+	typedConfig, err := anypb.New(config)
+	if err != nil {
+		return nil, err
+	}
+	return &hcmv3.HttpFilter{
+		Name:       RateLimitFilterName,
+		ConfigType: &hcmv3.HttpFilter_TypedConfig{TypedConfig: typedConfig},
+	}, nil
+}
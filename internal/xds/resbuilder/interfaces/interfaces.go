@@ -33,6 +33,8 @@ type RoutingBuilder interface {
 	BuildRouteConfiguration(vs *v1alpha1.VirtualService, xdsListener *listenerv3.Listener,
 		nn helpers.NamespacedName) (*routev3.VirtualHost, *routev3.RouteConfiguration, error)
 	BuildVirtualHost(vs *v1alpha1.VirtualService, nn helpers.NamespacedName) (*routev3.VirtualHost, error)
+	BuildScopedRouteConfigurations(routeConfig *routev3.RouteConfiguration,
+		virtualHost *routev3.VirtualHost) ([]*routev3.ScopedRouteConfiguration, error)
 }
 
 // TLSBuilder is responsible for building TLS configuration
@@ -10,10 +10,12 @@ import (
 
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
 	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/filters"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/interfaces"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/utils"
 )
@@ -174,7 +176,7 @@ func (b *Builder) BuildResources(vs *v1alpha1.VirtualService) (interface{}, erro
 	// Apply template if specified
 	vs, err = b.applyVirtualServiceTemplate(vs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to apply template: %w", err)
+		return nil, &utils.TemplateError{Err: err}
 	}
 
 	// Build listener
@@ -229,7 +231,8 @@ func (b *Builder) applyVirtualServiceTemplate(vs *v1alpha1.VirtualService) (*v1a
 
 	vst := b.store.GetVirtualServiceTemplate(templateNN)
 	if vst == nil {
-		return nil, fmt.Errorf("virtual service template %s/%s not found", templateNamespace, templateName)
+		return nil, utils.NewNotFoundError(utils.KindTemplate, "virtual service template %s/%s not found",
+			templateNamespace, templateName)
 	}
 
 	vsCopy := vs.DeepCopy()
@@ -244,7 +247,7 @@ func (b *Builder) applyVirtualServiceTemplate(vs *v1alpha1.VirtualService) (*v1a
 func (b *Builder) buildListener(listenerNN helpers.NamespacedName) (*listenerv3.Listener, error) {
 	listener := b.store.GetListener(listenerNN)
 	if listener == nil {
-		return nil, fmt.Errorf("listener %s not found", listenerNN.String())
+		return nil, utils.NewNotFoundError(utils.KindListener, "listener %s not found", listenerNN.String())
 	}
 
 	xdsListener, err := listener.UnmarshalV3()
@@ -308,6 +311,20 @@ func (b *Builder) buildResourcesFromVirtualService(
 		return nil, fmt.Errorf("failed to build filter chain parameters: %w", err)
 	}
 
+	// 4.1 Listeners with scoped routes select the route configuration by scopes of its domains
+	var scopedRoutes []*routev3.ScopedRouteConfiguration
+	routeDiscovery, err := b.store.GetListener(listenerNN).GetRouteDiscovery()
+	if err != nil {
+		return nil, fmt.Errorf("listener %s: %w", listenerNN.String(), err)
+	}
+	if routeDiscovery == v1alpha1.RouteDiscoveryScopedRDS {
+		scopedRoutes, err = b.routingBuilder.BuildScopedRouteConfigurations(routeConfig, virtualHost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build scoped routes: %w", err)
+		}
+		params.ScopedRoutesName = listenerNN.String()
+	}
+
 	// 5. Build filter chains
 	filterChains, err := b.filterChainBuilder.BuildFilterChains(params)
 	if err != nil {
@@ -319,10 +336,12 @@ func (b *Builder) buildResourcesFromVirtualService(
 
 	// 7. Create initial resources structure
 	resources := &Resources{
-		Listener:    listenerNN,
-		FilterChain: filterChains,
-		RouteConfig: routeConfig,
-		Domains:     domains,
+		Listener:         listenerNN,
+		FilterChain:      filterChains,
+		RouteConfig:      routeConfig,
+		ScopedRoutes:     scopedRoutes,
+		Domains:          domains,
+		ExtensionConfigs: filters.ExtensionConfigRefs(vs, b.store),
 	}
 
 	// 8. Extract clusters from various sources
@@ -387,7 +406,7 @@ func (b *Builder) buildResourcesFromVirtualService(
 func (b *Builder) buildSecret(secretName helpers.NamespacedName) (*tlsv3.Secret, error) {
 	k8sSecret := b.store.GetSecret(secretName)
 	if k8sSecret == nil {
-		return nil, fmt.Errorf("Kubernetes secret %s not found", secretName.String())
+		return nil, utils.NewNotFoundError(utils.KindSecret, "Kubernetes secret %s not found", secretName.String())
 	}
 
 	// Validate and extract certificate data
@@ -81,6 +81,14 @@ func (m *MockRoutingBuilder) BuildVirtualHost(
 	return args.Get(0).(*routev3.VirtualHost), args.Error(1)
 }
 
+func (m *MockRoutingBuilder) BuildScopedRouteConfigurations(
+	routeConfig *routev3.RouteConfiguration,
+	virtualHost *routev3.VirtualHost,
+) ([]*routev3.ScopedRouteConfiguration, error) {
+	args := m.Called(routeConfig, virtualHost)
+	return args.Get(0).([]*routev3.ScopedRouteConfiguration), args.Error(1)
+}
+
 type MockTLSBuilder struct {
 	mock.Mock
 }
@@ -6,6 +6,7 @@ import (
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/filters"
 )
 
 // Resources represents all the Envoy resources built for a VirtualService
@@ -19,6 +20,9 @@ type Resources struct {
 	// RouteConfig is the route configuration for the HTTP connection manager
 	RouteConfig *routev3.RouteConfiguration
 
+	// ScopedRoutes are the scopes selecting RouteConfig on listeners with scoped routes
+	ScopedRoutes []*routev3.ScopedRouteConfiguration
+
 	// Clusters is a slice of clusters used by the virtual service
 	Clusters []*clusterv3.Cluster
 
@@ -30,6 +34,9 @@ type Resources struct {
 
 	// Domains is a slice of domain names for the virtual service
 	Domains []string
+
+	// ExtensionConfigs references the HTTP filters served over ECDS
+	ExtensionConfigs []filters.ExtensionConfigRef
 }
 
 // HasTLSConfig returns true if the resources include TLS configuration
@@ -2,14 +2,25 @@ package routes
 
 import (
 	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
 
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
 	"github.com/kaasops/envoy-xds-controller/internal/protoutil"
 	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/filters"
 	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/utils"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -80,6 +91,29 @@ func (b *Builder) BuildVirtualHost(
 		virtualHost.Routes = append(virtualHost.Routes, additionalRoutes...)
 	}
 
+	// Render TrafficSplits into the weighted clusters of their routes
+	b.applyTrafficSplits(virtualHost, nn)
+
+	// Mirror requests of routes before canary copies are made of them
+	if err := b.applyMirror(virtualHost, vs.Spec.Mirror, vs.Namespace); err != nil {
+		return nil, fmt.Errorf("mirror: %w", err)
+	}
+
+	// Add descriptors of RateLimitPolicies of the VirtualService to their routes
+	if err := b.applyRateLimitPolicies(virtualHost, nn); err != nil {
+		return nil, err
+	}
+
+	// Give routes with an override of the local rate limit a bucket of their own
+	if err := applyLocalRateLimit(virtualHost, vs.Spec.LocalRateLimit); err != nil {
+		return nil, fmt.Errorf("local rate limit: %w", err)
+	}
+
+	// Precede routes with copies routing canary requests to the canary cluster
+	if err := b.applyCanary(virtualHost, vs.Spec.Canary, vs.Namespace); err != nil {
+		return nil, fmt.Errorf("canary: %w", err)
+	}
+
 	// Reorder routes to ensure root routes are at the end
 	if err := b.reorderRoutes(virtualHost); err != nil {
 		return nil, fmt.Errorf("failed to reorder routes: %w", err)
@@ -147,6 +181,349 @@ func (b *Builder) buildRoutesFromSpec(
 	return routes, nil
 }
 
+// applyTrafficSplits replaces the clusters of routes split by TrafficSplits of the VirtualService
+// with their stable and canary clusters, weighted by the current step of the TrafficSplit.
+// TrafficSplits which cannot be applied are skipped, leaving their routes as they are: their
+// errors are reported in the statuses of the TrafficSplits, see TrafficSplitError.
+func (b *Builder) applyTrafficSplits(virtualHost *routev3.VirtualHost, nn helpers.NamespacedName) {
+	for _, ts := range b.store.GetTrafficSplitsForVirtualService(nn) {
+		routeAction, stable, canary, err := b.resolveTrafficSplit(virtualHost, ts)
+		if err != nil {
+			continue
+		}
+		switch weight := ts.CanaryWeight(); weight {
+		case 0:
+			routeAction.ClusterSpecifier = &routev3.RouteAction_Cluster{Cluster: stable}
+		case 100:
+			routeAction.ClusterSpecifier = &routev3.RouteAction_Cluster{Cluster: canary}
+		default:
+			routeAction.ClusterSpecifier = &routev3.RouteAction_WeightedClusters{
+				WeightedClusters: &routev3.WeightedCluster{
+					Clusters: []*routev3.WeightedCluster_ClusterWeight{
+						{Name: stable, Weight: wrapperspb.UInt32(100 - weight)},
+						{Name: canary, Weight: wrapperspb.UInt32(weight)},
+					},
+				},
+			}
+		}
+	}
+}
+
+// TrafficSplitError returns why the TrafficSplit cannot be applied to the routes of the
+// virtual host, or nil if it can.
+func (b *Builder) TrafficSplitError(virtualHost *routev3.VirtualHost, ts *v1alpha1.TrafficSplit) error {
+	_, _, _, err := b.resolveTrafficSplit(virtualHost, ts)
+	return err
+}
+
+// resolveTrafficSplit returns the action of the route split by the TrafficSplit, with the
+// Envoy names of its stable and canary clusters.
+func (b *Builder) resolveTrafficSplit(
+	virtualHost *routev3.VirtualHost,
+	ts *v1alpha1.TrafficSplit,
+) (routeAction *routev3.RouteAction, stable, canary string, err error) {
+	if err := ts.Validate(); err != nil {
+		return nil, "", "", err
+	}
+	for _, route := range virtualHost.Routes {
+		if route.GetName() != ts.Spec.Route {
+			continue
+		}
+		if routeAction != nil {
+			return nil, "", "", fmt.Errorf("multiple routes named %s", ts.Spec.Route)
+		}
+		if routeAction = route.GetRoute(); routeAction == nil {
+			return nil, "", "", fmt.Errorf("route %s does not route to a cluster", ts.Spec.Route)
+		}
+	}
+	if routeAction == nil {
+		return nil, "", "", fmt.Errorf("route %s not found", ts.Spec.Route)
+	}
+	if stable, err = b.specClusterName(ts.GetStableNamespacedName()); err != nil {
+		return nil, "", "", err
+	}
+	if canary, err = b.specClusterName(ts.GetCanaryNamespacedName()); err != nil {
+		return nil, "", "", err
+	}
+	return routeAction, stable, canary, nil
+}
+
+// applyCanary inserts before each route to a cluster, or each route listed by the canary,
+// one copy per header and cookie of the canary, routing matching requests to its cluster.
+func (b *Builder) applyCanary(virtualHost *routev3.VirtualHost, canary *v1alpha1.CanarySpec, namespace string) error {
+	if canary == nil {
+		return nil
+	}
+	if canary.Cluster.Name == "" {
+		return fmt.Errorf("cluster name is empty")
+	}
+	matchers, err := canaryMatchers(canary)
+	if err != nil {
+		return err
+	}
+	clusterName, err := b.specClusterName(helpers.NamespacedName{
+		Namespace: helpers.GetNamespace(canary.Cluster.Namespace, namespace),
+		Name:      canary.Cluster.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	selected, err := selectRoutes(virtualHost, canary.Routes)
+	if err != nil {
+		return err
+	}
+	routes := make([]*routev3.Route, 0, len(virtualHost.Routes)+len(selected)*len(matchers))
+	for _, route := range virtualHost.Routes {
+		if slices.Contains(selected, route) {
+			for i, matcher := range matchers {
+				canaryRoute := proto.Clone(route).(*routev3.Route)
+				if canaryRoute.Name != "" {
+					canaryRoute.Name = fmt.Sprintf("%s-canary-%d", canaryRoute.Name, i)
+				}
+				if canaryRoute.Match == nil {
+					canaryRoute.Match = &routev3.RouteMatch{}
+				}
+				canaryRoute.Match.Headers = append(canaryRoute.Match.Headers, matcher)
+				canaryRoute.GetRoute().ClusterSpecifier = &routev3.RouteAction_Cluster{Cluster: clusterName}
+				routes = append(routes, canaryRoute)
+			}
+		}
+		routes = append(routes, route)
+	}
+	virtualHost.Routes = routes
+	return nil
+}
+
+// applyRateLimitPolicies adds to routes limited by RateLimitPolicies of the VirtualService one
+// rate limit per limit, whose descriptor identifies the limit and the key requests are counted by.
+func (b *Builder) applyRateLimitPolicies(virtualHost *routev3.VirtualHost, nn helpers.NamespacedName) error {
+	for _, policy := range b.store.GetRateLimitPoliciesForVirtualService(nn) {
+		policyNN := helpers.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("rate limit policy %s: %w", policyNN.String(), err)
+		}
+		routes, err := selectRoutes(virtualHost, policy.Spec.Routes)
+		if err != nil {
+			return fmt.Errorf("rate limit policy %s: %w", policyNN.String(), err)
+		}
+		rateLimits := make([]*routev3.RateLimit, 0, len(policy.Spec.Limits))
+		for _, limit := range policy.Spec.Limits {
+			rateLimits = append(rateLimits, rateLimit(policy, limit))
+		}
+		for _, route := range routes {
+			routeAction := route.GetRoute()
+			for _, rl := range rateLimits {
+				routeAction.RateLimits = append(routeAction.RateLimits, proto.Clone(rl).(*routev3.RateLimit))
+			}
+		}
+	}
+	return nil
+}
+
+// applyLocalRateLimit sets the local rate limit config of routes with an override of the bucket.
+// Other routes share the bucket of the filter.
+func applyLocalRateLimit(virtualHost *routev3.VirtualHost, spec *v1alpha1.LocalRateLimitSpec) error {
+	if spec == nil {
+		return nil
+	}
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	for _, name := range spec.RouteNames() {
+		bucket, _ := spec.RouteBucket(name)
+		config, err := filters.LocalRateLimitConfig(bucket)
+		if err != nil {
+			return fmt.Errorf("route %s: %w", name, err)
+		}
+		found := false
+		for _, route := range virtualHost.Routes {
+			if route.GetName() != name {
+				continue
+			}
+			found = true
+			if route.TypedPerFilterConfig == nil {
+				route.TypedPerFilterConfig = make(map[string]*anypb.Any)
+			}
+			route.TypedPerFilterConfig[filters.LocalRateLimitFilterName] = proto.Clone(config).(*anypb.Any)
+		}
+		if !found {
+			return fmt.Errorf("route %s not found", name)
+		}
+	}
+	return nil
+}
+
+// rateLimit returns the rate limit of the limit of the policy
+func rateLimit(policy *v1alpha1.RateLimitPolicy, limit v1alpha1.RateLimit) *routev3.RateLimit {
+	actions := []*routev3.RateLimit_Action{{
+		ActionSpecifier: &routev3.RateLimit_Action_GenericKey_{GenericKey: &routev3.RateLimit_Action_GenericKey{
+			DescriptorKey:   v1alpha1.RateLimitPolicyDescriptorKey,
+			DescriptorValue: policy.DescriptorValue(limit.Name),
+		}},
+	}}
+	switch {
+	case limit.By == nil:
+	case limit.By.RemoteAddress:
+		actions = append(actions, &routev3.RateLimit_Action{
+			ActionSpecifier: &routev3.RateLimit_Action_RemoteAddress_{
+				RemoteAddress: &routev3.RateLimit_Action_RemoteAddress{},
+			},
+		})
+	case limit.By.Header != "":
+		actions = append(actions, &routev3.RateLimit_Action{
+			ActionSpecifier: &routev3.RateLimit_Action_RequestHeaders_{
+				RequestHeaders: &routev3.RateLimit_Action_RequestHeaders{
+					HeaderName:    limit.By.Header,
+					DescriptorKey: v1alpha1.RateLimitHeaderDescriptorKey,
+				},
+			},
+		})
+	}
+	return &routev3.RateLimit{Actions: actions}
+}
+
+// selectRoutes returns the routes with the names, all routes to a cluster if there are none.
+// Named routes must exist and route to a cluster.
+func selectRoutes(virtualHost *routev3.VirtualHost, names []string) ([]*routev3.Route, error) {
+	var routes []*routev3.Route
+	if len(names) == 0 {
+		for _, route := range virtualHost.Routes {
+			if route.GetRoute() != nil {
+				routes = append(routes, route)
+			}
+		}
+		return routes, nil
+	}
+	for _, name := range names {
+		found := false
+		for _, route := range virtualHost.Routes {
+			if route.GetName() != name {
+				continue
+			}
+			if route.GetRoute() == nil {
+				return nil, fmt.Errorf("route %s does not route to a cluster", name)
+			}
+			found = true
+			if !slices.Contains(routes, route) {
+				routes = append(routes, route)
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("route %s not found", name)
+		}
+	}
+	return routes, nil
+}
+
+// applyMirror adds to each route to a cluster, or each route listed by the mirror, a request
+// mirror policy to its cluster.
+func (b *Builder) applyMirror(virtualHost *routev3.VirtualHost, mirror *v1alpha1.MirrorSpec, namespace string) error {
+	if mirror == nil {
+		return nil
+	}
+	if mirror.Cluster.Name == "" {
+		return fmt.Errorf("cluster name is empty")
+	}
+	if mirror.Percent != nil && *mirror.Percent > 100 {
+		return fmt.Errorf("percent %d is greater than 100", *mirror.Percent)
+	}
+	clusterName, err := b.specClusterName(helpers.NamespacedName{
+		Namespace: helpers.GetNamespace(mirror.Cluster.Namespace, namespace),
+		Name:      mirror.Cluster.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	policy := &routev3.RouteAction_RequestMirrorPolicy{Cluster: clusterName}
+	if mirror.Percent != nil || mirror.RuntimeKey != "" {
+		percent := uint32(100)
+		if mirror.Percent != nil {
+			percent = *mirror.Percent
+		}
+		policy.RuntimeFraction = &corev3.RuntimeFractionalPercent{
+			DefaultValue: &typev3.FractionalPercent{
+				Numerator:   percent,
+				Denominator: typev3.FractionalPercent_HUNDRED,
+			},
+			RuntimeKey: mirror.RuntimeKey,
+		}
+	}
+	if mirror.TraceSampled != nil {
+		policy.TraceSampled = wrapperspb.Bool(*mirror.TraceSampled)
+	}
+
+	routes, err := selectRoutes(virtualHost, mirror.Routes)
+	if err != nil {
+		return err
+	}
+	for _, route := range routes {
+		routeAction := route.GetRoute()
+		routeAction.RequestMirrorPolicies = append(routeAction.RequestMirrorPolicies,
+			proto.Clone(policy).(*routev3.RouteAction_RequestMirrorPolicy))
+	}
+	return nil
+}
+
+// canaryMatchers returns the header matchers of the headers and cookies of the canary
+func canaryMatchers(canary *v1alpha1.CanarySpec) ([]*routev3.HeaderMatcher, error) {
+	if len(canary.Headers) == 0 && len(canary.Cookies) == 0 {
+		return nil, fmt.Errorf("no headers or cookies to match")
+	}
+	matchers := make([]*routev3.HeaderMatcher, 0, len(canary.Headers)+len(canary.Cookies))
+	for _, header := range canary.Headers {
+		if header.Name == "" {
+			return nil, fmt.Errorf("header name is empty")
+		}
+		matcher := &routev3.HeaderMatcher{Name: header.Name}
+		if header.Value == "" {
+			matcher.HeaderMatchSpecifier = &routev3.HeaderMatcher_PresentMatch{PresentMatch: true}
+		} else {
+			matcher.HeaderMatchSpecifier = &routev3.HeaderMatcher_StringMatch{
+				StringMatch: &matcherv3.StringMatcher{
+					MatchPattern: &matcherv3.StringMatcher_Exact{Exact: header.Value},
+				},
+			}
+		}
+		matchers = append(matchers, matcher)
+	}
+	for _, cookie := range canary.Cookies {
+		if cookie.Name == "" {
+			return nil, fmt.Errorf("cookie name is empty")
+		}
+		// Safe regexes match the whole header, cookies are separated by semicolons
+		value := `[^;]*`
+		if cookie.Value != "" {
+			value = regexp.QuoteMeta(cookie.Value)
+		}
+		matchers = append(matchers, &routev3.HeaderMatcher{
+			Name: "cookie",
+			HeaderMatchSpecifier: &routev3.HeaderMatcher_StringMatch{
+				StringMatch: &matcherv3.StringMatcher{
+					MatchPattern: &matcherv3.StringMatcher_SafeRegex{SafeRegex: &matcherv3.RegexMatcher{
+						Regex: `(.*;\s*)?` + regexp.QuoteMeta(cookie.Name) + `=` + value + `(;.*)?`,
+					}},
+				},
+			},
+		})
+	}
+	return matchers, nil
+}
+
+// specClusterName returns the Envoy cluster name of the Cluster
+func (b *Builder) specClusterName(nn helpers.NamespacedName) (string, error) {
+	cl := b.store.GetCluster(nn)
+	if cl == nil {
+		return "", utils.NewNotFoundError(utils.KindCluster, "cluster %s not found", nn.String())
+	}
+	clusterV3, err := cl.UnmarshalV3()
+	if err != nil {
+		return "", fmt.Errorf("failed to unmarshal cluster %s: %w", nn.String(), err)
+	}
+	return clusterV3.Name, nil
+}
+
 // validateRouteClusterReferences validates that referenced clusters exist
 func (b *Builder) validateRouteClusterReferences(route *routev3.Route) error {
 	if routeAction := route.GetRoute(); routeAction != nil {
@@ -154,7 +531,7 @@ func (b *Builder) validateRouteClusterReferences(route *routev3.Route) error {
 		case *routev3.RouteAction_Cluster:
 			if clusterSpec.Cluster != "" {
 				if cl := b.store.GetSpecCluster(clusterSpec.Cluster); cl == nil {
-					return fmt.Errorf("cluster %s not found", clusterSpec.Cluster)
+					return utils.NewNotFoundError(utils.KindCluster, "cluster %s not found", clusterSpec.Cluster)
 				}
 			}
 		case *routev3.RouteAction_WeightedClusters:
@@ -162,49 +539,53 @@ func (b *Builder) validateRouteClusterReferences(route *routev3.Route) error {
 				for _, wc := range clusterSpec.WeightedClusters.Clusters {
 					if wc.Name != "" {
 						if cl := b.store.GetSpecCluster(wc.Name); cl == nil {
-							return fmt.Errorf("weighted cluster %s not found", wc.Name)
+							return utils.NewNotFoundError(utils.KindCluster, "weighted cluster %s not found", wc.Name)
 						}
 					}
 				}
 			}
 		}
+		for _, policy := range routeAction.RequestMirrorPolicies {
+			if policy.Cluster != "" {
+				if cl := b.store.GetSpecCluster(policy.Cluster); cl == nil {
+					return utils.NewNotFoundError(utils.KindCluster, "mirror cluster %s not found", policy.Cluster)
+				}
+			}
+		}
 	}
 	return nil
 }
 
-// reorderRoutes ensures that root routes (prefix="/", path="/") are positioned at the end
+// reorderRoutes ensures that root routes (prefix="/", path="/") are positioned at the end,
+// keeping their order. Root routes with match conditions, such as generated canary routes,
+// stay ahead of the one matching unconditionally, of which there may be only one.
 func (b *Builder) reorderRoutes(virtualHost *routev3.VirtualHost) error {
 	if len(virtualHost.Routes) <= 1 {
 		return nil // No reordering needed for 0 or 1 routes
 	}
 
-	rootMatchIndexes := b.findRootRouteIndexes(virtualHost.Routes)
-
-	switch {
-	case len(rootMatchIndexes) > 1:
-		return fmt.Errorf("multiple root routes found")
-	case len(rootMatchIndexes) == 1:
-		rootIndex := rootMatchIndexes[0]
-		// Only move if not already at the end
-		if rootIndex != len(virtualHost.Routes)-1 {
-			b.moveRouteToEnd(virtualHost, rootIndex)
+	routes := make([]*routev3.Route, 0, len(virtualHost.Routes))
+	var rootRoutes []*routev3.Route
+	var catchAllRoute *routev3.Route
+	for _, route := range virtualHost.Routes {
+		switch {
+		case !b.isRootRoute(route):
+			routes = append(routes, route)
+		case hasMatchConditions(route.Match):
+			rootRoutes = append(rootRoutes, route)
+		case catchAllRoute != nil:
+			return fmt.Errorf("multiple root routes found")
+		default:
+			catchAllRoute = route
 		}
 	}
 
-	return nil
-}
-
-// findRootRouteIndexes finds indexes of routes that match root paths
-func (b *Builder) findRootRouteIndexes(routes []*routev3.Route) []int {
-	var rootIndexes []int
-
-	for index, route := range routes {
-		if b.isRootRoute(route) {
-			rootIndexes = append(rootIndexes, index)
-		}
+	routes = append(routes, rootRoutes...)
+	if catchAllRoute != nil {
+		routes = append(routes, catchAllRoute)
 	}
-
-	return rootIndexes
+	virtualHost.Routes = routes
+	return nil
 }
 
 // isRootRoute checks if a route matches root path patterns
@@ -223,13 +604,15 @@ func (b *Builder) isRootRoute(route *routev3.Route) bool {
 	}
 }
 
-// moveRouteToEnd moves a route from the given index to the end of the routes slice
-func (b *Builder) moveRouteToEnd(virtualHost *routev3.VirtualHost, index int) {
-	route := virtualHost.Routes[index]
-	// Remove route from current position
-	virtualHost.Routes = append(virtualHost.Routes[:index], virtualHost.Routes[index+1:]...)
-	// Add route to the end
-	virtualHost.Routes = append(virtualHost.Routes, route)
+// hasMatchConditions reports whether the match depends on more than the path
+func hasMatchConditions(match *routev3.RouteMatch) bool {
+	return len(match.GetHeaders()) > 0 ||
+		len(match.GetQueryParameters()) > 0 ||
+		len(match.GetDynamicMetadata()) > 0 ||
+		len(match.GetFilterState()) > 0 ||
+		match.GetRuntimeFraction() != nil ||
+		match.GetGrpc() != nil ||
+		match.GetTlsContext() != nil
 }
 
 // BuildFallbackVirtualHost creates a fallback virtual host for TLS listeners
@@ -270,3 +653,42 @@ func (b *Builder) AddFallbackVirtualHostIfNeeded(
 		routeConfig.VirtualHosts = append(routeConfig.VirtualHosts, fallbackVH)
 	}
 }
+
+// BuildScopedRouteConfigurations builds a scope per domain of the virtual host, keyed by the
+// host of the request, selecting the route configuration on demand. Ports are stripped from
+// domains, as the scope key is the host without port. Wildcard domains can not be scope keys.
+func (b *Builder) BuildScopedRouteConfigurations(
+	routeConfig *routev3.RouteConfiguration,
+	virtualHost *routev3.VirtualHost,
+) ([]*routev3.ScopedRouteConfiguration, error) {
+	hosts := make(map[string]struct{}, len(virtualHost.Domains))
+	scopes := make([]*routev3.ScopedRouteConfiguration, 0, len(virtualHost.Domains))
+	for _, domain := range virtualHost.Domains {
+		if strings.Contains(domain, "*") {
+			return nil, fmt.Errorf("wildcard domain %s is not supported with scoped routes", domain)
+		}
+		host := domain
+		if h, _, err := net.SplitHostPort(domain); err == nil {
+			host = h
+		}
+		if _, ok := hosts[host]; ok {
+			continue
+		}
+		hosts[host] = struct{}{}
+		scope := &routev3.ScopedRouteConfiguration{
+			Name:                   routeConfig.Name + "/" + host,
+			OnDemand:               true,
+			RouteConfigurationName: routeConfig.Name,
+			Key: &routev3.ScopedRouteConfiguration_Key{
+				Fragments: []*routev3.ScopedRouteConfiguration_Key_Fragment{{
+					Type: &routev3.ScopedRouteConfiguration_Key_Fragment_StringKey{StringKey: host},
+				}},
+			},
+		}
+		if err := scope.ValidateAll(); err != nil {
+			return nil, fmt.Errorf("failed to validate scoped route configuration: %w", err)
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
@@ -0,0 +1,320 @@
+package routes
+
+import (
+	"testing"
+	"time"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	localratelimitv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/local_ratelimit/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/filters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newStoreWithClusters(names ...string) store.Store {
+	st := store.New()
+	for _, name := range names {
+		st.SetCluster(&v1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: &runtime.RawExtension{
+				Raw: []byte(`{"name":"` + name + `","connect_timeout":"1s"}`),
+			},
+		})
+	}
+	return st
+}
+
+func newVirtualService(virtualHost string, canary *v1alpha1.CanarySpec) *v1alpha1.VirtualService {
+	vs := &v1alpha1.VirtualService{ObjectMeta: metav1.ObjectMeta{Name: "vs", Namespace: "default"}}
+	vs.Spec.VirtualHost = &runtime.RawExtension{Raw: []byte(virtualHost)}
+	vs.Spec.Canary = canary
+	return vs
+}
+
+func routeNames(routes []*routev3.Route) []string {
+	names := make([]string, 0, len(routes))
+	for _, route := range routes {
+		names = append(names, route.GetName())
+	}
+	return names
+}
+
+const canaryVirtualHost = `{
+	"domains": ["example.com"],
+	"routes": [
+		{"name": "root", "match": {"prefix": "/"}, "route": {"cluster": "stable"}},
+		{"name": "api", "match": {"prefix": "/api"}, "route": {"cluster": "stable"}},
+		{"name": "health", "match": {"path": "/health"}, "direct_response": {"status": 200}}
+	]
+}`
+
+func TestBuildVirtualHost_Canary(t *testing.T) {
+	builder := NewBuilder(newStoreWithClusters("stable", "canary"))
+	nn := helpers.NamespacedName{Namespace: "default", Name: "vs"}
+
+	vs := newVirtualService(canaryVirtualHost, &v1alpha1.CanarySpec{
+		Cluster: v1alpha1.ResourceRef{Name: "canary"},
+		Headers: []v1alpha1.CanaryMatch{{Name: "x-canary", Value: "true"}},
+		Cookies: []v1alpha1.CanaryMatch{{Name: "canary"}},
+	})
+	virtualHost, err := builder.BuildVirtualHost(vs, nn)
+	require.NoError(t, err)
+
+	// Canary copies of the root route stay ahead of it, each matcher gets its own name
+	names := routeNames(virtualHost.Routes)
+	assert.Equal(t, []string{
+		"api-canary-0", "api-canary-1", "api", "health",
+		"root-canary-0", "root-canary-1", "root",
+	}, names)
+	distinct := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		distinct[name] = struct{}{}
+	}
+	assert.Len(t, distinct, len(names), "route names must be distinct")
+	for _, route := range virtualHost.Routes {
+		if route.GetName() == "health" {
+			continue
+		}
+		expected := "stable"
+		if route.GetName() != "root" && route.GetName() != "api" {
+			expected = "canary"
+		}
+		assert.Equal(t, expected, route.GetRoute().GetCluster(), route.GetName())
+	}
+
+	header := virtualHost.Routes[0].Match.Headers[0]
+	assert.Equal(t, "x-canary", header.Name)
+	assert.Equal(t, "true", header.GetStringMatch().GetExact())
+	cookie := virtualHost.Routes[1].Match.Headers[0]
+	assert.Equal(t, "cookie", cookie.Name)
+	assert.Equal(t, `(.*;\s*)?canary=[^;]*(;.*)?`, cookie.GetStringMatch().GetSafeRegex().GetRegex())
+
+	// Only the listed routes get canary copies
+	vs.Spec.Canary.Routes = []string{"api"}
+	virtualHost, err = builder.BuildVirtualHost(vs, nn)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api-canary-0", "api-canary-1", "api", "health", "root"}, routeNames(virtualHost.Routes))
+}
+
+func TestBuildVirtualHost_CanaryErrors(t *testing.T) {
+	builder := NewBuilder(newStoreWithClusters("stable", "canary"))
+	nn := helpers.NamespacedName{Namespace: "default", Name: "vs"}
+	headers := []v1alpha1.CanaryMatch{{Name: "x-canary"}}
+
+	tests := []struct {
+		name   string
+		canary *v1alpha1.CanarySpec
+		errMsg string
+	}{
+		{
+			name:   "no matchers",
+			canary: &v1alpha1.CanarySpec{Cluster: v1alpha1.ResourceRef{Name: "canary"}},
+			errMsg: "no headers or cookies to match",
+		},
+		{
+			name:   "unknown cluster",
+			canary: &v1alpha1.CanarySpec{Cluster: v1alpha1.ResourceRef{Name: "missing"}, Headers: headers},
+			errMsg: "cluster default/missing not found",
+		},
+		{
+			name: "unknown route",
+			canary: &v1alpha1.CanarySpec{
+				Cluster: v1alpha1.ResourceRef{Name: "canary"},
+				Routes:  []string{"missing"},
+				Headers: headers,
+			},
+			errMsg: "route missing not found",
+		},
+		{
+			name: "route without cluster",
+			canary: &v1alpha1.CanarySpec{
+				Cluster: v1alpha1.ResourceRef{Name: "canary"},
+				Routes:  []string{"health"},
+				Headers: headers,
+			},
+			errMsg: "route health does not route to a cluster",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := builder.BuildVirtualHost(newVirtualService(canaryVirtualHost, tt.canary), nn)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestReorderRoutes(t *testing.T) {
+	builder := NewBuilder(store.New())
+	root := func(name string, headers ...*routev3.HeaderMatcher) *routev3.Route {
+		return &routev3.Route{
+			Name:  name,
+			Match: &routev3.RouteMatch{PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/"}, Headers: headers},
+		}
+	}
+	other := &routev3.Route{
+		Name:  "other",
+		Match: &routev3.RouteMatch{PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/other"}},
+	}
+	header := &routev3.HeaderMatcher{
+		Name:                 "x-canary",
+		HeaderMatchSpecifier: &routev3.HeaderMatcher_PresentMatch{PresentMatch: true},
+	}
+
+	virtualHost := &routev3.VirtualHost{Routes: []*routev3.Route{root("root"), root("canary", header), other}}
+	require.NoError(t, builder.reorderRoutes(virtualHost))
+	assert.Equal(t, []string{"other", "canary", "root"}, routeNames(virtualHost.Routes))
+
+	virtualHost = &routev3.VirtualHost{Routes: []*routev3.Route{root("a"), other, root("b")}}
+	assert.EqualError(t, builder.reorderRoutes(virtualHost), "multiple root routes found")
+}
+
+func TestBuildVirtualHost_Mirror(t *testing.T) {
+	builder := NewBuilder(newStoreWithClusters("stable", "canary", "shadow"))
+	nn := helpers.NamespacedName{Namespace: "default", Name: "vs"}
+	percent := uint32(10)
+
+	vs := newVirtualService(canaryVirtualHost, nil)
+	vs.Spec.Mirror = &v1alpha1.MirrorSpec{
+		Cluster:    v1alpha1.ResourceRef{Name: "shadow"},
+		Routes:     []string{"api"},
+		Percent:    &percent,
+		RuntimeKey: "mirror.api",
+	}
+	vs.Spec.Canary = &v1alpha1.CanarySpec{
+		Cluster: v1alpha1.ResourceRef{Name: "canary"},
+		Routes:  []string{"api"},
+		Headers: []v1alpha1.CanaryMatch{{Name: "x-canary"}},
+	}
+	virtualHost, err := builder.BuildVirtualHost(vs, nn)
+	require.NoError(t, err)
+	require.Equal(t, []string{"api-canary-0", "api", "health", "root"}, routeNames(virtualHost.Routes))
+
+	// Canary copies are mirrored too
+	for _, route := range virtualHost.Routes[:2] {
+		policies := route.GetRoute().RequestMirrorPolicies
+		require.Len(t, policies, 1, route.GetName())
+		assert.Equal(t, "shadow", policies[0].Cluster)
+		assert.Equal(t, uint32(10), policies[0].RuntimeFraction.DefaultValue.Numerator)
+		assert.Equal(t, "mirror.api", policies[0].RuntimeFraction.RuntimeKey)
+	}
+	assert.Empty(t, virtualHost.Routes[3].GetRoute().RequestMirrorPolicies)
+
+	// All routes to a cluster are mirrored by default, without fraction
+	vs.Spec.Mirror = &v1alpha1.MirrorSpec{Cluster: v1alpha1.ResourceRef{Name: "shadow"}}
+	vs.Spec.Canary = nil
+	virtualHost, err = builder.BuildVirtualHost(vs, nn)
+	require.NoError(t, err)
+	for _, route := range virtualHost.Routes {
+		if route.GetRoute() == nil {
+			continue
+		}
+		require.Len(t, route.GetRoute().RequestMirrorPolicies, 1, route.GetName())
+		assert.Nil(t, route.GetRoute().RequestMirrorPolicies[0].RuntimeFraction)
+	}
+
+	vs.Spec.Mirror.Cluster.Name = "missing"
+	_, err = builder.BuildVirtualHost(vs, nn)
+	assert.ErrorContains(t, err, "mirror: cluster default/missing not found")
+}
+
+func TestBuildVirtualHost_RateLimitPolicies(t *testing.T) {
+	st := newStoreWithClusters("stable", "ratelimit")
+	builder := NewBuilder(st)
+	nn := helpers.NamespacedName{Namespace: "default", Name: "vs"}
+	newPolicy := func(name string, routes []string, limits ...v1alpha1.RateLimit) *v1alpha1.RateLimitPolicy {
+		return &v1alpha1.RateLimitPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1alpha1.RateLimitPolicySpec{
+				VirtualService: "vs",
+				Routes:         routes,
+				Service:        v1alpha1.RateLimitService{Cluster: v1alpha1.ResourceRef{Name: "ratelimit"}},
+				Limits:         limits,
+			},
+		}
+	}
+	st.SetRateLimitPolicy(newPolicy("all", nil,
+		v1alpha1.RateLimit{Name: "global", Requests: 1000, Unit: v1alpha1.RateLimitUnitSecond}))
+	st.SetRateLimitPolicy(newPolicy("api", []string{"api"},
+		v1alpha1.RateLimit{
+			Name:     "per-key",
+			Requests: 10,
+			Unit:     v1alpha1.RateLimitUnitMinute,
+			By:       &v1alpha1.RateLimitKey{Header: "x-api-key"},
+		}))
+
+	vs := newVirtualService(canaryVirtualHost, &v1alpha1.CanarySpec{
+		Cluster: v1alpha1.ResourceRef{Name: "stable"},
+		Routes:  []string{"api"},
+		Headers: []v1alpha1.CanaryMatch{{Name: "x-canary"}},
+	})
+	virtualHost, err := builder.BuildVirtualHost(vs, nn)
+	require.NoError(t, err)
+	require.Equal(t, []string{"api-canary-0", "api", "health", "root"}, routeNames(virtualHost.Routes))
+
+	// Canary copies are limited too
+	for _, route := range virtualHost.Routes[:2] {
+		rateLimits := route.GetRoute().RateLimits
+		require.Len(t, rateLimits, 2, route.GetName())
+		assert.Equal(t, "default/all/global", rateLimits[0].Actions[0].GetGenericKey().DescriptorValue)
+		assert.Len(t, rateLimits[0].Actions, 1)
+		assert.Equal(t, v1alpha1.RateLimitPolicyDescriptorKey, rateLimits[1].Actions[0].GetGenericKey().DescriptorKey)
+		assert.Equal(t, "default/api/per-key", rateLimits[1].Actions[0].GetGenericKey().DescriptorValue)
+		assert.Equal(t, "x-api-key", rateLimits[1].Actions[1].GetRequestHeaders().HeaderName)
+	}
+	assert.Len(t, virtualHost.Routes[3].GetRoute().RateLimits, 1)
+
+	st.SetRateLimitPolicy(newPolicy("api", []string{"health"},
+		v1alpha1.RateLimit{Name: "global", Requests: 1, Unit: v1alpha1.RateLimitUnitSecond}))
+	_, err = builder.BuildVirtualHost(vs, nn)
+	assert.ErrorContains(t, err, "rate limit policy default/api: route health does not route to a cluster")
+}
+
+func TestBuildVirtualHost_LocalRateLimit(t *testing.T) {
+	builder := NewBuilder(newStoreWithClusters("stable", "canary"))
+	nn := helpers.NamespacedName{Namespace: "default", Name: "vs"}
+
+	vs := newVirtualService(canaryVirtualHost, &v1alpha1.CanarySpec{
+		Cluster: v1alpha1.ResourceRef{Name: "canary"},
+		Routes:  []string{"api"},
+		Headers: []v1alpha1.CanaryMatch{{Name: "x-canary"}},
+	})
+	vs.Spec.LocalRateLimit = &v1alpha1.LocalRateLimitSpec{
+		LocalRateLimitBucket: v1alpha1.LocalRateLimitBucket{
+			Tokens:       100,
+			FillInterval: &metav1.Duration{Duration: time.Second},
+		},
+		Routes: map[string]v1alpha1.LocalRateLimitBucket{"api": {Tokens: 10, MaxTokens: 20}},
+	}
+	virtualHost, err := builder.BuildVirtualHost(vs, nn)
+	require.NoError(t, err)
+	require.Equal(t, []string{"api-canary-0", "api", "health", "root"}, routeNames(virtualHost.Routes))
+
+	// Canary copies get the override too
+	for _, route := range virtualHost.Routes[:2] {
+		typedConfig := route.TypedPerFilterConfig[filters.LocalRateLimitFilterName]
+		require.NotNil(t, typedConfig, route.GetName())
+		config := &localratelimitv3.LocalRateLimit{}
+		require.NoError(t, typedConfig.UnmarshalTo(config))
+		assert.Equal(t, uint32(20), config.TokenBucket.MaxTokens)
+		assert.Equal(t, uint32(10), config.TokenBucket.TokensPerFill.GetValue())
+		assert.Equal(t, time.Second, config.TokenBucket.FillInterval.AsDuration())
+		assert.Equal(t, uint32(100), config.FilterEnforced.DefaultValue.Numerator)
+	}
+	// Other routes share the bucket of the filter
+	assert.Empty(t, virtualHost.Routes[3].TypedPerFilterConfig)
+
+	vs.Spec.LocalRateLimit.Routes = map[string]v1alpha1.LocalRateLimitBucket{"missing": {Tokens: 10}}
+	_, err = builder.BuildVirtualHost(vs, nn)
+	assert.EqualError(t, err, "local rate limit: route missing not found")
+
+	vs.Spec.LocalRateLimit.FillInterval = nil
+	_, err = builder.BuildVirtualHost(vs, nn)
+	assert.EqualError(t, err, "local rate limit: fillInterval is required")
+}
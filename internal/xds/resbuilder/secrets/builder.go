@@ -2,6 +2,7 @@ package secrets
 
 import (
 	"fmt"
+	"sort"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -73,14 +74,43 @@ func (b *Builder) GetSecretNameToDomains(
 		return nil, err
 	}
 
+	var secretNameToDomains map[helpers.NamespacedName][]string
 	switch tlsType {
 	case utils.SecretRefType:
-		return b.getSecretNameToDomainsViaSecretRef(vs.Spec.TlsConfig.SecretRef, vs.Namespace, domains), nil
+		secretNameToDomains = b.getSecretNameToDomainsViaSecretRef(vs.Spec.TlsConfig.SecretRef, vs.Namespace, domains)
 	case utils.AutoDiscoveryType:
-		return b.getSecretNameToDomainsViaAutoDiscovery(domains, vs.Namespace)
+		secretNameToDomains, err = b.getSecretNameToDomainsViaAutoDiscovery(domains, vs.Namespace)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unknown TLS type: %s", tlsType)
 	}
+
+	if err := b.checkAccess(vs, secretNameToDomains); err != nil {
+		return nil, err
+	}
+	return secretNameToDomains, nil
+}
+
+// checkAccess verifies that the VirtualService may use the secrets. Node IDs of
+// common VirtualServices are only known when mixing and are checked there.
+func (b *Builder) checkAccess(vs *v1alpha1.VirtualService, secretNameToDomains map[helpers.NamespacedName][]string) error {
+	nodeIDs := vs.GetNodeIDs()
+	if len(nodeIDs) == 1 && nodeIDs[0] == "*" {
+		nodeIDs = nil
+	}
+	secretNames := make([]helpers.NamespacedName, 0, len(secretNameToDomains))
+	for secretName := range secretNameToDomains {
+		secretNames = append(secretNames, secretName)
+	}
+	sort.Slice(secretNames, func(i, j int) bool { return secretNames[i].String() < secretNames[j].String() })
+	for _, secretName := range secretNames {
+		if err := CheckAccess(b.store.GetSecret(secretName), vs.GetAccessGroup(), nodeIDs); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // getSecretNameToDomainsViaSecretRef maps domains to a single secret for secretRef type
@@ -117,7 +147,7 @@ func (b *Builder) getSecretNameToDomainsViaAutoDiscovery(
 		result := b.store.GetDomainSecretWithWildcardFallbackInfo(domain, preferredNamespace)
 
 		if result.Secret == nil {
-			return nil, fmt.Errorf("can't find secret for domain %s", domain)
+			return nil, utils.NewNotFoundError(utils.KindSecret, "can't find secret for domain %s", domain)
 		}
 
 		// Log when wildcard fallback is used due to expired/unknown exact cert
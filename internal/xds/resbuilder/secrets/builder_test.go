@@ -752,6 +752,60 @@ func TestGetSecretNameToDomains_AutoDiscovery_NamespacePreference(t *testing.T)
 	assert.Equal(t, []string{"example.com"}, result[expectedNN])
 }
 
+// =============================================================================
+// Delivery policy tests
+// =============================================================================
+
+func TestGetSecretNameToDomains_DeliveryPolicy(t *testing.T) {
+	s := store.NewOptimizedStore()
+	s.SetSecret(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "team-a-tls",
+			Namespace: "certs",
+			Annotations: map[string]string{
+				"envoy.kaasops.io/domains":               "a.example.com",
+				"envoy.kaasops.io/allowed-node-ids":      "edge-a, edge-shared",
+				"envoy.kaasops.io/allowed-access-groups": "team-a",
+			},
+		},
+		Data: map[string][]byte{
+			"tls.crt": testutil.GenerateTestCertificate(time.Now().Add(24 * time.Hour)),
+			"tls.key": []byte("key"),
+		},
+	})
+	builder := NewBuilder(s)
+
+	makeVS := func(accessGroup string, nodeIDs ...string) *v1alpha1.VirtualService {
+		vs := &v1alpha1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-vs", Namespace: "team-a", Annotations: map[string]string{}},
+			Spec: v1alpha1.VirtualServiceSpec{
+				VirtualServiceCommonSpec: v1alpha1.VirtualServiceCommonSpec{
+					TlsConfig: &v1alpha1.TlsConfig{AutoDiscovery: boolPtr(true)},
+				},
+			},
+		}
+		vs.SetNodeIDs(nodeIDs)
+		vs.SetAccessGroup(accessGroup)
+		return vs
+	}
+
+	_, err := builder.GetSecretNameToDomains(makeVS("team-a", "edge-a", "edge-shared"), []string{"a.example.com"})
+	require.NoError(t, err)
+
+	var accessErr *AccessError
+	_, err = builder.GetSecretNameToDomains(makeVS("team-a", "edge-a", "edge-b"), []string{"a.example.com"})
+	require.ErrorAs(t, err, &accessErr)
+	assert.Contains(t, err.Error(), "for node edge-b")
+
+	_, err = builder.GetSecretNameToDomains(makeVS("team-b", "edge-a"), []string{"a.example.com"})
+	require.ErrorAs(t, err, &accessErr)
+	assert.Contains(t, err.Error(), "for access group team-b")
+
+	// Nodes of common VirtualServices are checked when mixing
+	_, err = builder.GetSecretNameToDomains(makeVS("team-a", "*"), []string{"a.example.com"})
+	require.NoError(t, err)
+}
+
 // =============================================================================
 // Builder method tests
 // =============================================================================
@@ -0,0 +1,67 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+)
+
+// AccessError reports a Secret that may not be delivered to a node or used by an access group
+type AccessError struct {
+	Secret helpers.NamespacedName
+	Reason string
+}
+
+func (e *AccessError) Error() string {
+	return fmt.Sprintf("secret %s is not allowed %s", e.Secret.String(), e.Reason)
+}
+
+// CheckAccess verifies the delivery policy of the Secret, set by its allowed-node-ids and
+// allowed-access-groups annotations, for a VirtualService of the access group served on the
+// node IDs. An empty access group or node IDs are not checked.
+func CheckAccess(secret *corev1.Secret, accessGroup string, nodeIDs []string) error {
+	if secret == nil {
+		return nil
+	}
+	nn := helpers.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}
+	if accessGroup != "" {
+		if groups, ok := allowedValues(secret, v1alpha1.AnnotationSecretAllowedAccessGroups); ok && !allows(groups, accessGroup) {
+			return &AccessError{Secret: nn, Reason: fmt.Sprintf("for access group %s", accessGroup)}
+		}
+	}
+	if nodes, ok := allowedValues(secret, v1alpha1.AnnotationSecretAllowedNodeIDs); ok {
+		for _, nodeID := range nodeIDs {
+			if !allows(nodes, nodeID) {
+				return &AccessError{Secret: nn, Reason: fmt.Sprintf("for node %s", nodeID)}
+			}
+		}
+	}
+	return nil
+}
+
+func allowedValues(secret *corev1.Secret, annotation string) ([]string, bool) {
+	value, ok := secret.Annotations[annotation]
+	if !ok {
+		return nil, false
+	}
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values, true
+}
+
+func allows(allowed []string, value string) bool {
+	for _, v := range allowed {
+		if v == "*" || v == value {
+			return true
+		}
+	}
+	return false
+}
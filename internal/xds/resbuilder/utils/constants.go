@@ -3,9 +3,10 @@ package utils
 // TypeURL constants for Envoy filters and extensions
 const (
 	// HTTP Filters
-	TypeURLOAuth2 = "type.googleapis.com/envoy.extensions.filters.http.oauth2.v3.OAuth2"
-	TypeURLRouter = "type.googleapis.com/envoy.extensions.filters.http.router.v3.Router"
-	TypeURLCORS   = "type.googleapis.com/envoy.extensions.filters.http.cors.v3.Cors"
+	TypeURLOAuth2    = "type.googleapis.com/envoy.extensions.filters.http.oauth2.v3.OAuth2"
+	TypeURLRouter    = "type.googleapis.com/envoy.extensions.filters.http.router.v3.Router"
+	TypeURLCORS      = "type.googleapis.com/envoy.extensions.filters.http.cors.v3.Cors"
+	TypeURLRateLimit = "type.googleapis.com/envoy.extensions.filters.http.ratelimit.v3.RateLimit"
 
 	// Network Filters
 	TypeURLTCPProxy = "type.googleapis.com/envoy.extensions.filters.network.tcp_proxy.v3.TcpProxy"
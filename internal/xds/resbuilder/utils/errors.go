@@ -0,0 +1,42 @@
+package utils
+
+import "fmt"
+
+// ObjectKind is the kind of an object VirtualServices are built from
+type ObjectKind string
+
+// Kinds of objects reported by NotFoundError
+const (
+	KindListener ObjectKind = "Listener"
+	KindCluster  ObjectKind = "Cluster"
+	KindSecret   ObjectKind = "Secret"
+	KindTemplate ObjectKind = "VirtualServiceTemplate"
+)
+
+// NotFoundError reports an object of the kind a VirtualService references which does not exist
+type NotFoundError struct {
+	Kind    ObjectKind
+	message string
+}
+
+// NewNotFoundError returns a NotFoundError of the kind with the formatted message
+func NewNotFoundError(kind ObjectKind, format string, args ...any) *NotFoundError {
+	return &NotFoundError{Kind: kind, message: fmt.Sprintf(format, args...)}
+}
+
+func (e *NotFoundError) Error() string {
+	return e.message
+}
+
+// TemplateError reports a VirtualService failing to apply its template
+type TemplateError struct {
+	Err error
+}
+
+func (e *TemplateError) Error() string {
+	return "failed to apply template: " + e.Err.Error()
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
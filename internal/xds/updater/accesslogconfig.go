@@ -5,6 +5,7 @@ import (
 
 	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -14,14 +15,16 @@ func (c *CacheUpdater) ApplyAccessLogConfig(ctx context.Context, alc *v1alpha1.A
 	prevALC := c.store.GetAccessLog(helpers.NamespacedName{Namespace: alc.Namespace, Name: alc.Name})
 	if prevALC == nil {
 		c.store.SetAccessLog(alc)
-		_ = c.rebuildSnapshots(ctx)
+		c.invalidateDependents(dependency(store.DependencyAccessLogConfig, alc.Namespace, alc.Name))
+		_ = c.requestRebuild(ctx)
 		return
 	}
 	if prevALC.IsEqual(alc) {
 		return
 	}
 	c.store.SetAccessLog(alc)
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencyAccessLogConfig, alc.Namespace, alc.Name))
+	_ = c.requestRebuild(ctx)
 }
 
 func (c *CacheUpdater) DeleteAccessLogConfig(ctx context.Context, alc types.NamespacedName) {
@@ -31,5 +34,6 @@ func (c *CacheUpdater) DeleteAccessLogConfig(ctx context.Context, alc types.Name
 		return
 	}
 	c.store.DeleteAccessLog(helpers.NamespacedName{Namespace: alc.Namespace, Name: alc.Name})
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencyAccessLogConfig, alc.Namespace, alc.Name))
+	_ = c.requestRebuild(ctx)
 }
@@ -31,13 +31,13 @@ func (m *mockStore) SetVirtualServiceTemplate(vst *v1alpha1.VirtualServiceTempla
 
 // SetVirtualServiceStatus implements the status-only update interface
 // This prevents rebuildSnapshots from calling SetVirtualService for status updates
-func (m *mockStore) SetVirtualServiceStatus(nn helpers.NamespacedName, invalid bool, message string) {
+func (m *mockStore) SetVirtualServiceStatus(nn helpers.NamespacedName, invalid bool, message string, generation int64) {
 	m.setVSStatusCalls++
 	// Delegate to real store if it supports this method
 	if statusStore, ok := m.Store.(interface {
-		SetVirtualServiceStatus(helpers.NamespacedName, bool, string)
+		SetVirtualServiceStatus(helpers.NamespacedName, bool, string, int64)
 	}); ok {
-		statusStore.SetVirtualServiceStatus(nn, invalid, message)
+		statusStore.SetVirtualServiceStatus(nn, invalid, message, generation)
 	}
 }
 
@@ -14,24 +14,28 @@ func (c *CacheUpdater) ApplyCluster(ctx context.Context, cl *v1alpha1.Cluster) {
 	prevCluster := c.store.GetCluster(helpers.NamespacedName{Namespace: cl.Namespace, Name: cl.Name})
 	if prevCluster == nil {
 		c.store.SetCluster(cl)
-		_ = c.rebuildSnapshots(ctx)
+		c.invalidateDependents(clusterDependencies(cl)...)
+		_ = c.requestRebuild(ctx)
 		return
 	}
 	if prevCluster.IsEqual(cl) {
 		return
 	}
 	c.store.SetCluster(cl)
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(clusterDependencies(prevCluster, cl)...)
+	_ = c.requestRebuild(ctx)
 }
 
 func (c *CacheUpdater) DeleteCluster(ctx context.Context, cl types.NamespacedName) {
 	c.mx.Lock()
 	defer c.mx.Unlock()
-	if !c.store.IsExistingCluster(helpers.NamespacedName{Namespace: cl.Namespace, Name: cl.Name}) {
+	prevCluster := c.store.GetCluster(helpers.NamespacedName{Namespace: cl.Namespace, Name: cl.Name})
+	if prevCluster == nil {
 		return
 	}
 	c.store.DeleteCluster(helpers.NamespacedName{Namespace: cl.Namespace, Name: cl.Name})
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(clusterDependencies(prevCluster)...)
+	_ = c.requestRebuild(ctx)
 }
 
 func (c *CacheUpdater) GetSpecCluster(specCluster string) *v1alpha1.Cluster {
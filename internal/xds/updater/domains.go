@@ -0,0 +1,153 @@
+package updater
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/secrets"
+	"go.uber.org/multierr"
+)
+
+// builtVirtualService is a VirtualService whose resources were built successfully
+// and still have to pass the domain ownership check.
+type builtVirtualService struct {
+	vs      *v1alpha1.VirtualService
+	nodeIDs []string
+	res     *resbuilder.Resources
+}
+
+func (b builtVirtualService) namespacedName() helpers.NamespacedName {
+	return helpers.NamespacedName{Namespace: b.vs.Namespace, Name: b.vs.Name}
+}
+
+// sortByCreation orders VirtualServices from the oldest to the newest, so the older one
+// wins a domain conflict. VirtualServices without creationTimestamp are not created yet
+// (webhook dry runs) and go last. Ties are broken by namespace and name.
+func sortByCreation(vss []builtVirtualService) {
+	sort.SliceStable(vss, func(i, j int) bool {
+		ti, tj := vss[i].vs.CreationTimestamp, vss[j].vs.CreationTimestamp
+		if ti.IsZero() != tj.IsZero() {
+			return tj.IsZero()
+		}
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		nnI, nnJ := vss[i].namespacedName(), vss[j].namespacedName()
+		return nnI.String() < nnJ.String()
+	})
+}
+
+// domainOwners maps a domain, or a scope key, on a node to the VirtualService serving it
+type domainOwners map[string]helpers.NamespacedName
+
+// claim assigns the domains and the keys of the scoped route configurations on the nodes to
+// the VirtualService. Scope keys are hosts without port shared by all scoped Listeners of a
+// node, so domains differing only by port may not be served by different VirtualServices
+// there: Envoy would reject all scopes of the node. If any of them is already owned by
+// another VirtualService, nothing is claimed and an error naming the owner is returned.
+func (o domainOwners) claim(
+	vs helpers.NamespacedName,
+	nodeIDs []string,
+	domains []string,
+	scopes []*routev3.ScopedRouteConfiguration,
+) error {
+	scopeHosts := scopeKeys(scopes)
+	sortedNodes := append([]string(nil), nodeIDs...)
+	sort.Strings(sortedNodes)
+	for _, nodeID := range sortedNodes {
+		for _, domain := range domains {
+			if owner, ok := o[nodeIDDomain(nodeID, domain)]; ok && owner != vs {
+				return fmt.Errorf("duplicate domain %s for node %s: already used by VirtualService %s",
+					domain, nodeID, owner.String())
+			}
+		}
+		for _, host := range scopeHosts {
+			if owner, ok := o[nodeIDScopeKey(nodeID, host)]; ok && owner != vs {
+				return fmt.Errorf("duplicate scoped routes key %s for node %s: already used by VirtualService %s",
+					host, nodeID, owner.String())
+			}
+		}
+	}
+	for _, nodeID := range nodeIDs {
+		for _, domain := range domains {
+			o[nodeIDDomain(nodeID, domain)] = vs
+		}
+		for _, host := range scopeHosts {
+			o[nodeIDScopeKey(nodeID, host)] = vs
+		}
+	}
+	return nil
+}
+
+// scopeKeys returns the hosts the scoped route configurations are selected by
+func scopeKeys(scopes []*routev3.ScopedRouteConfiguration) []string {
+	var hosts []string
+	for _, scope := range scopes {
+		for _, fragment := range scope.GetKey().GetFragments() {
+			if host := fragment.GetStringKey(); host != "" {
+				hosts = append(hosts, host)
+			}
+		}
+	}
+	return hosts
+}
+
+// nodeIDScopeKey keys scope hosts apart from domains, which may not contain '#'
+func nodeIDScopeKey(nodeID, host string) string {
+	return nodeID + "#" + host
+}
+
+func sortedNodeIDs(nodeIDs map[string]struct{}) []string {
+	result := make([]string, 0, len(nodeIDs))
+	for nodeID := range nodeIDs {
+		result = append(result, nodeID)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// rejectionError is reported by dry runs for a VirtualService rejected for a duplicate
+// domain or a secret it may not use
+type rejectionError struct {
+	vs  helpers.NamespacedName
+	err error
+}
+
+func (e *rejectionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *rejectionError) Unwrap() error {
+	return e.err
+}
+
+// dropKnownRejections removes errors of VirtualServices that are already rejected in
+// the current state, so an existing rejection does not block validation of unrelated
+// changes. Only newly introduced rejections are reported.
+func dropKnownRejections(err error, known map[helpers.NamespacedName][]string) error {
+	if err == nil || len(known) == 0 {
+		return err
+	}
+	var kept []error
+	for _, e := range multierr.Errors(err) {
+		var rejection *rejectionError
+		if errors.As(e, &rejection) {
+			if _, ok := known[rejection.vs]; ok {
+				continue
+			}
+		}
+		kept = append(kept, e)
+	}
+	return multierr.Combine(kept...)
+}
+
+// isSecretAccessError reports whether a VirtualService failed to build for using a secret it may not use
+func isSecretAccessError(err error) bool {
+	var accessErr *secrets.AccessError
+	return errors.As(err, &accessErr)
+}
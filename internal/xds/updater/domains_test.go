@@ -0,0 +1,153 @@
+package updater
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TestDuplicateDomains_NewerVirtualServiceInvalid verifies that a duplicate domain marks only
+// the newer VirtualService invalid, other nodes are still committed, and the newer one is
+// delivered to all its nodes once the conflict is resolved.
+func TestDuplicateDomains_NewerVirtualServiceInvalid(t *testing.T) {
+	ctx := context.Background()
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, _ store.Store) (*resbuilder.Resources, error) {
+		domain := vs.Name + ".example.com"
+		if vs.Name == "vs-old" || strings.HasPrefix(vs.Name, "vs-new") {
+			domain = "shared.example.com"
+		}
+		return &resbuilder.Resources{
+			Listener:    helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain: []*listenerv3.FilterChain{{Name: vs.Name}},
+			Clusters:    []*clusterv3.Cluster{{Name: "cluster-" + vs.Name}},
+			Domains:     []string{domain},
+		}, nil
+	})()
+
+	created := time.Now()
+	makeCreatedVS := func(name string, nodeIDs []string, age time.Duration) *v1alpha1.VirtualService {
+		vs := makeVSWithListener(name, nodeIDs, "http")
+		vs.CreationTimestamp = metav1.NewTime(created.Add(-age))
+		return vs
+	}
+
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	// vs-new is applied first, so the outcome does not depend on apply order
+	cu.ApplyVirtualService(ctx, makeCreatedVS("vs-new", []string{"node-a", "node-b"}, time.Minute))
+	cu.ApplyVirtualService(ctx, makeCreatedVS("vs-old", []string{"node-a"}, time.Hour))
+	cu.ApplyVirtualService(ctx, makeCreatedVS("vs-other", []string{"node-c"}, time.Hour))
+
+	if err := cu.RebuildSnapshots(ctx); err != nil {
+		t.Fatalf("expected rebuild to succeed, got %v", err)
+	}
+
+	vsNew := cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: "vs-new"})
+	if !vsNew.Status.Invalid || !strings.Contains(vsNew.Status.Message, "already used by VirtualService ns/vs-old") {
+		t.Errorf("expected vs-new to be invalid naming vs-old, got %+v", vsNew.Status)
+	}
+	if vsOld := cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: "vs-old"}); vsOld.Status.Invalid {
+		t.Errorf("expected vs-old to stay valid, got %+v", vsOld.Status)
+	}
+	assertClusters(t, cu, "node-a", "cluster-vs-old")
+	assertClusters(t, cu, "node-b")
+	assertClusters(t, cu, "node-c", "cluster-vs-other")
+
+	// Dry runs reject changes introducing a duplicate, but not unrelated changes
+	if err := cu.DryBuildSnapshotsWithVirtualService(ctx, makeVSWithListener("vs-newest", []string{"node-a"}, "http")); err == nil {
+		t.Errorf("expected dry run to fail on duplicate domain")
+	}
+	if err := cu.DryBuildSnapshotsWithVirtualService(ctx, makeVSWithListener("vs-other", []string{"node-c"}, "http")); err != nil {
+		t.Errorf("expected dry run of unrelated change to pass, got %v", err)
+	}
+	// The known rejection of the validated VirtualService itself is not dropped
+	resubmitted := makeCreatedVS("vs-new", []string{"node-a", "node-b"}, time.Minute)
+	if err := cu.DryBuildSnapshotsWithVirtualService(ctx, resubmitted); err == nil {
+		t.Errorf("expected dry run of rejected VirtualService keeping the duplicate domain to fail")
+	}
+
+	if err := cu.DeleteVirtualService(ctx, types.NamespacedName{Namespace: "ns", Name: "vs-old"}); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if vsNew := cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: "vs-new"}); vsNew.Status.Invalid {
+		t.Errorf("expected vs-new to become valid, got %+v", vsNew.Status)
+	}
+	assertClusters(t, cu, "node-a", "cluster-vs-new")
+	assertClusters(t, cu, "node-b", "cluster-vs-new")
+}
+
+func assertClusters(t *testing.T, cu *CacheUpdater, nodeID string, expected ...string) {
+	t.Helper()
+	clusters, _ := cu.snapshotCache.GetClusters(nodeID)
+	names := make([]string, 0, len(clusters))
+	for _, cl := range clusters {
+		names = append(names, cl.Name)
+	}
+	sort.Strings(names)
+	expected = append([]string(nil), expected...)
+	sort.Strings(expected)
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("node %s: expected clusters %v, got %v", nodeID, expected, names)
+	}
+}
+
+// TestDuplicateScopeKeys_NewerVirtualServiceInvalid verifies that VirtualServices on scoped
+// route Listeners whose domains differ only by port do not share a scope key on a node.
+func TestDuplicateScopeKeys_NewerVirtualServiceInvalid(t *testing.T) {
+	ctx := context.Background()
+	ports := map[string]string{"vs-old": "8080", "vs-new": "9090", "vs-other": "8080"}
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, _ store.Store) (*resbuilder.Resources, error) {
+		return &resbuilder.Resources{
+			Listener:    helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain: []*listenerv3.FilterChain{{Name: vs.Name}},
+			Clusters:    []*clusterv3.Cluster{{Name: "cluster-" + vs.Name}},
+			Domains:     []string{"shared.example.com:" + ports[vs.Name]},
+			ScopedRoutes: []*routev3.ScopedRouteConfiguration{{
+				Name: vs.Name + "/shared.example.com",
+				Key: &routev3.ScopedRouteConfiguration_Key{
+					Fragments: []*routev3.ScopedRouteConfiguration_Key_Fragment{{
+						Type: &routev3.ScopedRouteConfiguration_Key_Fragment_StringKey{StringKey: "shared.example.com"},
+					}},
+				},
+			}},
+		}, nil
+	})()
+
+	created := time.Now()
+	vsOld := makeVSWithListener("vs-old", []string{"node-a"}, "http")
+	vsOld.CreationTimestamp = metav1.NewTime(created.Add(-time.Hour))
+	vsNew := makeVSWithListener("vs-new", []string{"node-a"}, "http")
+	vsNew.CreationTimestamp = metav1.NewTime(created.Add(-time.Minute))
+	vsOther := makeVSWithListener("vs-other", []string{"node-b"}, "http")
+	vsOther.CreationTimestamp = metav1.NewTime(created)
+
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	cu.ApplyVirtualService(ctx, vsNew)
+	cu.ApplyVirtualService(ctx, vsOld)
+	cu.ApplyVirtualService(ctx, vsOther)
+
+	status := cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: "vs-new"}).Status
+	if !status.Invalid || !strings.Contains(status.Message, "duplicate scoped routes key shared.example.com") {
+		t.Errorf("expected vs-new to be invalid for a duplicate scope key, got %+v", status)
+	}
+	for _, name := range []string{"vs-old", "vs-other"} {
+		vs := cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: name})
+		if status := vs.Status; status.Invalid {
+			t.Errorf("expected %s to stay valid, got %+v", name, status)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package updater
+
+import (
+	"context"
+
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/endpoints"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Endpoints are not used to build VirtualServices: a change of endpoints only re-mixes
+// the nodes serving EDS clusters with the changed load assignments. Other resource
+// types of these nodes stay the same, so only their EDS version is bumped.
+
+func (c *CacheUpdater) ApplyEndpoint(ctx context.Context, endpoint *v1alpha1.Endpoint) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	prevEndpoint := c.store.GetEndpoint(helpers.NamespacedName{Namespace: endpoint.Namespace, Name: endpoint.Name})
+	if prevEndpoint.IsEqual(endpoint) {
+		return
+	}
+	c.store.SetEndpoint(endpoint)
+	if c.invalidateLoadAssignments(loadAssignmentNames(prevEndpoint, endpoint)) {
+		_ = c.requestRebuild(ctx)
+	}
+}
+
+func (c *CacheUpdater) DeleteEndpoint(ctx context.Context, nn types.NamespacedName) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	prevEndpoint := c.store.GetEndpoint(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
+	if prevEndpoint == nil {
+		return
+	}
+	c.store.DeleteEndpoint(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
+	if c.invalidateLoadAssignments(loadAssignmentNames(prevEndpoint)) {
+		_ = c.requestRebuild(ctx)
+	}
+}
+
+func (c *CacheUpdater) ApplyEndpointSlice(ctx context.Context, slice *discoveryv1.EndpointSlice) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	prevSlice := c.store.GetEndpointSlice(helpers.NamespacedName{Namespace: slice.Namespace, Name: slice.Name})
+	if prevSlice != nil && prevSlice.ResourceVersion == slice.ResourceVersion {
+		return
+	}
+	c.store.SetEndpointSlice(slice)
+	if c.invalidateServices(prevSlice, slice) {
+		_ = c.requestRebuild(ctx)
+	}
+}
+
+func (c *CacheUpdater) DeleteEndpointSlice(ctx context.Context, nn types.NamespacedName) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	prevSlice := c.store.GetEndpointSlice(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
+	if prevSlice == nil {
+		return
+	}
+	c.store.DeleteEndpointSlice(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
+	if c.invalidateServices(prevSlice) {
+		_ = c.requestRebuild(ctx)
+	}
+}
+
+// invalidateServices re-mixes nodes with EDS clusters referencing the Services of the slices.
+// It reports whether any node was affected: slices of Services no EDS cluster references
+// are only stored, so churn of unrelated Services does not trigger rebuilds.
+// Must be called with c.mx held.
+func (c *CacheUpdater) invalidateServices(slices ...*discoveryv1.EndpointSlice) bool {
+	services := make(map[helpers.NamespacedName]struct{})
+	for _, slice := range slices {
+		if slice == nil {
+			continue
+		}
+		if service, ok := store.EndpointSliceService(slice); ok {
+			services[service] = struct{}{}
+		}
+	}
+	if len(services) == 0 {
+		return false
+	}
+	return c.invalidateLoadAssignmentsFunc(func(name string) bool {
+		ref, ok := endpoints.ParseServiceRef(name)
+		if !ok {
+			return false
+		}
+		_, ok = services[ref.Service]
+		return ok
+	})
+}
+
+// invalidateLoadAssignments re-mixes nodes with EDS clusters using the given load assignments
+// and reports whether any node was affected. Must be called with c.mx held.
+func (c *CacheUpdater) invalidateLoadAssignments(names map[string]struct{}) bool {
+	if len(names) == 0 {
+		return false
+	}
+	return c.invalidateLoadAssignmentsFunc(func(name string) bool {
+		_, ok := names[name]
+		return ok
+	})
+}
+
+func (c *CacheUpdater) invalidateLoadAssignmentsFunc(match func(name string) bool) bool {
+	touched := false
+	for nodeID := range c.snapshotCache.GetNodeIDsAsMap() {
+		clusters, err := c.snapshotCache.GetClusters(nodeID)
+		if err != nil {
+			continue
+		}
+		for _, cl := range clusters {
+			if name, ok := endpoints.ServiceName(cl); ok && match(name) {
+				c.incremental.touch(nodeID)
+				touched = true
+				break
+			}
+		}
+	}
+	return touched
+}
+
+func loadAssignmentNames(eps ...*v1alpha1.Endpoint) map[string]struct{} {
+	names := make(map[string]struct{})
+	for _, ep := range eps {
+		if ep == nil {
+			continue
+		}
+		if cla, err := ep.UnmarshalV3(); err == nil {
+			names[cla.ClusterName] = struct{}{}
+		}
+	}
+	return names
+}
+
+func (c *CacheUpdater) GetSpecEndpoint(clusterName string) *v1alpha1.Endpoint {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	return c.store.GetSpecEndpoint(clusterName)
+}
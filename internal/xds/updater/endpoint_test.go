@@ -0,0 +1,137 @@
+package updater
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+// TestEndpointChurn_OnlyEndpointsVersionBumped verifies that load assignments of EDS clusters
+// follow EndpointSlices and Endpoint resources without rebuilding VirtualServices or bumping
+// versions of other resource types.
+func TestEndpointChurn_OnlyEndpointsVersionBumped(t *testing.T) {
+	ctx := context.Background()
+	builds := 0
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, _ store.Store) (*resbuilder.Resources, error) {
+		builds++
+		return &resbuilder.Resources{
+			Listener:    helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain: []*listenerv3.FilterChain{{Name: vs.Name}},
+			Clusters: []*clusterv3.Cluster{
+				{
+					Name:                 "backend",
+					ClusterDiscoveryType: &clusterv3.Cluster_Type{Type: clusterv3.Cluster_EDS},
+					EdsClusterConfig:     &clusterv3.Cluster_EdsClusterConfig{ServiceName: "ns/backend:http"},
+				},
+				{
+					Name:                 "static",
+					ClusterDiscoveryType: &clusterv3.Cluster_Type{Type: clusterv3.Cluster_EDS},
+				},
+			},
+			Domains: []string{vs.Name + ".example.com"},
+		}, nil
+	})()
+
+	makeSlice := func(addresses ...string) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "ns",
+				Name:            "backend-abc",
+				Labels:          map[string]string{discoveryv1.LabelServiceName: "backend"},
+				ResourceVersion: addresses[len(addresses)-1],
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports:       []discoveryv1.EndpointPort{{Name: ptr.To("http"), Port: ptr.To[int32](8080)}},
+			Endpoints:   []discoveryv1.Endpoint{{Addresses: addresses}},
+		}
+	}
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	loadAssignments := func() map[string]int {
+		t.Helper()
+		las, err := cu.snapshotCache.GetEndpoints(testNodeID)
+		if err != nil {
+			t.Fatalf("no snapshot: %v", err)
+		}
+		result := make(map[string]int)
+		for _, la := range las {
+			result[la.ClusterName] = 0
+			for _, locality := range la.Endpoints {
+				result[la.ClusterName] += len(locality.LbEndpoints)
+			}
+		}
+		return result
+	}
+
+	cu.ApplyEndpointSlice(ctx, makeSlice("10.0.0.1"))
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs", []string{testNodeID}, "http"))
+	if err := cu.RebuildSnapshots(ctx); err != nil {
+		t.Fatalf("rebuild failed: %v", err)
+	}
+
+	// Unresolved load assignments are empty, so the snapshot stays consistent
+	if got := loadAssignments(); len(got) != 2 || got["ns/backend:http"] != 1 || got["static"] != 0 {
+		t.Fatalf("unexpected load assignments %v", got)
+	}
+	versionsBefore, _ := cu.snapshotCache.GetVersions(testNodeID)
+	buildsBefore := builds
+
+	cu.ApplyEndpointSlice(ctx, makeSlice("10.0.0.1", "10.0.0.2"))
+	cu.ApplyEndpoint(ctx, &v1alpha1.Endpoint{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "static"},
+		Spec: &runtime.RawExtension{Raw: []byte(`{"cluster_name":"static","endpoints":[{"lb_endpoints":[` +
+			`{"endpoint":{"address":{"socket_address":{"address":"10.1.0.1","port_value":80}}}}]}]}`)},
+	})
+
+	if got := loadAssignments(); got["ns/backend:http"] != 2 || got["static"] != 1 {
+		t.Errorf("expected load assignments to follow endpoints, got %v", got)
+	}
+	if builds != buildsBefore {
+		t.Errorf("expected no VirtualService rebuilds, got %d", builds-buildsBefore)
+	}
+	versionsAfter, _ := cu.snapshotCache.GetVersions(testNodeID)
+	for typ, version := range versionsBefore {
+		if typ == "endpoints" {
+			if versionsAfter[typ] == version {
+				t.Errorf("expected endpoints version to be bumped")
+			}
+			continue
+		}
+		if versionsAfter[typ] != version {
+			t.Errorf("expected %s version to stay %s, got %s", typ, version, versionsAfter[typ])
+		}
+	}
+
+	cu.DeleteEndpointSlice(ctx, types.NamespacedName{Namespace: "ns", Name: "backend-abc"})
+	if got := loadAssignments(); got["ns/backend:http"] != 0 {
+		t.Errorf("expected no endpoints after slice deletion, got %v", got)
+	}
+
+	// Slices of Services no EDS cluster references are stored without requesting a rebuild
+	cu.scheduler = newRebuildScheduler(time.Minute, time.Minute)
+	other := makeSlice("10.0.0.3")
+	other.Name = "other-abc"
+	other.Labels = map[string]string{discoveryv1.LabelServiceName: "other"}
+	cu.ApplyEndpointSlice(ctx, other)
+	cu.DeleteEndpointSlice(ctx, types.NamespacedName{Namespace: "ns", Name: "other-abc"})
+	if pending := cu.scheduler.take(); pending != 0 {
+		t.Errorf("expected no rebuild for slices of unreferenced Services, got %d", pending)
+	}
+	cu.ApplyEndpointSlice(ctx, makeSlice("10.0.0.4"))
+	if pending := cu.scheduler.take(); pending != 1 {
+		t.Errorf("expected a rebuild for slices of referenced Services, got %d", pending)
+	}
+}
@@ -0,0 +1,68 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestPartialCommit_FailingNodeKeepsSnapshot verifies that a node failing to build keeps
+// its previous snapshot and is reported unhealthy, while other nodes are still updated.
+func TestPartialCommit_FailingNodeKeepsSnapshot(t *testing.T) {
+	ctx := context.Background()
+	failA := false
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, _ store.Store) (*resbuilder.Resources, error) {
+		if vs.Name == "vs-a" && failA {
+			return nil, errors.New("secret not found")
+		}
+		return &resbuilder.Resources{
+			Listener:    helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain: []*listenerv3.FilterChain{{Name: vs.Name}},
+			Clusters:    []*clusterv3.Cluster{{Name: "cluster-" + vs.Name + "-" + vs.Spec.Listener.Name}},
+			Domains:     []string{vs.Name + ".example.com"},
+		}, nil
+	})()
+
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-a", []string{"node-a"}, "http"))
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-b", []string{"node-b"}, "http"))
+	if err := cu.RebuildSnapshots(ctx); err != nil {
+		t.Fatalf("rebuild failed: %v", err)
+	}
+
+	failA = true
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-a", []string{"node-a"}, "http-v2"))
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-b", []string{"node-b"}, "http-v2"))
+
+	assertClusters(t, cu, "node-a", "cluster-vs-a-http")
+	assertClusters(t, cu, "node-b", "cluster-vs-b-http-v2")
+
+	healthA, ok := cu.snapshotCache.GetNodeHealth("node-a")
+	if !ok || healthA.Healthy || healthA.ConsecutiveFailures != 2 || healthA.LastSuccess == nil {
+		t.Errorf("expected node-a to be failing since the last success, got %+v", healthA)
+	}
+	if healthB, _ := cu.snapshotCache.GetNodeHealth("node-b"); !healthB.Healthy {
+		t.Errorf("expected node-b to be healthy, got %+v", healthB)
+	}
+	if got := testutil.ToFloat64(nodeSnapshotHealthy.WithLabelValues("node-a")); got != 0 {
+		t.Errorf("expected node-a health metric 0, got %v", got)
+	}
+
+	// Fixing the VirtualService recovers the node
+	failA = false
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-a", []string{"node-a"}, "http"))
+	assertClusters(t, cu, "node-a", "cluster-vs-a-http")
+	if healthA, _ := cu.snapshotCache.GetNodeHealth("node-a"); !healthA.Healthy || healthA.ConsecutiveFailures != 0 {
+		t.Errorf("expected node-a to recover, got %+v", healthA)
+	}
+}
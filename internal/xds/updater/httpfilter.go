@@ -3,8 +3,12 @@ package updater
 import (
 	"context"
 
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/filters"
+	"google.golang.org/protobuf/proto"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -14,14 +18,21 @@ func (c *CacheUpdater) ApplyHTTPFilter(ctx context.Context, httpFilter *v1alpha1
 	prevHTTPFilter := c.store.GetHTTPFilter(helpers.NamespacedName{Namespace: httpFilter.Namespace, Name: httpFilter.Name})
 	if prevHTTPFilter == nil {
 		c.store.SetHTTPFilter(httpFilter)
-		_ = c.rebuildSnapshots(ctx)
+		c.invalidateDependents(dependency(store.DependencyHTTPFilter, httpFilter.Namespace, httpFilter.Name))
+		_ = c.requestRebuild(ctx)
 		return
 	}
 	if prevHTTPFilter.IsEqual(httpFilter) {
 		return
 	}
 	c.store.SetHTTPFilter(httpFilter)
-	_ = c.rebuildSnapshots(ctx)
+	if sameHCMFilters(prevHTTPFilter, httpFilter) {
+		// Only extension configs changed: HCMs and VirtualServices using them stay the same
+		c.touchExtensionConfigNodes(httpFilter)
+	} else {
+		c.invalidateDependents(dependency(store.DependencyHTTPFilter, httpFilter.Namespace, httpFilter.Name))
+	}
+	_ = c.requestRebuild(ctx)
 }
 
 func (c *CacheUpdater) DeleteHTTPFilter(ctx context.Context, nn types.NamespacedName) {
@@ -31,5 +42,51 @@ func (c *CacheUpdater) DeleteHTTPFilter(ctx context.Context, nn types.Namespaced
 		return
 	}
 	c.store.DeleteHTTPFilter(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencyHTTPFilter, nn.Namespace, nn.Name))
+	_ = c.requestRebuild(ctx)
+}
+
+// sameHCMFilters reports whether both HttpFilters are served over ECDS and are referenced
+// by HCMs the same way, so that only their extension configs differ.
+func sameHCMFilters(prev, hf *v1alpha1.HttpFilter) bool {
+	if !prev.IsConfigDiscovery() || !hf.IsConfigDiscovery() {
+		return false
+	}
+	prevFilters, err := filters.HTTPFilters(prev)
+	if err != nil {
+		return false
+	}
+	hcmFilters, err := filters.HTTPFilters(hf)
+	if err != nil || len(prevFilters) != len(hcmFilters) {
+		return false
+	}
+	for i := range hcmFilters {
+		if !proto.Equal(prevFilters[i], hcmFilters[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// touchExtensionConfigNodes re-mixes the nodes served extension configs of the HttpFilter,
+// bumping their ECDS version. Must be called with c.mx held.
+func (c *CacheUpdater) touchExtensionConfigNodes(hf *v1alpha1.HttpFilter) {
+	httpFilters, err := hf.UnmarshalV3()
+	if err != nil {
+		return
+	}
+	names := make(map[string]struct{}, len(httpFilters))
+	for _, httpFilter := range httpFilters {
+		names[httpFilter.GetName()] = struct{}{}
+	}
+	c.ownersMx.RLock()
+	defer c.ownersMx.RUnlock()
+	for nodeID, byType := range c.resourceOwners {
+		for name := range byType[resource.ExtensionConfigType] {
+			if _, ok := names[name]; ok {
+				c.incremental.touch(nodeID)
+				break
+			}
+		}
+	}
 }
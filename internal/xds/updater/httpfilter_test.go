@@ -0,0 +1,116 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	bufferv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/filters"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func makeBufferHTTPFilter(name string, maxRequestBytes int, configDiscovery bool) *v1alpha1.HttpFilter {
+	return &v1alpha1.HttpFilter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        name,
+			Annotations: map[string]string{v1alpha1.AnnotationConfigDiscovery: fmt.Sprint(configDiscovery)},
+		},
+		Spec: []*runtime.RawExtension{
+			{Raw: []byte(fmt.Sprintf(`{"name":"envoy.filters.http.buffer","typed_config":{`+
+				`"@type":"type.googleapis.com/envoy.extensions.filters.http.buffer.v3.Buffer",`+
+				`"max_request_bytes":%d}}`, maxRequestBytes))},
+			{Raw: []byte(`{"name":"envoy.filters.http.router","typed_config":{` +
+				`"@type":"type.googleapis.com/envoy.extensions.filters.http.router.v3.Router"}}`)},
+		},
+	}
+}
+
+// assertBufferExtensionConfig checks max_request_bytes of the buffer extension config of the node,
+// 0 if the node is served none.
+func assertBufferExtensionConfig(t *testing.T, cu *CacheUpdater, nodeID string, expected uint32) {
+	t.Helper()
+	snapshot, err := cu.snapshotCache.GetSnapshot(nodeID)
+	if err != nil {
+		t.Fatalf("node %s: %v", nodeID, err)
+	}
+	res, ok := snapshot.GetResources(resource.ExtensionConfigType)["envoy.filters.http.buffer"]
+	if !ok {
+		if expected != 0 {
+			t.Errorf("node %s: expected a buffer extension config", nodeID)
+		}
+		return
+	}
+	buffer := &bufferv3.Buffer{}
+	if err := res.(*corev3.TypedExtensionConfig).GetTypedConfig().UnmarshalTo(buffer); err != nil {
+		t.Fatalf("node %s: %v", nodeID, err)
+	}
+	if buffer.GetMaxRequestBytes().GetValue() != expected {
+		t.Errorf("node %s: expected max_request_bytes %d, got %d", nodeID, expected, buffer.GetMaxRequestBytes().GetValue())
+	}
+}
+
+// TestExtensionConfigDiscovery verifies that filters of an HttpFilter served over ECDS are
+// referenced by config_discovery, and that a change of their typed configs bumps only the
+// ECDS version of the nodes serving them.
+func TestExtensionConfigDiscovery(t *testing.T) {
+	ctx := context.Background()
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, st store.Store) (*resbuilder.Resources, error) {
+		return &resbuilder.Resources{
+			Listener:         helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain:      []*listenerv3.FilterChain{{Name: vs.Name}},
+			Clusters:         []*clusterv3.Cluster{{Name: "cluster-" + vs.Name}},
+			Domains:          []string{vs.Name + ".example.com"},
+			ExtensionConfigs: filters.ExtensionConfigRefs(vs, st),
+		}, nil
+	})()
+
+	hf := makeBufferHTTPFilter("buffer", 1024, true)
+	hcmFilters, err := filters.HTTPFilters(hf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hcmFilters) != 2 || hcmFilters[0].GetConfigDiscovery() == nil || hcmFilters[1].GetTypedConfig() == nil {
+		t.Fatalf("expected the buffer filter to be discovered and the router filter inline, got %v", hcmFilters)
+	}
+
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	cu.ApplyHTTPFilter(ctx, hf)
+	vs1 := makeVSWithListener("vs-1", []string{"node-1"}, "http")
+	vs1.Spec.AdditionalHttpFilters = []*v1alpha1.ResourceRef{{Name: "buffer"}}
+	cu.ApplyVirtualService(ctx, vs1)
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-2", []string{"node-2"}, "http"))
+	assertBufferExtensionConfig(t, cu, "node-1", 1024)
+	assertBufferExtensionConfig(t, cu, "node-2", 0)
+
+	listenersVersion := cu.snapshotCache.GetVersion("node-1", resource.ListenerType)
+	extensionConfigsVersion := cu.snapshotCache.GetVersion("node-1", resource.ExtensionConfigType)
+	otherNodeVersion := cu.snapshotCache.GetVersion("node-2", resource.ListenerType)
+	cu.ApplyHTTPFilter(ctx, makeBufferHTTPFilter("buffer", 2048, true))
+	assertBufferExtensionConfig(t, cu, "node-1", 2048)
+	if cu.snapshotCache.GetVersion("node-1", resource.ListenerType) != listenersVersion {
+		t.Errorf("expected an extension config change to keep the listeners version")
+	}
+	if cu.snapshotCache.GetVersion("node-1", resource.ExtensionConfigType) == extensionConfigsVersion {
+		t.Errorf("expected an extension config change to bump the extension configs version")
+	}
+	if cu.snapshotCache.GetVersion("node-2", resource.ListenerType) != otherNodeVersion {
+		t.Errorf("expected nodes not serving the filter to be left untouched")
+	}
+
+	// Inlining the filters again rebuilds the VirtualServices using them
+	cu.ApplyHTTPFilter(ctx, makeBufferHTTPFilter("buffer", 2048, false))
+	assertBufferExtensionConfig(t, cu, "node-1", 0)
+}
@@ -0,0 +1,274 @@
+package updater
+
+import (
+	"slices"
+
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+)
+
+// vsBuild is the cached result of building resources for a VirtualService.
+// The VirtualService pointer identifies the stored object the result was built from:
+// Apply replaces the pointer in the store, so a different pointer means a changed spec.
+type vsBuild struct {
+	vs      *v1alpha1.VirtualService
+	nodeIDs []string
+	res     *resbuilder.Resources
+	err     error
+}
+
+// incrementalBuild carries VirtualService build results between rebuilds, so that a change
+// rebuilds only the affected VirtualServices and re-mixes only the nodes they are served on.
+// Snapshots of all other nodes are left as they are. Must be used with CacheUpdater.mx held.
+type incrementalBuild struct {
+	builds map[helpers.NamespacedName]*vsBuild
+	// dirty VirtualServices depend on objects changed since the last rebuild
+	dirty map[helpers.NamespacedName]struct{}
+	// pending nodes were affected by a rebuild that did not commit them
+	pending map[string]struct{}
+	full    bool
+	// rejected VirtualServices of the last rebuild, with their nodes
+	rejected map[helpers.NamespacedName][]string
+
+	// affected nodes of the current rebuild; nil means all nodes
+	affected map[string]struct{}
+	// failed nodes of the current rebuild kept their previous snapshots
+	failed map[string]struct{}
+
+	// references are objects VirtualServices started or stopped being built from
+	references map[store.Dependency]struct{}
+}
+
+func newIncrementalBuild() *incrementalBuild {
+	return &incrementalBuild{
+		builds:     make(map[helpers.NamespacedName]*vsBuild),
+		dirty:      make(map[helpers.NamespacedName]struct{}),
+		pending:    make(map[string]struct{}),
+		full:       true,
+		references: make(map[store.Dependency]struct{}),
+	}
+}
+
+// reset drops all cached results, so the next rebuild is a full one.
+func (b *incrementalBuild) reset() {
+	b.builds = make(map[helpers.NamespacedName]*vsBuild)
+	b.dirty = make(map[helpers.NamespacedName]struct{})
+	b.pending = make(map[string]struct{})
+	b.full = true
+	b.rejected = nil
+}
+
+// invalidate marks VirtualServices as requiring a rebuild.
+func (b *incrementalBuild) invalidate(vss ...helpers.NamespacedName) {
+	for _, vs := range vss {
+		b.dirty[vs] = struct{}{}
+	}
+}
+
+// touch marks nodes as requiring a re-mix without rebuilding their VirtualServices.
+func (b *incrementalBuild) touch(nodeIDs ...string) {
+	for _, nodeID := range nodeIDs {
+		b.pending[nodeID] = struct{}{}
+	}
+}
+
+// prepare drops stale build results and computes the nodes affected by the changes
+// since the last rebuild: old and new nodes of changed, dirty and deleted VirtualServices,
+// and of VirtualServices whose node selector matches other connected nodes.
+// Any change of a common VirtualService affects all nodes.
+func (b *incrementalBuild) prepare(vss map[helpers.NamespacedName]*v1alpha1.VirtualService, nodes nodeLabels) {
+	affected := b.pending
+	all := b.full
+
+	addNodes := func(nodeIDs []string) {
+		if isCommonVirtualService(nodeIDs) {
+			all = true
+			return
+		}
+		for _, nodeID := range nodeIDs {
+			affected[nodeID] = struct{}{}
+		}
+	}
+
+	for vsNN, cached := range b.builds {
+		vs := vss[vsNN]
+		_, isDirty := b.dirty[vsNN]
+		if vs == cached.vs && !isDirty {
+			// Resources do not depend on the nodes, only the nodes they are served on are affected
+			if nodeIDs, _ := nodes.resolveNodeIDs(vs); !slices.Equal(nodeIDs, cached.nodeIDs) {
+				addNodes(cached.nodeIDs)
+				addNodes(nodeIDs)
+				cached.nodeIDs = nodeIDs
+			}
+			continue
+		}
+		addNodes(cached.nodeIDs)
+		delete(b.builds, vsNN)
+	}
+	for vsNN, vs := range vss {
+		if _, ok := b.builds[vsNN]; !ok {
+			nodeIDs, _ := nodes.resolveNodeIDs(vs)
+			addNodes(nodeIDs)
+		}
+	}
+
+	b.dirty = make(map[helpers.NamespacedName]struct{})
+	b.pending = make(map[string]struct{})
+	b.full = false
+	b.failed = make(map[string]struct{})
+	if all {
+		b.affected = nil
+	} else {
+		b.affected = affected
+	}
+}
+
+// build returns the cached resources of the VirtualService or builds them, recording
+// the objects they depend on in the store and the nodes they are served on.
+func (b *incrementalBuild) build(
+	vs *v1alpha1.VirtualService,
+	st store.Store,
+	nodeIDs []string,
+) (*resbuilder.Resources, error) {
+	if b == nil {
+		return buildVSResources(vs, st)
+	}
+	vsNN := helpers.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+	if cached, ok := b.builds[vsNN]; ok {
+		return cached.res, cached.err
+	}
+	recorder := store.NewDependencyRecorder(st)
+	res, err := buildVSResources(vs, recorder)
+	b.changeReferences(st.GetVirtualServiceDependencies(vsNN), recorder.Dependencies())
+	st.SetVirtualServiceDependencies(vsNN, recorder.Dependencies())
+	b.builds[vsNN] = &vsBuild{vs: vs, nodeIDs: nodeIDs, res: res, err: err}
+	return res, err
+}
+
+// changeReferences records objects referenced by name by only one of the previous and the
+// current dependencies of a VirtualService.
+func (b *incrementalBuild) changeReferences(prev []store.Dependency, deps map[store.Dependency]struct{}) {
+	prevSet := make(map[store.Dependency]struct{}, len(prev))
+	for _, dep := range prev {
+		prevSet[dep] = struct{}{}
+		if _, ok := deps[dep]; !ok && dep.Name.Name != "" {
+			b.references[dep] = struct{}{}
+		}
+	}
+	for dep := range deps {
+		if _, ok := prevSet[dep]; !ok && dep.Name.Name != "" {
+			b.references[dep] = struct{}{}
+		}
+	}
+}
+
+// takeReferences returns and clears objects whose referencing VirtualServices changed.
+func (b *incrementalBuild) takeReferences() []store.Dependency {
+	refs := make([]store.Dependency, 0, len(b.references))
+	for dep := range b.references {
+		refs = append(refs, dep)
+	}
+	b.references = make(map[store.Dependency]struct{})
+	return refs
+}
+
+// setRejected records VirtualServices rejected for duplicate domains or secrets they may not use.
+// Nodes of VirtualServices that became or stopped being rejected are affected too.
+func (b *incrementalBuild) setRejected(rejected map[helpers.NamespacedName][]string) {
+	if b == nil {
+		return
+	}
+	for vs, nodeIDs := range rejected {
+		if _, ok := b.rejected[vs]; !ok {
+			b.affect(nodeIDs)
+		}
+	}
+	for vs, nodeIDs := range b.rejected {
+		if _, ok := rejected[vs]; !ok {
+			b.affect(nodeIDs)
+		}
+	}
+	b.rejected = rejected
+}
+
+func (b *incrementalBuild) affect(nodeIDs []string) {
+	if b == nil || b.affected == nil {
+		return
+	}
+	if isCommonVirtualService(nodeIDs) {
+		b.affected = nil
+		return
+	}
+	for _, nodeID := range nodeIDs {
+		b.affected[nodeID] = struct{}{}
+	}
+}
+
+// isAffected reports whether the node must be re-mixed in the current rebuild.
+func (b *incrementalBuild) isAffected(nodeID string) bool {
+	if b == nil || b.affected == nil {
+		return true
+	}
+	_, ok := b.affected[nodeID]
+	return ok
+}
+
+// fail records a node that kept its previous snapshot in the current rebuild.
+func (b *incrementalBuild) fail(nodeID string) {
+	if b == nil {
+		return
+	}
+	b.failed[nodeID] = struct{}{}
+}
+
+// isFailed reports whether the node kept its previous snapshot in the current rebuild.
+func (b *incrementalBuild) isFailed(nodeID string) bool {
+	_, ok := b.failed[nodeID]
+	return ok
+}
+
+// done finishes the current rebuild. Nodes that failed, or all nodes of a rebuild
+// that did not commit, are re-mixed by the next one.
+func (b *incrementalBuild) done(committed bool) {
+	if !committed {
+		if b.affected == nil {
+			b.full = true
+		}
+		for nodeID := range b.affected {
+			b.pending[nodeID] = struct{}{}
+		}
+	}
+	for nodeID := range b.failed {
+		b.pending[nodeID] = struct{}{}
+	}
+	b.affected = nil
+	b.failed = nil
+}
+
+// invalidateDependents marks VirtualServices built from any of the given objects
+// as requiring a rebuild. Must be called with c.mx held.
+func (c *CacheUpdater) invalidateDependents(deps ...store.Dependency) {
+	c.incremental.invalidate(c.store.GetDependentVirtualServices(deps...)...)
+}
+
+// clusterDependencies returns the dependencies matching a Cluster, both by its
+// name and by the Envoy cluster name of its spec.
+func clusterDependencies(clusters ...*v1alpha1.Cluster) []store.Dependency {
+	var deps []store.Dependency
+	for _, cl := range clusters {
+		if cl == nil {
+			continue
+		}
+		deps = append(deps, dependency(store.DependencyCluster, cl.Namespace, cl.Name))
+		if clv3, err := cl.UnmarshalV3(); err == nil {
+			deps = append(deps, dependency(store.DependencySpecCluster, "", clv3.Name))
+		}
+	}
+	return deps
+}
+
+func dependency(kind store.DependencyKind, namespace, name string) store.Dependency {
+	return store.Dependency{Kind: kind, Name: helpers.NamespacedName{Namespace: namespace, Name: name}}
+}
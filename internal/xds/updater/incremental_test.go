@@ -0,0 +1,92 @@
+package updater
+
+import (
+	"context"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TestIncrementalRebuild_OnlyAffectedNodes verifies that a change of an object used by
+// one VirtualService rebuilds only that VirtualService and leaves snapshots of other nodes untouched.
+func TestIncrementalRebuild_OnlyAffectedNodes(t *testing.T) {
+	ctx := context.Background()
+	routeNN := helpers.NamespacedName{Namespace: "ns", Name: "route-a"}
+
+	builds := make(map[string]int)
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, st store.Store) (*resbuilder.Resources, error) {
+		builds[vs.Name]++
+		res := &resbuilder.Resources{
+			Listener:    helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain: []*listenerv3.FilterChain{{Name: vs.Name}},
+			Clusters:    []*clusterv3.Cluster{{Name: "cluster-" + vs.Name}},
+			Domains:     []string{vs.Name + ".example.com"},
+		}
+		if vs.Name == "vs-a" && st.GetRoute(routeNN) != nil {
+			res.Clusters = append(res.Clusters, &clusterv3.Cluster{Name: "cluster-route-a"})
+		}
+		return res, nil
+	})()
+
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-a", []string{"node-a"}, "http"))
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-b", []string{"node-b"}, "http"))
+	if err := cu.RebuildSnapshots(ctx); err != nil {
+		t.Fatalf("rebuild failed: %v", err)
+	}
+
+	versionsBefore, err := cu.snapshotCache.GetVersions("node-b")
+	if err != nil {
+		t.Fatalf("no snapshot for node-b: %v", err)
+	}
+	snapshotBefore, _ := cu.snapshotCache.GetSnapshot("node-b")
+	buildsA, buildsB := builds["vs-a"], builds["vs-b"]
+
+	cu.ApplyRoute(ctx, &v1alpha1.Route{ObjectMeta: metav1.ObjectMeta{Namespace: routeNN.Namespace, Name: routeNN.Name}})
+
+	if builds["vs-a"] != buildsA+1 {
+		t.Errorf("expected vs-a to be rebuilt once, got %d builds", builds["vs-a"]-buildsA)
+	}
+	if builds["vs-b"] != buildsB {
+		t.Errorf("expected vs-b not to be rebuilt, got %d builds", builds["vs-b"]-buildsB)
+	}
+
+	clusters, err := cu.snapshotCache.GetClusters("node-a")
+	if err != nil || len(clusters) != 2 {
+		t.Errorf("expected node-a to get the route cluster, got %v (err %v)", clusters, err)
+	}
+	snapshotAfter, _ := cu.snapshotCache.GetSnapshot("node-b")
+	if snapshotAfter != snapshotBefore {
+		t.Errorf("expected node-b snapshot to be reused")
+	}
+	versionsAfter, _ := cu.snapshotCache.GetVersions("node-b")
+	for typ, version := range versionsBefore {
+		if versionsAfter[typ] != version {
+			t.Errorf("expected %s version of node-b to stay %s, got %s", typ, version, versionsAfter[typ])
+		}
+	}
+
+	// Deleting a VirtualService clears the snapshot of its node only
+	if err := cu.DeleteVirtualService(ctx, types.NamespacedName{Namespace: "ns", Name: "vs-a"}); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if clusters, _ := cu.snapshotCache.GetClusters("node-a"); len(clusters) != 0 {
+		t.Errorf("expected node-a snapshot to be cleared, got %v", clusters)
+	}
+	if snapshot, _ := cu.snapshotCache.GetSnapshot("node-b"); snapshot != snapshotBefore {
+		t.Errorf("expected node-b snapshot to be reused after delete")
+	}
+	if owners := cu.ResolveRejectedOwners("node-b", resource.ClusterType, ""); len(owners) != 1 {
+		t.Errorf("expected owners of node-b to be kept, got %v", owners)
+	}
+}
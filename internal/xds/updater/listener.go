@@ -5,6 +5,7 @@ import (
 
 	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -14,14 +15,16 @@ func (c *CacheUpdater) ApplyListener(ctx context.Context, listener *v1alpha1.Lis
 	prevListener := c.store.GetListener(helpers.NamespacedName{Namespace: listener.Namespace, Name: listener.Name})
 	if prevListener == nil {
 		c.store.SetListener(listener)
-		_ = c.rebuildSnapshots(ctx)
+		c.invalidateDependents(dependency(store.DependencyListener, listener.Namespace, listener.Name))
+		_ = c.requestRebuild(ctx)
 		return
 	}
 	if prevListener.IsEqual(listener) {
 		return
 	}
 	c.store.SetListener(listener)
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencyListener, listener.Namespace, listener.Name))
+	_ = c.requestRebuild(ctx)
 }
 
 func (c *CacheUpdater) DeleteListener(ctx context.Context, nn types.NamespacedName) {
@@ -31,5 +34,6 @@ func (c *CacheUpdater) DeleteListener(ctx context.Context, nn types.NamespacedNa
 		return
 	}
 	c.store.DeleteListener(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencyListener, nn.Namespace, nn.Name))
+	_ = c.requestRebuild(ctx)
 }
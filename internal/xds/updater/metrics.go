@@ -0,0 +1,91 @@
+package updater
+
+import (
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/prometheus/client_golang/prometheus"
+	ctrmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	rebuildQueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "exc",
+			Subsystem: "updater",
+			Name:      "rebuild_queue_depth",
+			Help:      "Number of changes waiting for the next coalesced snapshot rebuild.",
+		},
+	)
+
+	rebuildCoalescedEvents = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "exc",
+			Subsystem: "updater",
+			Name:      "rebuild_coalesced_events_total",
+			Help:      "Total number of changes merged into an already pending snapshot rebuild.",
+		},
+	)
+
+	rebuildsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "exc",
+			Subsystem: "updater",
+			Name:      "coalesced_rebuilds_total",
+			Help:      "Total number of coalesced snapshot rebuilds.",
+		},
+		[]string{"reason"}, // reason: window|max_latency
+	)
+
+	unmatchedNodes = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "exc",
+			Subsystem: "updater",
+			Name:      "unmatched_nodes",
+			Help:      "Number of connected nodes no VirtualService selects, served the default node configuration or an empty snapshot.",
+		},
+	)
+
+	nodeSnapshotHealthy = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "exc",
+			Subsystem: "updater",
+			Name:      "node_snapshot_healthy",
+			Help:      "Whether the latest snapshot update of the node succeeded (1) or the node kept its previous snapshot (0).",
+		},
+		[]string{"node_id"},
+	)
+
+	nodeSnapshotConsecutiveFailures = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "exc",
+			Subsystem: "updater",
+			Name:      "node_snapshot_consecutive_failures",
+			Help:      "Number of consecutive failed snapshot updates of the node.",
+		},
+		[]string{"node_id"},
+	)
+)
+
+func init() {
+	ctrmetrics.Registry.MustRegister(
+		rebuildQueueDepth,
+		rebuildCoalescedEvents,
+		rebuildsTotal,
+		unmatchedNodes,
+		nodeSnapshotHealthy,
+		nodeSnapshotConsecutiveFailures,
+	)
+}
+
+// updateNodeHealthMetrics replaces per-node health metrics, so removed nodes disappear.
+func updateNodeHealthMetrics(health []wrapped.NodeHealth) {
+	nodeSnapshotHealthy.Reset()
+	nodeSnapshotConsecutiveFailures.Reset()
+	for _, h := range health {
+		healthy := 0.0
+		if h.Healthy {
+			healthy = 1
+		}
+		nodeSnapshotHealthy.WithLabelValues(h.NodeID).Set(healthy)
+		nodeSnapshotConsecutiveFailures.WithLabelValues(h.NodeID).Set(float64(h.ConsecutiveFailures))
+	}
+}
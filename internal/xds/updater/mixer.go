@@ -2,29 +2,83 @@ package updater
 
 import (
 	"sort"
+	"strings"
 
 	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	runtimev3 "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
 	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/filters"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/secrets"
 )
 
 type Mixer struct {
 	listeners map[helpers.NamespacedName]map[string][]*listenerv3.FilterChain
 	data      map[string]map[resource.Type][]types.Resource
 	nodeIDs   map[string]struct{}
+	owners    resourceOwners
+	// extensionConfigs are resolved per node after mixing, see filters.ExtensionConfigBuilder
+	extensionConfigs map[string][]filters.ExtensionConfigRef
 }
 
 func NewMixer() *Mixer {
 	return &Mixer{
-		data:      make(map[string]map[resource.Type][]types.Resource),
-		listeners: make(map[helpers.NamespacedName]map[string][]*listenerv3.FilterChain),
-		nodeIDs:   make(map[string]struct{}),
+		data:             make(map[string]map[resource.Type][]types.Resource),
+		listeners:        make(map[helpers.NamespacedName]map[string][]*listenerv3.FilterChain),
+		nodeIDs:          make(map[string]struct{}),
+		owners:           make(resourceOwners),
+		extensionConfigs: make(map[string][]filters.ExtensionConfigRef),
 	}
 }
 
+// AddVirtualServiceResources adds resources built for a VirtualService to the node
+// and records the VirtualService as their owner.
+func (m *Mixer) AddVirtualServiceResources(nodeID string, vsNN helpers.NamespacedName, vsRes *resbuilder.Resources) {
+	if vsRes.RouteConfig != nil {
+		m.Add(nodeID, resource.RouteType, withNodeRateLimits(vsRes.RouteConfig, nodeID))
+		m.owners.add(nodeID, resource.RouteType, vsRes.RouteConfig.GetName(), vsNN)
+	}
+	for _, scope := range vsRes.ScopedRoutes {
+		m.Add(nodeID, resource.ScopedRouteType, scope)
+		m.owners.add(nodeID, resource.ScopedRouteType, scope.GetName(), vsNN)
+	}
+	for _, cl := range vsRes.Clusters {
+		m.Add(nodeID, resource.ClusterType, cl)
+		m.owners.add(nodeID, resource.ClusterType, cl.GetName(), vsNN)
+	}
+	for _, secret := range vsRes.Secrets {
+		m.Add(nodeID, resource.SecretType, secret)
+		m.owners.add(nodeID, resource.SecretType, secret.GetName(), vsNN)
+	}
+	for _, ref := range vsRes.ExtensionConfigs {
+		m.extensionConfigs[nodeID] = append(m.extensionConfigs[nodeID], ref)
+		m.owners.add(nodeID, resource.ExtensionConfigType, ref.Name, vsNN)
+	}
+	m.AddListenerParams(vsRes.Listener, vsRes.FilterChain, nodeID)
+	m.owners.add(nodeID, resource.ListenerType, vsRes.Listener.String(), vsNN)
+}
+
+// AddNode adds a node to be mixed even without resources, so it is served an empty snapshot.
+func (m *Mixer) AddNode(nodeID string) {
+	m.nodeIDs[nodeID] = struct{}{}
+}
+
+// AddRuntime adds a runtime layer to the node unless it has a layer of the same name already.
+// It reports whether the layer was added.
+func (m *Mixer) AddRuntime(nodeID string, layer *runtimev3.Runtime) bool {
+	for _, res := range m.data[nodeID][resource.RuntimeType] {
+		if cachev3.GetResourceName(res) == layer.GetName() {
+			return false
+		}
+	}
+	m.Add(nodeID, resource.RuntimeType, layer)
+	return true
+}
+
 func (m *Mixer) Add(nodeID string, resourceType resource.Type, resource types.Resource) {
 	if resources, ok := m.data[nodeID]; ok {
 		resources[resourceType] = append(resources[resourceType], resource)
@@ -48,15 +102,18 @@ func (m *Mixer) AddListenerParams(
 	m.nodeIDs[nodeID] = struct{}{}
 }
 
-func (m *Mixer) Mix(store store.Store) (map[string]map[resource.Type][]types.Resource, error) {
+// Mix returns the resources of each node. Nodes whose resources cannot be delivered to them
+// are left out and returned with their errors, the error fails all nodes.
+func (m *Mixer) Mix(store store.Store) (map[string]map[resource.Type][]types.Resource, map[string]error, error) {
 	result := make(map[string]map[resource.Type][]types.Resource)
+	nodeErrs := make(map[string]error)
 
 	for listenerNamespacedName, data := range m.listeners {
 		listener := store.GetListener(listenerNamespacedName)
 		for nodeID, fcs := range data {
 			lv3, err := listener.UnmarshalV3()
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			// Sort filter chains by name to ensure deterministic order.
 			// This prevents spurious snapshot version increments caused by
@@ -75,12 +132,30 @@ func (m *Mixer) Mix(store store.Store) (map[string]map[resource.Type][]types.Res
 	}
 
 	for nodeID, resources := range m.data {
+		// Secrets reaching a node they are not allowed for are rejected before mixing,
+		// this guards against delivering a private key to an unauthorised node
+		if err := checkSecretResourcesAccess(store, resources[resource.SecretType], nodeID); err != nil {
+			nodeErrs[nodeID] = err
+			delete(result, nodeID)
+			continue
+		}
 		if result[nodeID] == nil {
 			result[nodeID] = make(map[resource.Type][]types.Resource)
 		}
 		result[nodeID][resource.SecretType] = resources[resource.SecretType]
 		result[nodeID][resource.ClusterType] = resources[resource.ClusterType]
 		result[nodeID][resource.RouteType] = resources[resource.RouteType]
+		result[nodeID][resource.ScopedRouteType] = resources[resource.ScopedRouteType]
+		result[nodeID][resource.RuntimeType] = resources[resource.RuntimeType]
+	}
+
+	for nodeID := range m.nodeIDs {
+		if _, failed := nodeErrs[nodeID]; failed {
+			continue
+		}
+		if result[nodeID] == nil {
+			result[nodeID] = make(map[resource.Type][]types.Resource)
+		}
 	}
 
 	// Sort all resources by name to ensure deterministic order.
@@ -91,9 +166,11 @@ func (m *Mixer) Mix(store store.Store) (map[string]map[resource.Type][]types.Res
 		sortResources(result[nodeID][resource.ClusterType])
 		sortResources(result[nodeID][resource.SecretType])
 		sortResources(result[nodeID][resource.RouteType])
+		sortResources(result[nodeID][resource.ScopedRouteType])
+		sortResources(result[nodeID][resource.RuntimeType])
 	}
 
-	return result, nil
+	return result, nodeErrs, nil
 }
 
 // sortFilterChains sorts filter chains by name for deterministic ordering.
@@ -122,3 +199,28 @@ func sortResources(resources []types.Resource) {
 		return cachev3.GetResourceName(resources[i]) < cachev3.GetResourceName(resources[j])
 	})
 }
+
+// checkSecretAccess verifies that the secrets of a VirtualService of the access group
+// may be delivered to the nodes.
+func checkSecretAccess(st store.Store, secretNames []helpers.NamespacedName, accessGroup string, nodeIDs []string) error {
+	for _, secretName := range secretNames {
+		if err := secrets.CheckAccess(st.GetSecret(secretName), accessGroup, nodeIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkSecretResourcesAccess(st store.Store, resources []types.Resource, nodeID string) error {
+	for _, res := range resources {
+		namespace, name, ok := strings.Cut(cachev3.GetResourceName(res), "/")
+		if !ok {
+			continue
+		}
+		secret := st.GetSecret(helpers.NamespacedName{Namespace: namespace, Name: name})
+		if err := secrets.CheckAccess(secret, "", []string{nodeID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package updater
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"golang.org/x/exp/maps"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// nodeLabels maps node IDs of connected Envoys to the labels node selectors are matched against.
+type nodeLabels map[string]labels.Set
+
+// connectedNodes describes the nodes of connected Envoys a rebuild serves.
+type connectedNodes struct {
+	labels nodeLabels
+	// defaultNodeID is the node whose VirtualServices are served on connected nodes
+	// no VirtualService selects; without it they are served only common VirtualServices.
+	defaultNodeID string
+}
+
+// resolveNodeIDs returns the node IDs of the VirtualService: its node IDs followed by the
+// connected nodes matching its node selector. Node IDs of common VirtualServices are not expanded.
+func (n nodeLabels) resolveNodeIDs(vs *v1alpha1.VirtualService) ([]string, error) {
+	nodeIDs := vs.GetNodeIDs()
+	if isCommonVirtualService(nodeIDs) {
+		return nodeIDs, nil
+	}
+	selector, err := vs.GetNodeSelector()
+	if err != nil || selector == nil {
+		return nodeIDs, err
+	}
+	known := make(map[string]struct{}, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		known[nodeID] = struct{}{}
+	}
+	var selected []string
+	for nodeID, set := range n {
+		if _, ok := known[nodeID]; !ok && selector.Matches(set) {
+			selected = append(selected, nodeID)
+		}
+	}
+	sort.Strings(selected)
+	return append(nodeIDs, selected...), nil
+}
+
+func hasNodeSelector(vs *v1alpha1.VirtualService) bool {
+	return vs.GetAnnotations()[v1alpha1.AnnotationNodeSelector] != ""
+}
+
+// SetDefaultNodeID sets the node whose VirtualServices are served on connected nodes no
+// VirtualService selects by node ID or node selector. Empty serves them an empty snapshot
+// with only common VirtualServices.
+func (c *CacheUpdater) SetDefaultNodeID(nodeID string) {
+	c.nodesMx.Lock()
+	defer c.nodesMx.Unlock()
+	c.defaultNodeID = nodeID
+}
+
+// ApplyNode records the labels of a node an Envoy connected with. VirtualServices whose node
+// selector matches it, and common ones, are delivered to the node, creating its snapshot if it
+// has none yet. It does not wait for the rebuild, so it can be called from xDS stream callbacks.
+func (c *CacheUpdater) ApplyNode(ctx context.Context, nodeID string, set map[string]string) {
+	c.nodesMx.Lock()
+	if prev, ok := c.nodes[nodeID]; ok && labels.Equals(prev, set) {
+		c.nodesMx.Unlock()
+		return
+	}
+	if c.nodes == nil {
+		c.nodes = make(nodeLabels)
+	}
+	c.nodes[nodeID] = set
+	c.nodesMx.Unlock()
+
+	go c.rebuildSelectedNodes(ctx, nodeID, false)
+}
+
+// DeleteNode forgets a node whose last Envoy disconnected. Nodes it was selected for only
+// by node selectors are dropped from the snapshot cache, rather than served an empty snapshot.
+func (c *CacheUpdater) DeleteNode(ctx context.Context, nodeID string) {
+	c.nodesMx.Lock()
+	if _, ok := c.nodes[nodeID]; !ok {
+		c.nodesMx.Unlock()
+		return
+	}
+	delete(c.nodes, nodeID)
+	c.nodesMx.Unlock()
+
+	go c.rebuildSelectedNodes(ctx, nodeID, true)
+}
+
+// rebuildSelectedNodes requests a rebuild after a node connected or was released, if any
+// VirtualService selects nodes by labels or the node is not listed by node ID: a new node
+// is served common VirtualServices, and a released one is dropped from the snapshot cache.
+func (c *CacheUpdater) rebuildSelectedNodes(ctx context.Context, nodeID string, released bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if released {
+		if c.releasedNodes == nil {
+			c.releasedNodes = make(map[string]struct{})
+		}
+		c.releasedNodes[nodeID] = struct{}{}
+	}
+	_, isListed := c.listedNodeIDs()[nodeID]
+	if !isListed {
+		c.incremental.touch(nodeID)
+		_ = c.requestRebuild(ctx)
+		return
+	}
+	for _, vs := range c.store.MapVirtualServices() {
+		if hasNodeSelector(vs) {
+			_ = c.requestRebuild(ctx)
+			return
+		}
+	}
+}
+
+// getConnectedNodes returns the connected nodes. Labels are replaced, not modified,
+// so the returned map can be read without holding c.nodesMx.
+func (c *CacheUpdater) getConnectedNodes() connectedNodes {
+	c.nodesMx.RLock()
+	defer c.nodesMx.RUnlock()
+	return connectedNodes{labels: maps.Clone(c.nodes), defaultNodeID: c.defaultNodeID}
+}
+
+// listedNodeIDs returns node IDs VirtualServices list explicitly. Must be called with c.mx held.
+func (c *CacheUpdater) listedNodeIDs() map[string]struct{} {
+	listed := make(map[string]struct{})
+	for _, vs := range c.store.MapVirtualServices() {
+		for _, nodeID := range vs.GetNodeIDs() {
+			listed[nodeID] = struct{}{}
+		}
+	}
+	return listed
+}
+
+// clearReleasedNodes drops snapshots of disconnected nodes no VirtualService lists by node ID.
+// A reconnecting Envoy waits for its snapshot to be rebuilt instead of receiving an empty one.
+// Must be called with c.mx held.
+func (c *CacheUpdater) clearReleasedNodes(nodes nodeLabels) {
+	if len(c.releasedNodes) == 0 {
+		return
+	}
+	listed := c.listedNodeIDs()
+	for nodeID := range c.releasedNodes {
+		_, isListed := listed[nodeID]
+		_, isConnected := nodes[nodeID]
+		if isListed || isConnected {
+			continue
+		}
+		c.snapshotCache.ClearSnapshot(nodeID)
+		c.incremental.touch(nodeID)
+	}
+	c.releasedNodes = nil
+}
+
+// reportUnmatchedNodes updates the unmatched nodes metric and warns about nodes that became
+// unmatched since the previous rebuild. Must be called with c.mx held.
+func (c *CacheUpdater) reportUnmatchedNodes(ctx context.Context, unmatched []string, defaultNodeID string) {
+	rlog := log.FromContext(ctx).WithName("cache-updater")
+	current := make(map[string]struct{}, len(unmatched))
+	for _, nodeID := range unmatched {
+		current[nodeID] = struct{}{}
+		if _, ok := c.unmatchedNodes[nodeID]; ok {
+			continue
+		}
+		if defaultNodeID != "" {
+			rlog.Info("[WARN] no VirtualService selects the node, serving the default node configuration",
+				"nodeID", nodeID, "defaultNodeID", defaultNodeID)
+		} else {
+			rlog.Info("[WARN] no VirtualService selects the node, serving an empty snapshot", "nodeID", nodeID)
+		}
+	}
+	c.unmatchedNodes = current
+	unmatchedNodes.Set(float64(len(current)))
+}
@@ -0,0 +1,140 @@
+package updater
+
+import (
+	"context"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+)
+
+// connectNode and disconnectNode mirror ApplyNode and DeleteNode, waiting for the rebuild
+func connectNode(ctx context.Context, cu *CacheUpdater, nodeID string, set map[string]string) {
+	cu.nodesMx.Lock()
+	if cu.nodes == nil {
+		cu.nodes = make(nodeLabels)
+	}
+	cu.nodes[nodeID] = set
+	cu.nodesMx.Unlock()
+	cu.rebuildSelectedNodes(ctx, nodeID, false)
+}
+
+func disconnectNode(ctx context.Context, cu *CacheUpdater, nodeID string) {
+	cu.nodesMx.Lock()
+	delete(cu.nodes, nodeID)
+	cu.nodesMx.Unlock()
+	cu.rebuildSelectedNodes(ctx, nodeID, true)
+}
+
+// TestNodeSelector verifies that VirtualServices selecting nodes by labels are delivered to
+// matching nodes once they connect, and that snapshots of disconnected nodes are dropped.
+func TestNodeSelector(t *testing.T) {
+	ctx := context.Background()
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, _ store.Store) (*resbuilder.Resources, error) {
+		return &resbuilder.Resources{
+			Listener:    helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain: []*listenerv3.FilterChain{{Name: vs.Name}},
+			Clusters:    []*clusterv3.Cluster{{Name: "cluster-" + vs.Name}},
+			Domains:     []string{vs.Name + ".example.com"},
+		}, nil
+	})()
+
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	selected := makeVSWithListener("vs-selected", nil, "http")
+	selected.Annotations[v1alpha1.AnnotationNodeSelector] = "env=prod,region=eu"
+	cu.ApplyVirtualService(ctx, selected)
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-static", []string{"edge-static"}, "http"))
+
+	vsSelected := helpers.NamespacedName{Namespace: "ns", Name: "vs-selected"}
+	if vs := cu.GetVirtualServiceWithStatus(vsSelected); vs.Status.Invalid {
+		t.Fatalf("expected VirtualService without matching nodes to be valid, got %+v", vs.Status)
+	}
+
+	connectNode(ctx, cu, "edge-1", map[string]string{"env": "prod", "region": "eu"})
+	connectNode(ctx, cu, "edge-2", map[string]string{"env": "dev", "region": "eu"})
+	connectNode(ctx, cu, "edge-static", map[string]string{"env": "prod", "region": "eu"})
+	assertClusters(t, cu, "edge-1", "cluster-vs-selected")
+	assertClusters(t, cu, "edge-static", "cluster-vs-selected", "cluster-vs-static")
+	assertClusters(t, cu, "edge-2")
+
+	// Nodes listed by node ID keep their snapshots
+	disconnectNode(ctx, cu, "edge-1")
+	disconnectNode(ctx, cu, "edge-static")
+	if _, err := cu.snapshotCache.GetSnapshot("edge-1"); err == nil {
+		t.Errorf("expected the snapshot of a disconnected selected node to be dropped")
+	}
+	assertClusters(t, cu, "edge-static", "cluster-vs-static")
+
+	invalid := makeVSWithListener("vs-invalid", nil, "http")
+	invalid.Annotations[v1alpha1.AnnotationNodeSelector] = "env in prod"
+	cu.ApplyVirtualService(ctx, invalid)
+	if vs := cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: "vs-invalid"}); !vs.Status.Invalid {
+		t.Errorf("expected VirtualService with an invalid node selector to be invalid")
+	}
+}
+
+// TestUnmatchedNodes verifies that connected nodes no VirtualService selects are served common
+// VirtualServices and those of the default node, or an empty snapshot answering their requests.
+func TestUnmatchedNodes(t *testing.T) {
+	ctx := context.Background()
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, _ store.Store) (*resbuilder.Resources, error) {
+		return &resbuilder.Resources{
+			Listener:    helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain: []*listenerv3.FilterChain{{Name: vs.Name}},
+			Clusters:    []*clusterv3.Cluster{{Name: "cluster-" + vs.Name}},
+			Domains:     []string{vs.Name + ".example.com"},
+		}, nil
+	})()
+
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-default", []string{"default"}, "http"))
+
+	// Without a default node an unmatched node gets an empty, versioned snapshot
+	connectNode(ctx, cu, "edge-1", nil)
+	snapshot, err := cu.snapshotCache.GetSnapshot("edge-1")
+	if err != nil {
+		t.Fatalf("expected a snapshot for an unmatched node: %v", err)
+	}
+	if snapshot.GetVersion(resource.ClusterType) == "" {
+		t.Errorf("expected the empty snapshot to be versioned")
+	}
+	assertClusters(t, cu, "edge-1")
+	if _, ok := cu.unmatchedNodes["edge-1"]; !ok {
+		t.Errorf("expected edge-1 to be reported as unmatched")
+	}
+
+	// Common VirtualServices are served on all connected nodes
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-common", []string{"*"}, "http"))
+	assertClusters(t, cu, "edge-1", "cluster-vs-common")
+
+	// The default node configuration is served on unmatched nodes and follows its changes
+	cu.SetDefaultNodeID("default")
+	connectNode(ctx, cu, "edge-2", nil)
+	assertClusters(t, cu, "edge-2", "cluster-vs-common", "cluster-vs-default")
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-default-2", []string{"default"}, "http"))
+	assertClusters(t, cu, "edge-1", "cluster-vs-common", "cluster-vs-default", "cluster-vs-default-2")
+	assertClusters(t, cu, "edge-2", "cluster-vs-common", "cluster-vs-default", "cluster-vs-default-2")
+
+	// A node listed by a VirtualService is no longer served the default node configuration
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-edge", []string{"edge-2"}, "http"))
+	assertClusters(t, cu, "edge-2", "cluster-vs-common", "cluster-vs-edge")
+	if _, ok := cu.unmatchedNodes["edge-2"]; ok {
+		t.Errorf("expected edge-2 not to be reported as unmatched")
+	}
+
+	disconnectNode(ctx, cu, "edge-1")
+	if _, err := cu.snapshotCache.GetSnapshot("edge-1"); err == nil {
+		t.Errorf("expected the snapshot of a disconnected unmatched node to be dropped")
+	}
+	if len(cu.unmatchedNodes) != 0 {
+		t.Errorf("expected no unmatched nodes, got %v", cu.unmatchedNodes)
+	}
+}
@@ -0,0 +1,109 @@
+package updater
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+)
+
+// resourceOwners maps nodeID -> resource type -> resource name -> VirtualServices
+// that contributed the resource to the node snapshot.
+type resourceOwners map[string]map[resource.Type]map[string]map[helpers.NamespacedName]struct{}
+
+func (o resourceOwners) add(nodeID string, typeURL resource.Type, name string, owner helpers.NamespacedName) {
+	byType, ok := o[nodeID]
+	if !ok {
+		byType = make(map[resource.Type]map[string]map[helpers.NamespacedName]struct{})
+		o[nodeID] = byType
+	}
+	byName, ok := byType[typeURL]
+	if !ok {
+		byName = make(map[string]map[helpers.NamespacedName]struct{})
+		byType[typeURL] = byName
+	}
+	owners, ok := byName[name]
+	if !ok {
+		owners = make(map[helpers.NamespacedName]struct{})
+		byName[name] = owners
+	}
+	owners[owner] = struct{}{}
+}
+
+// resolve returns VirtualServices owning resources of the given type on the node.
+// Envoy error details reference the names of the rejected resources, so owners of
+// resources mentioned in the message are preferred. If none of the names match,
+// all owners of the resource type on the node are returned.
+func (o resourceOwners) resolve(nodeID string, typeURL resource.Type, message string) []helpers.NamespacedName {
+	byName := o[nodeID][typeURL]
+	if len(byName) == 0 {
+		return nil
+	}
+
+	matched := make(map[helpers.NamespacedName]struct{})
+	for name, owners := range byName {
+		if name == "" || !strings.Contains(message, name) {
+			continue
+		}
+		for owner := range owners {
+			matched[owner] = struct{}{}
+		}
+	}
+	if len(matched) == 0 {
+		for _, owners := range byName {
+			for owner := range owners {
+				matched[owner] = struct{}{}
+			}
+		}
+	}
+
+	result := make([]helpers.NamespacedName, 0, len(matched))
+	for owner := range matched {
+		result = append(result, owner)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].String() < result[j].String()
+	})
+	return result
+}
+
+// nodes returns the nodes each VirtualService contributed resources to.
+func (o resourceOwners) nodes() map[helpers.NamespacedName]map[string]struct{} {
+	result := make(map[helpers.NamespacedName]map[string]struct{})
+	for nodeID, byType := range o {
+		for _, byName := range byType {
+			for _, owners := range byName {
+				for owner := range owners {
+					nodeIDs, ok := result[owner]
+					if !ok {
+						nodeIDs = make(map[string]struct{})
+						result[owner] = nodeIDs
+					}
+					nodeIDs[nodeID] = struct{}{}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// virtualServices returns VirtualServices that contributed resources to the node snapshot.
+func (o resourceOwners) virtualServices(nodeID string) []helpers.NamespacedName {
+	matched := make(map[helpers.NamespacedName]struct{})
+	for _, byName := range o[nodeID] {
+		for _, owners := range byName {
+			for owner := range owners {
+				matched[owner] = struct{}{}
+			}
+		}
+	}
+	result := make([]helpers.NamespacedName, 0, len(matched))
+	for owner := range matched {
+		result = append(result, owner)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].String() < result[j].String()
+	})
+	return result
+}
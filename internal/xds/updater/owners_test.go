@@ -0,0 +1,40 @@
+package updater
+
+import (
+	"testing"
+
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+)
+
+// TestResourceOwnersResolve verifies that owners of resources named in the error
+// message are preferred, with a fallback to all owners of the resource type.
+func TestResourceOwnersResolve(t *testing.T) {
+	vsA := helpers.NamespacedName{Namespace: "default", Name: "vs-a"}
+	vsB := helpers.NamespacedName{Namespace: "default", Name: "vs-b"}
+
+	owners := make(resourceOwners)
+	owners.add(testNodeID, resource.ClusterType, "cluster-a", vsA)
+	owners.add(testNodeID, resource.ClusterType, "cluster-b", vsB)
+	owners.add(testNodeID, resource.ClusterType, "shared", vsA)
+	owners.add(testNodeID, resource.ClusterType, "shared", vsB)
+
+	got := owners.resolve(testNodeID, resource.ClusterType, "Error adding/updating cluster(s) cluster-b: bad")
+	if len(got) != 1 || got[0] != vsB {
+		t.Errorf("expected [%v], got %v", vsB, got)
+	}
+
+	got = owners.resolve(testNodeID, resource.ClusterType, "Error adding/updating cluster(s) shared: bad")
+	if len(got) != 2 || got[0] != vsA || got[1] != vsB {
+		t.Errorf("expected both owners of shared cluster, got %v", got)
+	}
+
+	got = owners.resolve(testNodeID, resource.ClusterType, "unrelated error")
+	if len(got) != 2 {
+		t.Errorf("expected fallback to all owners, got %v", got)
+	}
+
+	if got = owners.resolve("unknown", resource.ClusterType, "cluster-a"); len(got) != 0 {
+		t.Errorf("expected no owners for unknown node, got %v", got)
+	}
+}
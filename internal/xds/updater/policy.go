@@ -5,6 +5,7 @@ import (
 
 	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -14,14 +15,16 @@ func (c *CacheUpdater) ApplyPolicy(ctx context.Context, policy *v1alpha1.Policy)
 	prevPolicy := c.store.GetPolicy(helpers.NamespacedName{Namespace: policy.Namespace, Name: policy.Name})
 	if prevPolicy == nil {
 		c.store.SetPolicy(policy)
-		_ = c.rebuildSnapshots(ctx)
+		c.invalidateDependents(dependency(store.DependencyPolicy, policy.Namespace, policy.Name))
+		_ = c.requestRebuild(ctx)
 		return
 	}
 	if prevPolicy.IsEqual(policy) {
 		return
 	}
 	c.store.SetPolicy(policy)
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencyPolicy, policy.Namespace, policy.Name))
+	_ = c.requestRebuild(ctx)
 }
 
 func (c *CacheUpdater) DeletePolicy(ctx context.Context, nn types.NamespacedName) {
@@ -31,5 +34,6 @@ func (c *CacheUpdater) DeletePolicy(ctx context.Context, nn types.NamespacedName
 		return
 	}
 	c.store.DeletePolicy(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencyPolicy, nn.Namespace, nn.Name))
+	_ = c.requestRebuild(ctx)
 }
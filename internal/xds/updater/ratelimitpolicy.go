@@ -0,0 +1,97 @@
+package updater
+
+import (
+	"context"
+	"slices"
+
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RateLimitPolicies are rendered into routes and HTTP filters of the VirtualServices they
+// target: a change only rebuilds the VirtualServices it was and is targeting. Route
+// configurations are shared by the nodes of a VirtualService, the node is added to the
+// descriptors of their limits while mixing, see withNodeRateLimits.
+
+func (c *CacheUpdater) ApplyRateLimitPolicy(ctx context.Context, p *v1alpha1.RateLimitPolicy) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	prevPolicy := c.store.GetRateLimitPolicy(helpers.NamespacedName{Namespace: p.Namespace, Name: p.Name})
+	if prevPolicy.IsEqual(p) {
+		return
+	}
+	c.store.SetRateLimitPolicy(p)
+	c.invalidateDependents(rateLimitPolicyDependencies(prevPolicy, p)...)
+	_ = c.requestRebuild(ctx)
+}
+
+func (c *CacheUpdater) DeleteRateLimitPolicy(ctx context.Context, nn types.NamespacedName) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	prevPolicy := c.store.GetRateLimitPolicy(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
+	if prevPolicy == nil {
+		return
+	}
+	c.store.DeleteRateLimitPolicy(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
+	c.invalidateDependents(rateLimitPolicyDependencies(prevPolicy)...)
+	_ = c.requestRebuild(ctx)
+}
+
+// rateLimitPolicyDependencies returns the dependencies of VirtualServices targeted by the policies
+func rateLimitPolicyDependencies(policies ...*v1alpha1.RateLimitPolicy) []store.Dependency {
+	deps := make([]store.Dependency, 0, len(policies))
+	for _, p := range policies {
+		if p != nil {
+			vsNN := p.GetVirtualServiceNamespacedName()
+			deps = append(deps, dependency(store.DependencyRateLimitPolicy, vsNN.Namespace, vsNN.Name))
+		}
+	}
+	return deps
+}
+
+// withNodeRateLimits returns the route configuration with the node ID added to the descriptors
+// of the limits of RateLimitPolicies, right after their first entry, so that a rate limit
+// service counts requests per node. The configuration is cloned only if it has such limits.
+func withNodeRateLimits(routeConfig *routev3.RouteConfiguration, nodeID string) *routev3.RouteConfiguration {
+	if !hasPolicyRateLimits(routeConfig) {
+		return routeConfig
+	}
+	routeConfig = proto.Clone(routeConfig).(*routev3.RouteConfiguration)
+	nodeAction := &routev3.RateLimit_Action{
+		ActionSpecifier: &routev3.RateLimit_Action_GenericKey_{GenericKey: &routev3.RateLimit_Action_GenericKey{
+			DescriptorKey:   v1alpha1.RateLimitNodeDescriptorKey,
+			DescriptorValue: nodeID,
+		}},
+	}
+	for _, virtualHost := range routeConfig.GetVirtualHosts() {
+		for _, route := range virtualHost.GetRoutes() {
+			for _, rl := range route.GetRoute().GetRateLimits() {
+				if isPolicyRateLimit(rl) {
+					rl.Actions = slices.Insert(rl.Actions, 1, proto.Clone(nodeAction).(*routev3.RateLimit_Action))
+				}
+			}
+		}
+	}
+	return routeConfig
+}
+
+func hasPolicyRateLimits(routeConfig *routev3.RouteConfiguration) bool {
+	for _, virtualHost := range routeConfig.GetVirtualHosts() {
+		for _, route := range virtualHost.GetRoutes() {
+			if slices.ContainsFunc(route.GetRoute().GetRateLimits(), isPolicyRateLimit) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isPolicyRateLimit reports whether the rate limit is a limit of a RateLimitPolicy
+func isPolicyRateLimit(rl *routev3.RateLimit) bool {
+	actions := rl.GetActions()
+	return len(actions) > 0 && actions[0].GetGenericKey().GetDescriptorKey() == v1alpha1.RateLimitPolicyDescriptorKey
+}
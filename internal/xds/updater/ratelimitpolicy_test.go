@@ -0,0 +1,143 @@
+package updater
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/routes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TestRateLimitPolicy verifies that a RateLimitPolicy only rebuilds the VirtualService it
+// targets, and that an invalid policy invalidates it until deleted.
+func TestRateLimitPolicy(t *testing.T) {
+	ctx := context.Background()
+	var builds []string
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, st store.Store) (*resbuilder.Resources, error) {
+		builds = append(builds, vs.Name)
+		nn := helpers.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		virtualHost, err := routes.NewBuilder(st).BuildVirtualHost(vs, nn)
+		if err != nil {
+			return nil, err
+		}
+		return &resbuilder.Resources{
+			Listener:    helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain: []*listenerv3.FilterChain{{Name: vs.Name}},
+			Domains:     virtualHost.Domains,
+		}, nil
+	})()
+
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	cu.ApplyCluster(ctx, makeClusterCR("ns", "api-stable", "stable"))
+	cu.ApplyVirtualService(ctx, makeVSWithRoutes("vs-a", "a.example.com"))
+	cu.ApplyVirtualService(ctx, makeVSWithRoutes("vs-b", "b.example.com"))
+
+	policy := &v1alpha1.RateLimitPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "api"},
+		Spec: v1alpha1.RateLimitPolicySpec{
+			VirtualService: "vs-a",
+			Routes:         []string{"api"},
+			Service:        v1alpha1.RateLimitService{Cluster: v1alpha1.ResourceRef{Name: "api-stable"}},
+			Limits:         []v1alpha1.RateLimit{{Name: "global", Requests: 10, Unit: v1alpha1.RateLimitUnitSecond}},
+		},
+	}
+	builds = nil
+	cu.ApplyRateLimitPolicy(ctx, policy)
+	if len(builds) != 1 || builds[0] != "vs-a" {
+		t.Errorf("expected only vs-a to be rebuilt, got %v", builds)
+	}
+	vsA := cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: "vs-a"})
+	if vsA.Status.Invalid {
+		t.Fatalf("expected vs-a to be valid, got %q", vsA.Status.Message)
+	}
+
+	// Applying the same policy does not rebuild anything
+	builds = nil
+	cu.ApplyRateLimitPolicy(ctx, policy.DeepCopy())
+	if len(builds) != 0 {
+		t.Errorf("expected no rebuild for an unchanged policy, got %v", builds)
+	}
+
+	// A missing route invalidates the VirtualService
+	policy = policy.DeepCopy()
+	policy.Spec.Routes = []string{"missing"}
+	cu.ApplyRateLimitPolicy(ctx, policy)
+	vsA = cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: "vs-a"})
+	if !vsA.Status.Invalid {
+		t.Errorf("expected vs-a to be invalid with a missing route")
+	}
+
+	cu.DeleteRateLimitPolicy(ctx, types.NamespacedName{Namespace: "ns", Name: "api"})
+	vsA = cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: "vs-a"})
+	if vsA.Status.Invalid {
+		t.Errorf("expected vs-a to be valid once the RateLimitPolicy is deleted, got %q", vsA.Status.Message)
+	}
+}
+
+// TestWithNodeRateLimits verifies that the node is added to the descriptors of the limits of
+// RateLimitPolicies only, without modifying the shared route configuration.
+func TestWithNodeRateLimits(t *testing.T) {
+	genericKey := func(key, value string) *routev3.RateLimit_Action {
+		return &routev3.RateLimit_Action{
+			ActionSpecifier: &routev3.RateLimit_Action_GenericKey_{GenericKey: &routev3.RateLimit_Action_GenericKey{
+				DescriptorKey:   key,
+				DescriptorValue: value,
+			}},
+		}
+	}
+	routeConfig := &routev3.RouteConfiguration{
+		Name: "vs",
+		VirtualHosts: []*routev3.VirtualHost{{
+			Name: "vs",
+			Routes: []*routev3.Route{{
+				Name: "api",
+				Action: &routev3.Route_Route{Route: &routev3.RouteAction{
+					RateLimits: []*routev3.RateLimit{
+						{Actions: []*routev3.RateLimit_Action{
+							genericKey(v1alpha1.RateLimitPolicyDescriptorKey, "ns/api/global"),
+							{ActionSpecifier: &routev3.RateLimit_Action_RemoteAddress_{
+								RemoteAddress: &routev3.RateLimit_Action_RemoteAddress{},
+							}},
+						}},
+						{Actions: []*routev3.RateLimit_Action{genericKey("custom", "value")}},
+					},
+				}},
+			}},
+		}},
+	}
+
+	if got := withNodeRateLimits(&routev3.RouteConfiguration{Name: "plain"}, "node-a"); got.Name != "plain" {
+		t.Errorf("expected a configuration without limits to be returned as is")
+	}
+
+	nodeConfig := withNodeRateLimits(routeConfig, "node-a")
+	rateLimits := nodeConfig.VirtualHosts[0].Routes[0].GetRoute().RateLimits
+	var keys []string
+	for _, action := range rateLimits[0].Actions {
+		if gk := action.GetGenericKey(); gk != nil {
+			keys = append(keys, gk.DescriptorKey+"="+gk.DescriptorValue)
+		} else {
+			keys = append(keys, "remote_address")
+		}
+	}
+	expected := []string{"rate_limit_policy=ns/api/global", "node=node-a", "remote_address"}
+	if strings.Join(keys, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected descriptor %v, got %v", expected, keys)
+	}
+	if len(rateLimits[1].Actions) != 1 {
+		t.Errorf("expected other rate limits to be kept as they are, got %v", rateLimits[1].Actions)
+	}
+	if len(routeConfig.VirtualHosts[0].Routes[0].GetRoute().RateLimits[0].Actions) != 2 {
+		t.Errorf("expected the shared route configuration not to be modified")
+	}
+}
@@ -0,0 +1,87 @@
+package updater
+
+import (
+	"sort"
+
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+)
+
+// SetOnReferencesChange registers a function called with objects VirtualServices started or
+// stopped being built from after a rebuild. Clusters referenced by the Envoy cluster name of
+// their specs are reported as Cluster objects, Secrets, TrafficSplits and RateLimitPolicies
+// are not reported. It must not block.
+func (c *CacheUpdater) SetOnReferencesChange(fn func([]store.Dependency)) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.onReferencesChange = fn
+}
+
+// notifyReferenceChanges reports objects whose referencing VirtualServices changed since
+// the previous rebuild. Must be called with c.mx held.
+func (c *CacheUpdater) notifyReferenceChanges() {
+	refs := c.incremental.takeReferences()
+	if c.onReferencesChange == nil || len(refs) == 0 {
+		return
+	}
+	if refs = c.resolveReferences(refs); len(refs) > 0 {
+		c.onReferencesChange(refs)
+	}
+}
+
+// GetReferences returns the objects the VirtualServices are built from by name, in the same
+// form as SetOnReferencesChange reports them. It does not take c.mx, so it may be called
+// by functions registered with SetOnStatusChange.
+func (c *CacheUpdater) GetReferences(vsNNs ...helpers.NamespacedName) []store.Dependency {
+	var deps []store.Dependency
+	for _, vsNN := range vsNNs {
+		deps = append(deps, c.store.GetVirtualServiceDependencies(vsNN)...)
+	}
+	return c.resolveReferences(deps)
+}
+
+// GetReferencingVirtualServices returns VirtualServices built from the object of the kind,
+// sorted by namespace and name. Clusters are referenced both by their names and by the Envoy
+// cluster names of their specs.
+func (c *CacheUpdater) GetReferencingVirtualServices(kind store.DependencyKind, nn helpers.NamespacedName) []helpers.NamespacedName {
+	deps := []store.Dependency{dependency(kind, nn.Namespace, nn.Name)}
+	if kind == store.DependencyCluster {
+		if cl := c.store.GetCluster(nn); cl != nil {
+			deps = clusterDependencies(cl)
+		}
+	}
+	return c.store.GetReferencingVirtualServices(deps...)
+}
+
+// resolveReferences maps dependencies to the objects they reference by name, sorted and
+// without duplicates. Dependencies on Secrets, on TrafficSplits and RateLimitPolicies, which
+// are named after the VirtualServices they target, and on all objects of a kind are dropped.
+func (c *CacheUpdater) resolveReferences(deps []store.Dependency) []store.Dependency {
+	set := make(map[store.Dependency]struct{}, len(deps))
+	for _, dep := range deps {
+		switch {
+		case dep.Name.Name == "", dep.Kind == store.DependencySecret:
+			continue
+		case dep.Kind == store.DependencyTrafficSplit, dep.Kind == store.DependencyRateLimitPolicy:
+			continue
+		case dep.Kind == store.DependencySpecCluster:
+			cl := c.store.GetSpecCluster(dep.Name.Name)
+			if cl == nil {
+				continue
+			}
+			dep = dependency(store.DependencyCluster, cl.Namespace, cl.Name)
+		}
+		set[dep] = struct{}{}
+	}
+	result := make([]store.Dependency, 0, len(set))
+	for dep := range set {
+		result = append(result, dep)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Name.String() < result[j].Name.String()
+	})
+	return result
+}
@@ -0,0 +1,114 @@
+package updater
+
+import (
+	"context"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TestReferences verifies that objects VirtualServices are built from list them, and that
+// objects VirtualServices start or stop being built from are reported after a rebuild.
+func TestReferences(t *testing.T) {
+	ctx := context.Background()
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, st store.Store) (*resbuilder.Resources, error) {
+		listenerNN := helpers.NamespacedName{Namespace: "ns", Name: vs.Spec.Listener.Name}
+		_ = st.GetListener(listenerNN)
+		_ = st.GetSpecCluster("backend")
+		_ = st.MapRoutes()
+		return &resbuilder.Resources{
+			Listener:    listenerNN,
+			FilterChain: []*listenerv3.FilterChain{{Name: vs.Name}},
+			Clusters:    []*clusterv3.Cluster{{Name: "cluster-" + vs.Name}},
+			Domains:     []string{vs.Name + ".example.com"},
+		}, nil
+	})()
+
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	var notified []store.Dependency
+	cu.SetOnReferencesChange(func(refs []store.Dependency) { notified = append(notified, refs...) })
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	cu.ApplyListener(ctx, makeListenerCR("ns", "https", "0.0.0.0", 8443))
+	cu.ApplyCluster(ctx, makeClusterCR("ns", "backend-cluster", "backend"))
+
+	httpNN := helpers.NamespacedName{Namespace: "ns", Name: "http"}
+	httpsNN := helpers.NamespacedName{Namespace: "ns", Name: "https"}
+	clusterNN := helpers.NamespacedName{Namespace: "ns", Name: "backend-cluster"}
+	vsA := helpers.NamespacedName{Namespace: "ns", Name: "vs-a"}
+
+	notified = nil
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-a", []string{testNodeID}, "http"))
+	expected := []store.Dependency{
+		{Kind: store.DependencyCluster, Name: clusterNN},
+		{Kind: store.DependencyListener, Name: httpNN},
+	}
+	if !equalDependencies(notified, expected) {
+		t.Errorf("expected %v to be notified, got %v", expected, notified)
+	}
+	if got := cu.GetReferencingVirtualServices(store.DependencyListener, httpNN); len(got) != 1 || got[0] != vsA {
+		t.Errorf("expected vs-a to reference listener http, got %v", got)
+	}
+	if got := cu.GetReferencingVirtualServices(store.DependencyCluster, clusterNN); len(got) != 1 || got[0] != vsA {
+		t.Errorf("expected vs-a to reference the cluster by its spec name, got %v", got)
+	}
+	if got := cu.GetReferencingVirtualServices(store.DependencyRoute, httpNN); len(got) != 0 {
+		t.Errorf("expected listing all routes not to reference any, got %v", got)
+	}
+	if got := cu.GetReferences(vsA); !equalDependencies(got, expected) {
+		t.Errorf("expected references %v, got %v", expected, got)
+	}
+
+	// Moving to another listener reports both listeners
+	notified = nil
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-a", []string{testNodeID}, "https"))
+	expected = []store.Dependency{
+		{Kind: store.DependencyListener, Name: httpNN},
+		{Kind: store.DependencyListener, Name: httpsNN},
+	}
+	if !equalDependencies(notified, expected) {
+		t.Errorf("expected %v to be notified, got %v", expected, notified)
+	}
+	if got := cu.GetReferencingVirtualServices(store.DependencyListener, httpNN); len(got) != 0 {
+		t.Errorf("expected listener http not to be referenced, got %v", got)
+	}
+
+	notified = nil
+	if err := cu.DeleteVirtualService(ctx, types.NamespacedName{Namespace: "ns", Name: "vs-a"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []store.Dependency{
+		{Kind: store.DependencyCluster, Name: clusterNN},
+		{Kind: store.DependencyListener, Name: httpsNN},
+	}
+	if !equalDependencies(notified, expected) {
+		t.Errorf("expected %v to be notified, got %v", expected, notified)
+	}
+}
+
+func makeClusterCR(ns, name, clusterName string) *v1alpha1.Cluster {
+	return &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Spec:       &runtime.RawExtension{Raw: []byte(`{"name":"` + clusterName + `","connect_timeout":"1s"}`)},
+	}
+}
+
+func equalDependencies(a, b []store.Dependency) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -14,14 +15,16 @@ func (c *CacheUpdater) ApplyRoute(ctx context.Context, route *v1alpha1.Route) {
 	prevRoute := c.store.GetRoute(helpers.NamespacedName{Namespace: route.Namespace, Name: route.Name})
 	if prevRoute == nil {
 		c.store.SetRoute(route)
-		_ = c.rebuildSnapshots(ctx)
+		c.invalidateDependents(dependency(store.DependencyRoute, route.Namespace, route.Name))
+		_ = c.requestRebuild(ctx)
 		return
 	}
 	if prevRoute.IsEqual(route) {
 		return
 	}
 	c.store.SetRoute(route)
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencyRoute, route.Namespace, route.Name))
+	_ = c.requestRebuild(ctx)
 }
 
 func (c *CacheUpdater) DeleteRoute(ctx context.Context, nn types.NamespacedName) {
@@ -31,5 +34,6 @@ func (c *CacheUpdater) DeleteRoute(ctx context.Context, nn types.NamespacedName)
 		return
 	}
 	c.store.DeleteRoute(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencyRoute, nn.Namespace, nn.Name))
+	_ = c.requestRebuild(ctx)
 }
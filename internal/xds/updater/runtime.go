@@ -0,0 +1,117 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	runtimev3 "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Runtimes are not used to build VirtualServices: a change of a Runtime only re-mixes
+// the nodes it was and is served to, bumping their RTDS version.
+
+func (c *CacheUpdater) ApplyRuntime(ctx context.Context, rt *v1alpha1.Runtime) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	prevRuntime := c.store.GetRuntime(helpers.NamespacedName{Namespace: rt.Namespace, Name: rt.Name})
+	if prevRuntime.IsEqual(rt) {
+		return
+	}
+	c.store.SetRuntime(rt)
+	c.touchRuntimeNodes(prevRuntime, rt)
+	_ = c.requestRebuild(ctx)
+}
+
+func (c *CacheUpdater) DeleteRuntime(ctx context.Context, nn types.NamespacedName) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	prevRuntime := c.store.GetRuntime(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
+	if prevRuntime == nil {
+		return
+	}
+	c.store.DeleteRuntime(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
+	c.touchRuntimeNodes(prevRuntime)
+	_ = c.requestRebuild(ctx)
+}
+
+// touchRuntimeNodes re-mixes the nodes the Runtimes are served to. Must be called with c.mx held.
+func (c *CacheUpdater) touchRuntimeNodes(rts ...*v1alpha1.Runtime) {
+	for _, rt := range rts {
+		if rt != nil {
+			c.incremental.touch(resolveTargetNodeIDs(rt.GetNodeIDs(), c.snapshotCache)...)
+		}
+	}
+}
+
+// runtimeLayer is the runtime layer of a Runtime and the nodes it is served to.
+type runtimeLayer struct {
+	nn      helpers.NamespacedName
+	nodeIDs []string
+	layer   *runtimev3.Runtime
+}
+
+// buildRuntimeLayers returns the layers of all Runtimes, oldest first, and errors of invalid ones.
+func buildRuntimeLayers(st store.Store) ([]runtimeLayer, []error) {
+	rts := make([]*v1alpha1.Runtime, 0)
+	for _, rt := range st.MapRuntimes() {
+		rts = append(rts, rt)
+	}
+	sort.Slice(rts, func(i, j int) bool {
+		ti, tj := rts[i].CreationTimestamp, rts[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		if rts[i].Namespace != rts[j].Namespace {
+			return rts[i].Namespace < rts[j].Namespace
+		}
+		return rts[i].Name < rts[j].Name
+	})
+
+	var errs []error
+	layers := make([]runtimeLayer, 0, len(rts))
+	for _, rt := range rts {
+		nn := helpers.NamespacedName{Namespace: rt.Namespace, Name: rt.Name}
+		layer, err := rt.UnmarshalV3AndValidate()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("runtime %s: %w", nn.String(), err))
+			continue
+		}
+		layers = append(layers, runtimeLayer{nn: nn, nodeIDs: rt.GetNodeIDs(), layer: layer})
+	}
+	return layers, errs
+}
+
+// mixRuntimeLayers adds the layers listing nodes to the affected ones, or common layers to all
+// mixed nodes. A node is served one layer per name: layers of nodes take precedence over common
+// ones, then older Runtimes over newer ones, and the skipped layers are returned as errors.
+func mixRuntimeLayers(mixer *Mixer, layers []runtimeLayer, inc *incrementalBuild, common bool) []error {
+	var errs []error
+	add := func(nodeID string, l runtimeLayer) {
+		if !mixer.AddRuntime(nodeID, l.layer) {
+			errs = append(errs, fmt.Errorf("runtime %s: layer %s is already served to node %s by another Runtime",
+				l.nn.String(), l.layer.GetName(), nodeID))
+		}
+	}
+	for _, l := range layers {
+		if isCommonVirtualService(l.nodeIDs) != common {
+			continue
+		}
+		if common {
+			for _, nodeID := range sortedNodeIDs(mixer.nodeIDs) {
+				add(nodeID, l)
+			}
+			continue
+		}
+		for _, nodeID := range l.nodeIDs {
+			if inc.isAffected(nodeID) {
+				add(nodeID, l)
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,114 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	runtimev3 "github.com/envoyproxy/go-control-plane/envoy/service/runtime/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func makeRuntime(name, nodeIDs, layerName, value string, created time.Time) *v1alpha1.Runtime {
+	return &v1alpha1.Runtime{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "ns",
+			Name:              name,
+			Annotations:       map[string]string{v1alpha1.AnnotationNodeIDs: nodeIDs},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: &runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"name":%q,"layer":{"feature.enabled":%q}}`, layerName, value))},
+	}
+}
+
+// assertRuntimes checks the runtime layers of the node as "name=feature.enabled" pairs.
+func assertRuntimes(t *testing.T, cu *CacheUpdater, nodeID string, expected ...string) {
+	t.Helper()
+	snapshot, err := cu.snapshotCache.GetSnapshot(nodeID)
+	if err != nil {
+		t.Fatalf("node %s: %v", nodeID, err)
+	}
+	var layers []string
+	for name, res := range snapshot.GetResources(resource.RuntimeType) {
+		value := res.(*runtimev3.Runtime).GetLayer().GetFields()["feature.enabled"].GetStringValue()
+		layers = append(layers, name+"="+value)
+	}
+	sort.Strings(layers)
+	sort.Strings(expected)
+	if strings.Join(layers, ",") != strings.Join(expected, ",") {
+		t.Errorf("node %s: expected runtime layers %v, got %v", nodeID, expected, layers)
+	}
+}
+
+// TestRuntimeLayers verifies that runtime layers are served to the nodes Runtimes list, that a
+// node gets one layer per name, and that a change of a Runtime bumps only the RTDS version.
+func TestRuntimeLayers(t *testing.T) {
+	ctx := context.Background()
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, _ store.Store) (*resbuilder.Resources, error) {
+		return &resbuilder.Resources{
+			Listener:    helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain: []*listenerv3.FilterChain{{Name: vs.Name}},
+			Clusters:    []*clusterv3.Cluster{{Name: "cluster-" + vs.Name}},
+			Domains:     []string{vs.Name + ".example.com"},
+		}, nil
+	})()
+
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-1", []string{"node-1"}, "http"))
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-2", []string{"node-2"}, "http"))
+
+	now := time.Now()
+	cu.ApplyRuntime(ctx, makeRuntime("flags", "node-1", "flags", "true", now))
+	cu.ApplyRuntime(ctx, makeRuntime("common", "*", "common", "true", now))
+	// Layers of nodes take precedence over common ones, and older Runtimes over newer ones
+	cu.ApplyRuntime(ctx, makeRuntime("common-flags", "*", "flags", "common", now))
+	cu.ApplyRuntime(ctx, makeRuntime("flags-newer", "node-1", "flags", "newer", now.Add(time.Second)))
+	assertRuntimes(t, cu, "node-1", "flags=true", "common=true")
+	assertRuntimes(t, cu, "node-2", "flags=common", "common=true")
+
+	clustersVersion := cu.snapshotCache.GetVersion("node-1", resource.ClusterType)
+	runtimesVersion := cu.snapshotCache.GetVersion("node-1", resource.RuntimeType)
+	cu.ApplyRuntime(ctx, makeRuntime("flags", "node-1", "flags", "false", now))
+	assertRuntimes(t, cu, "node-1", "flags=false", "common=true")
+	if cu.snapshotCache.GetVersion("node-1", resource.ClusterType) != clustersVersion {
+		t.Errorf("expected a runtime change to keep the clusters version")
+	}
+	if cu.snapshotCache.GetVersion("node-1", resource.RuntimeType) == runtimesVersion {
+		t.Errorf("expected a runtime change to bump the runtime version")
+	}
+
+	cu.DeleteRuntime(ctx, types.NamespacedName{Namespace: "ns", Name: "flags"})
+	assertRuntimes(t, cu, "node-1", "flags=newer", "common=true")
+	cu.DeleteRuntime(ctx, types.NamespacedName{Namespace: "ns", Name: "common"})
+	assertRuntimes(t, cu, "node-2", "flags=common")
+}
+
+// TestRuntimeOnlyNode verifies that a node only listed by a Runtime gets all resource types
+// versioned, so that its initial requests for listeners and clusters are answered.
+func TestRuntimeOnlyNode(t *testing.T) {
+	ctx := context.Background()
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	cu.ApplyRuntime(ctx, makeRuntime("flags", "node-runtime", "flags", "true", time.Now()))
+	connectNode(ctx, cu, "node-runtime", nil)
+	assertRuntimes(t, cu, "node-runtime", "flags=true")
+
+	for _, typ := range []string{resource.ListenerType, resource.ClusterType} {
+		if cu.snapshotCache.GetVersion("node-runtime", typ) == "" {
+			t.Errorf("expected %s to be versioned on a node only listed by a Runtime", typ)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package updater
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	rebuildReasonWindow     = "window"
+	rebuildReasonMaxLatency = "max_latency"
+)
+
+// rebuildScheduler coalesces rebuild requests. A rebuild runs once no new request
+// arrived within window, or when maxLatency passed since the first pending request.
+type rebuildScheduler struct {
+	window     time.Duration
+	maxLatency time.Duration
+	trigger    chan struct{}
+
+	mu      sync.Mutex
+	pending int
+}
+
+func newRebuildScheduler(window, maxLatency time.Duration) *rebuildScheduler {
+	if maxLatency < window {
+		maxLatency = window
+	}
+	return &rebuildScheduler{
+		window:     window,
+		maxLatency: maxLatency,
+		trigger:    make(chan struct{}, 1),
+	}
+}
+
+// enqueue marks the store dirty and wakes up the scheduler loop.
+func (s *rebuildScheduler) enqueue() {
+	s.mu.Lock()
+	s.pending++
+	pending := s.pending
+	s.mu.Unlock()
+	rebuildQueueDepth.Set(float64(pending))
+
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// take resets the pending counter and returns the number of coalesced requests.
+func (s *rebuildScheduler) take() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := s.pending
+	s.pending = 0
+	rebuildQueueDepth.Set(0)
+	return pending
+}
+
+// debounce blocks until the window or the max-latency bound elapses.
+// It returns an empty reason if the context was canceled.
+func (s *rebuildScheduler) debounce(ctx context.Context) string {
+	windowTimer := time.NewTimer(s.window)
+	defer windowTimer.Stop()
+	deadlineTimer := time.NewTimer(s.maxLatency)
+	defer deadlineTimer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ""
+		case <-s.trigger:
+			windowTimer.Reset(s.window)
+		case <-windowTimer.C:
+			return rebuildReasonWindow
+		case <-deadlineTimer.C:
+			return rebuildReasonMaxLatency
+		}
+	}
+}
+
+// StartRebuildScheduler switches the updater to coalesced rebuilds: Apply and Delete
+// methods only mark the store dirty, and snapshots are rebuilt in the background
+// once per window. VirtualServices whose status changed are reported through the
+// handler set with SetOnStatusChange. A zero window keeps synchronous rebuilds.
+func (c *CacheUpdater) StartRebuildScheduler(ctx context.Context, window, maxLatency time.Duration) {
+	if window <= 0 {
+		return
+	}
+	s := newRebuildScheduler(window, maxLatency)
+
+	c.mx.Lock()
+	c.scheduler = s
+	c.mx.Unlock()
+
+	rlog := log.FromContext(ctx).WithName("rebuild-scheduler")
+	rlog.Info("coalesced snapshot rebuilds enabled", "window", window.String(), "maxLatency", s.maxLatency.String())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.trigger:
+			}
+			reason := s.debounce(ctx)
+			if reason == "" {
+				return
+			}
+			events := s.take()
+			if events > 1 {
+				rebuildCoalescedEvents.Add(float64(events - 1))
+			}
+			rebuildsTotal.WithLabelValues(reason).Inc()
+			rlog.V(1).Info("running coalesced rebuild", "events", events, "reason", reason)
+
+			c.mx.Lock()
+			_ = c.rebuildSnapshots(ctx)
+			c.mx.Unlock()
+		}
+	}()
+}
+
+// SetOnStatusChange registers a function called with VirtualServices whose status
+// changed after a rebuild. It must not block.
+func (c *CacheUpdater) SetOnStatusChange(fn func([]helpers.NamespacedName)) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.onStatusChange = fn
+}
+
+// requestRebuild rebuilds snapshots synchronously or, if the rebuild scheduler is
+// running, schedules a coalesced rebuild. Must be called with c.mx held.
+func (c *CacheUpdater) requestRebuild(ctx context.Context) error {
+	if c.scheduler == nil {
+		return c.rebuildSnapshots(ctx)
+	}
+	c.scheduler.enqueue()
+	return nil
+}
+
+// notifyStatusChanges reports VirtualServices whose status differs from the previous rebuild.
+// Must be called with c.mx held.
+func (c *CacheUpdater) notifyStatusChanges(vsStatuses map[helpers.NamespacedName]VSStatus) {
+	var changed []helpers.NamespacedName
+	for vsNN, status := range vsStatuses {
+		if c.vsStatuses[vsNN] != status {
+			changed = append(changed, vsNN)
+		}
+	}
+	c.vsStatuses = vsStatuses
+	if c.onStatusChange != nil && len(changed) > 0 {
+		c.onStatusChange(changed)
+	}
+}
@@ -0,0 +1,161 @@
+package updater
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestRebuildScheduler_CoalescesApplies verifies that applies within the window are merged
+// into a single rebuild and that status changes are reported after it.
+func TestRebuildScheduler_CoalescesApplies(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updater := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+
+	var mu sync.Mutex
+	notified := make(map[helpers.NamespacedName]struct{})
+	done := make(chan struct{}, 1)
+	updater.SetOnStatusChange(func(nns []helpers.NamespacedName) {
+		mu.Lock()
+		for _, nn := range nns {
+			notified[nn] = struct{}{}
+		}
+		mu.Unlock()
+		done <- struct{}{}
+	})
+
+	rebuildsBefore := testutil.ToFloat64(rebuildsTotal.WithLabelValues(rebuildReasonWindow))
+	coalescedBefore := testutil.ToFloat64(rebuildCoalescedEvents)
+
+	updater.StartRebuildScheduler(ctx, 100*time.Millisecond, 5*time.Second)
+
+	// VirtualServices without a listener are invalid, so each gets a status change
+	for _, name := range []string{"vs-1", "vs-2", "vs-3"} {
+		updater.ApplyVirtualService(ctx, &v1alpha1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: map[string]string{v1alpha1.AnnotationNodeIDs: testNodeID},
+			},
+		})
+	}
+
+	// Statuses are not computed until the coalesced rebuild runs
+	if vs := updater.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "default", Name: "vs-1"}); vs.Status.Invalid {
+		t.Fatalf("expected status to be computed asynchronously")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for coalesced rebuild")
+	}
+
+	if got := testutil.ToFloat64(rebuildsTotal.WithLabelValues(rebuildReasonWindow)) - rebuildsBefore; got != 1 {
+		t.Errorf("expected 1 coalesced rebuild, got %v", got)
+	}
+	if got := testutil.ToFloat64(rebuildCoalescedEvents) - coalescedBefore; got != 2 {
+		t.Errorf("expected 2 coalesced events, got %v", got)
+	}
+	if got := testutil.ToFloat64(rebuildQueueDepth); got != 0 {
+		t.Errorf("expected empty queue after rebuild, got %v", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(notified) != 3 {
+		t.Errorf("expected 3 VirtualServices reported, got %v", notified)
+	}
+	for nn := range notified {
+		if vs := updater.GetVirtualServiceWithStatus(nn); vs == nil || !vs.Status.Invalid {
+			t.Errorf("expected %s to be invalid after rebuild", nn.String())
+		}
+	}
+}
+
+// TestRebuildScheduler_MaxLatency verifies that a steady stream of changes does not
+// postpone the rebuild beyond the max-latency bound.
+func TestRebuildScheduler_MaxLatency(t *testing.T) {
+	s := newRebuildScheduler(100*time.Millisecond, 300*time.Millisecond)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				s.enqueue()
+			}
+		}
+	}()
+
+	start := time.Now()
+	reason := s.debounce(context.Background())
+	if reason != rebuildReasonMaxLatency {
+		t.Errorf("expected rebuild reason %q, got %q", rebuildReasonMaxLatency, reason)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("rebuild was postponed for %s", elapsed)
+	}
+}
+
+// TestRebuildScheduler_StatusObservesBuiltGeneration verifies that the status keeps the generation
+// it was built from until the debounced rebuild builds the applied generation, and reports it then.
+func TestRebuildScheduler_StatusObservesBuiltGeneration(t *testing.T) {
+	ctx := context.Background()
+	updater := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	var notified []helpers.NamespacedName
+	updater.SetOnStatusChange(func(nns []helpers.NamespacedName) {
+		notified = append(notified, nns...)
+	})
+	nn := helpers.NamespacedName{Namespace: "default", Name: "vs"}
+	makeVS := func(generation int64) *v1alpha1.VirtualService {
+		return &v1alpha1.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        nn.Name,
+				Namespace:   nn.Namespace,
+				Generation:  generation,
+				Annotations: map[string]string{v1alpha1.AnnotationNodeIDs: testNodeID},
+			},
+		}
+	}
+
+	updater.ApplyVirtualService(ctx, makeVS(1))
+	if got := updater.GetVirtualServiceWithStatus(nn).Status.ObservedGeneration; got != 1 {
+		t.Fatalf("expected status of generation 1, got %d", got)
+	}
+
+	// The equal spec of a new generation is still rebuilt once the scheduler runs
+	updater.scheduler = newRebuildScheduler(time.Minute, time.Minute)
+	notified = nil
+	updater.ApplyVirtualService(ctx, makeVS(2))
+	if pending := updater.scheduler.take(); pending != 1 {
+		t.Fatalf("expected a rebuild for the new generation, got %d", pending)
+	}
+	if got := updater.GetVirtualServiceWithStatus(nn).Status.ObservedGeneration; got != 1 {
+		t.Errorf("expected status of generation 1 before the rebuild, got %d", got)
+	}
+
+	// The VirtualService has no listener, so the rebuild reports it invalid
+	_ = updater.RebuildSnapshots(ctx)
+	if got := updater.GetVirtualServiceWithStatus(nn).Status.ObservedGeneration; got != 2 {
+		t.Errorf("expected status of generation 2 after the rebuild, got %d", got)
+	}
+	if len(notified) != 1 || notified[0] != nn {
+		t.Errorf("expected the new generation to be reported, got %v", notified)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -16,14 +17,16 @@ func (c *CacheUpdater) ApplySecret(ctx context.Context, secret *v1.Secret) {
 	prevSecret := c.store.GetSecret(helpers.NamespacedName{Namespace: secret.Namespace, Name: secret.Name})
 	if prevSecret == nil {
 		c.store.SetSecret(secret)
-		_ = c.rebuildSnapshots(ctx)
+		c.invalidateDependents(dependency(store.DependencySecret, secret.Namespace, secret.Name))
+		_ = c.requestRebuild(ctx)
 		return
 	}
 	if secretsEqual(prevSecret, secret) {
 		return
 	}
 	c.store.SetSecret(secret)
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencySecret, secret.Namespace, secret.Name))
+	_ = c.requestRebuild(ctx)
 }
 
 func (c *CacheUpdater) DeleteSecret(ctx context.Context, nn types.NamespacedName) {
@@ -33,10 +36,22 @@ func (c *CacheUpdater) DeleteSecret(ctx context.Context, nn types.NamespacedName
 		return
 	}
 	c.store.DeleteSecret(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencySecret, nn.Namespace, nn.Name))
+	_ = c.requestRebuild(ctx)
 }
 
 func secretsEqual(a, b *v1.Secret) bool {
+	for _, annotation := range []string{
+		v1alpha1.AnnotationSecretDomains,
+		v1alpha1.AnnotationSecretAllowedNodeIDs,
+		v1alpha1.AnnotationSecretAllowedAccessGroups,
+	} {
+		valA, okA := a.Annotations[annotation]
+		valB, okB := b.Annotations[annotation]
+		if okA != okB || valA != valB {
+			return false
+		}
+	}
 	if a.Data == nil && b.Data == nil {
 		return true
 	}
@@ -54,10 +69,5 @@ func secretsEqual(a, b *v1.Secret) bool {
 			return false
 		}
 	}
-	valA, okA := a.Annotations[v1alpha1.AnnotationSecretDomains]
-	valB, okB := b.Annotations[v1alpha1.AnnotationSecretDomains]
-	if okA != okB || valA != valB {
-		return false
-	}
 	return true
 }
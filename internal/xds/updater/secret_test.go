@@ -0,0 +1,127 @@
+package updater
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/secrets"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestSecretDeliveryPolicy_RejectsVirtualServices verifies that VirtualServices using a secret
+// not allowed on their nodes are marked invalid and not served, without failing the nodes,
+// both for node-specific and common VirtualServices.
+func TestSecretDeliveryPolicy_RejectsVirtualServices(t *testing.T) {
+	ctx := context.Background()
+	secretNN := helpers.NamespacedName{Namespace: "certs", Name: "tls"}
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, st store.Store) (*resbuilder.Resources, error) {
+		res := &resbuilder.Resources{
+			Listener:    helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain: []*listenerv3.FilterChain{{Name: vs.Name}},
+			Clusters:    []*clusterv3.Cluster{{Name: "cluster-" + vs.Name}},
+			Domains:     []string{vs.Name + ".example.com"},
+		}
+		if strings.HasPrefix(vs.Name, "vs-tls") {
+			// the secrets builder checks node-specific VirtualServices
+			if !isCommonVirtualService(vs.GetNodeIDs()) {
+				if err := secrets.CheckAccess(st.GetSecret(secretNN), "", vs.GetNodeIDs()); err != nil {
+					return nil, err
+				}
+			}
+			res.Secrets = []*tlsv3.Secret{{Name: secretNN.String()}}
+			res.UsedSecrets = []helpers.NamespacedName{secretNN}
+		}
+		return res, nil
+	})()
+
+	makeSecret := func(allowedNodeIDs string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   secretNN.Namespace,
+				Name:        secretNN.Name,
+				Annotations: map[string]string{v1alpha1.AnnotationSecretAllowedNodeIDs: allowedNodeIDs},
+			},
+			Data: map[string][]byte{"tls.crt": []byte("crt"), "tls.key": []byte("key")},
+		}
+	}
+
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	cu.ApplySecret(ctx, makeSecret("node-a"))
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-a", []string{"node-a"}, "http"))
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-b", []string{"node-b"}, "http"))
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-tls-a", []string{"node-a"}, "http"))
+	if err := cu.RebuildSnapshots(ctx); err != nil {
+		t.Fatalf("rebuild failed: %v", err)
+	}
+	assertClusters(t, cu, "node-a", "cluster-vs-a", "cluster-vs-tls-a")
+
+	// A common VirtualService would deliver the secret to node-b as well
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-tls-common", []string{"*"}, "http"))
+	vsCommon := cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: "vs-tls-common"})
+	if !vsCommon.Status.Invalid || !strings.Contains(vsCommon.Status.Message, "not allowed for node node-b") {
+		t.Errorf("expected common VirtualService to be invalid for node-b, got %+v", vsCommon.Status)
+	}
+	assertClusters(t, cu, "node-b", "cluster-vs-b")
+
+	// Revoking node-a removes the secret from it, instead of keeping its previous snapshot
+	cu.ApplySecret(ctx, makeSecret("node-c"))
+	vsTLS := cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: "vs-tls-a"})
+	if !vsTLS.Status.Invalid || !strings.Contains(vsTLS.Status.Message, "not allowed for node node-a") {
+		t.Errorf("expected vs-tls-a to be invalid for node-a, got %+v", vsTLS.Status)
+	}
+	assertClusters(t, cu, "node-a", "cluster-vs-a")
+	if health, _ := cu.snapshotCache.GetNodeHealth("node-a"); !health.Healthy {
+		t.Errorf("expected node-a to stay healthy, got %+v", health)
+	}
+	if got, _ := cu.snapshotCache.GetSecrets("node-a"); len(got) != 0 {
+		t.Errorf("expected node-a to receive no secrets, got %v", got)
+	}
+}
+
+// TestMixer_SkipsNodesWithDisallowedSecrets verifies that a secret not allowed on a node
+// leaves out only that node, which is reported, while other nodes are still mixed.
+func TestMixer_SkipsNodesWithDisallowedSecrets(t *testing.T) {
+	st := store.New()
+	st.SetSecret(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "certs",
+			Name:        "tls",
+			Annotations: map[string]string{v1alpha1.AnnotationSecretAllowedNodeIDs: "node-a"},
+		},
+	})
+
+	mixer := NewMixer()
+	for _, nodeID := range []string{"node-a", "node-b"} {
+		mixer.Add(nodeID, resource.SecretType, &tlsv3.Secret{Name: "certs/tls"})
+		mixer.Add(nodeID, resource.ClusterType, &clusterv3.Cluster{Name: "cluster-" + nodeID})
+	}
+	mixer.Add("node-c", resource.ClusterType, &clusterv3.Cluster{Name: "cluster-node-c"})
+
+	result, nodeErrs, err := mixer.Mix(st)
+	if err != nil {
+		t.Fatalf("expected mixing to succeed, got %v", err)
+	}
+	if len(nodeErrs) != 1 || nodeErrs["node-b"] == nil {
+		t.Errorf("expected only node-b to fail, got %v", nodeErrs)
+	}
+	if _, ok := result["node-b"]; ok {
+		t.Errorf("expected node-b to be left out")
+	}
+	for _, nodeID := range []string{"node-a", "node-c"} {
+		if len(result[nodeID][resource.ClusterType]) != 1 {
+			t.Errorf("expected %s to be mixed, got %v", nodeID, result[nodeID])
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package updater
 
 import (
+	"context"
+	"maps"
 	"testing"
 
 	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
@@ -8,6 +10,11 @@ import (
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
 )
 
 const testNodeID = "test-node"
@@ -68,7 +75,7 @@ func TestMixerDeterministicOutput(t *testing.T) {
 		for _, idx := range order {
 			mixer.Add(testNodeID, resource.ClusterType, clusters[idx])
 		}
-		result, _ := mixer.Mix(nil)
+		result, _, _ := mixer.Mix(nil)
 		results = append(results, result[testNodeID][resource.ClusterType])
 	}
 
@@ -98,7 +105,7 @@ func TestMixerVersionStability(t *testing.T) {
 	for _, c := range clusters {
 		mixer.Add(testNodeID, resource.ClusterType, c)
 	}
-	result, _ := mixer.Mix(nil)
+	result, _, _ := mixer.Mix(nil)
 	snapshot, _ := cachev3.NewSnapshot("1", result[testNodeID])
 
 	// Reconcile with different add orders
@@ -111,7 +118,7 @@ func TestMixerVersionStability(t *testing.T) {
 		for _, idx := range order {
 			m.Add(testNodeID, resource.ClusterType, clusters[idx])
 		}
-		res, _ := m.Mix(nil)
+		res, _, _ := m.Mix(nil)
 
 		newSnapshot, hasChanges, _ := updateSnapshot(prevSnapshot, res[testNodeID])
 		if hasChanges {
@@ -125,6 +132,50 @@ func TestMixerVersionStability(t *testing.T) {
 	}
 }
 
+// TestSnapshotVersions_SurviveRestart verifies that a restarted controller serves the same
+// versions for the same resources, and that a change of one type changes only its version.
+func TestSnapshotVersions_SurviveRestart(t *testing.T) {
+	ctx := context.Background()
+	clusterName := "cluster"
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, _ store.Store) (*resbuilder.Resources, error) {
+		return &resbuilder.Resources{
+			Listener:    helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain: []*listenerv3.FilterChain{{Name: vs.Name}},
+			Clusters:    []*clusterv3.Cluster{{Name: clusterName}},
+			Domains:     []string{vs.Name + ".example.com"},
+		}, nil
+	})()
+
+	start := func() map[string]string {
+		t.Helper()
+		cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+		cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+		cu.ApplyVirtualService(ctx, makeVSWithListener("vs", []string{testNodeID}, "http"))
+		if err := cu.RebuildSnapshots(ctx); err != nil {
+			t.Fatalf("rebuild failed: %v", err)
+		}
+		versions, err := cu.snapshotCache.GetVersions(testNodeID)
+		if err != nil {
+			t.Fatalf("no snapshot: %v", err)
+		}
+		return versions
+	}
+
+	before := start()
+	if after := start(); !maps.Equal(before, after) {
+		t.Errorf("expected versions %v after restart, got %v", before, after)
+	}
+
+	clusterName = "cluster-v2"
+	changed := start()
+	if changed["clusters"] == before["clusters"] {
+		t.Errorf("expected clusters version to change")
+	}
+	if changed["listeners"] != before["listeners"] {
+		t.Errorf("expected listeners version %s to stay, got %s", before["listeners"], changed["listeners"])
+	}
+}
+
 // BenchmarkSortResources measures sorting performance.
 func BenchmarkSortResources(b *testing.B) {
 	clusters := make([]types.Resource, 100)
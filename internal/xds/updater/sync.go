@@ -0,0 +1,163 @@
+package updater
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"golang.org/x/exp/maps"
+)
+
+// AckSource reports the versions acknowledged by the proxies connected to this replica.
+type AckSource interface {
+	// AcknowledgedVersions returns, for every proxy of the node, its acknowledged versions per type URL.
+	AcknowledgedVersions(nodeID string) []map[string]string
+}
+
+// syncTarget is the snapshot generation of a node that first contained the current
+// build of a VirtualService. Proxies having acknowledged it have the VirtualService applied.
+type syncTarget struct {
+	build      *vsBuild
+	generation wrapped.Generation
+}
+
+// NodeSyncStatus describes how many proxies of a node acknowledged a VirtualService change.
+type NodeSyncStatus struct {
+	NodeID string
+	// Version of the node snapshot that first contained the current configuration of the VirtualService
+	Version      string
+	Acknowledged int
+	Total        int
+}
+
+// SyncStatus describes whether a VirtualService change has reached all its proxies.
+type SyncStatus struct {
+	// Nodes the VirtualService is served on, sorted by node ID
+	Nodes []NodeSyncStatus
+}
+
+// Proxies returns the number of proxies that acknowledged the change and the number of connected proxies.
+func (s SyncStatus) Proxies() (acknowledged, total int) {
+	for _, node := range s.Nodes {
+		acknowledged += node.Acknowledged
+		total += node.Total
+	}
+	return acknowledged, total
+}
+
+// Synced reports whether all connected proxies acknowledged the change. It is false without proxies.
+func (s SyncStatus) Synced() bool {
+	acknowledged, total := s.Proxies()
+	return total > 0 && acknowledged == total
+}
+
+// Message describes the status, e.g. "3/3 proxies acknowledged version 42".
+// Every node is named when the VirtualService is served on several nodes.
+func (s SyncStatus) Message() string {
+	if _, total := s.Proxies(); total == 0 {
+		return "no connected proxies serve the VirtualService"
+	}
+	messages := make([]string, 0, len(s.Nodes))
+	for _, node := range s.Nodes {
+		message := fmt.Sprintf("%d/%d proxies acknowledged version %s", node.Acknowledged, node.Total, node.Version)
+		if len(s.Nodes) > 1 {
+			message = fmt.Sprintf("node %s: %s", node.NodeID, message)
+		}
+		messages = append(messages, message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// SetAckSource sets the source of versions acknowledged by connected proxies used by GetSyncStatus.
+func (c *CacheUpdater) SetAckSource(src AckSource) {
+	c.ownersMx.Lock()
+	defer c.ownersMx.Unlock()
+	c.ackSource = src
+}
+
+// GetSyncStatus returns how many proxies of every node of the VirtualService acknowledged
+// a snapshot containing its current configuration.
+func (c *CacheUpdater) GetSyncStatus(nn helpers.NamespacedName) SyncStatus {
+	c.ownersMx.RLock()
+	targets := maps.Clone(c.syncTargets[nn])
+	src := c.ackSource
+	c.ownersMx.RUnlock()
+
+	status := SyncStatus{Nodes: make([]NodeSyncStatus, 0, len(targets))}
+	for nodeID, target := range targets {
+		node := NodeSyncStatus{NodeID: nodeID, Version: target.generation.Version}
+		if src != nil {
+			for _, acked := range src.AcknowledgedVersions(nodeID) {
+				node.Total++
+				if c.snapshotCache.GetAcknowledgedGeneration(nodeID, acked) >= target.generation.Generation {
+					node.Acknowledged++
+				}
+			}
+		}
+		status.Nodes = append(status.Nodes, node)
+	}
+	sort.Slice(status.Nodes, func(i, j int) bool {
+		return status.Nodes[i].NodeID < status.Nodes[j].NodeID
+	})
+	return status
+}
+
+// GetVirtualServicesForNode returns VirtualServices that contributed resources to the snapshot of the node.
+func (c *CacheUpdater) GetVirtualServicesForNode(nodeID string) []helpers.NamespacedName {
+	c.ownersMx.RLock()
+	defer c.ownersMx.RUnlock()
+	return c.resourceOwners.virtualServices(nodeID)
+}
+
+// recordSyncTargets records, for every VirtualService, the generations of its nodes that first
+// contained its current build. Nodes that kept their previous snapshot keep their targets.
+// It returns VirtualServices whose targets changed. Must be called with c.mx and c.ownersMx held.
+func (c *CacheUpdater) recordSyncTargets(owners resourceOwners) []helpers.NamespacedName {
+	if c.syncTargets == nil {
+		c.syncTargets = make(map[helpers.NamespacedName]map[string]syncTarget)
+	}
+	changed := make(map[helpers.NamespacedName]struct{})
+	vsNodes := owners.nodes()
+	for vsNN, nodeIDs := range vsNodes {
+		build := c.incremental.builds[vsNN]
+		targets, ok := c.syncTargets[vsNN]
+		if !ok {
+			targets = make(map[string]syncTarget)
+			c.syncTargets[vsNN] = targets
+		}
+		for nodeID := range nodeIDs {
+			if c.incremental.isFailed(nodeID) {
+				continue
+			}
+			if target, ok := targets[nodeID]; ok && target.build == build {
+				continue
+			}
+			generation, ok := c.snapshotCache.GetGeneration(nodeID)
+			if !ok {
+				continue
+			}
+			targets[nodeID] = syncTarget{build: build, generation: generation}
+			changed[vsNN] = struct{}{}
+		}
+		for nodeID := range targets {
+			if _, ok := nodeIDs[nodeID]; !ok {
+				delete(targets, nodeID)
+				changed[vsNN] = struct{}{}
+			}
+		}
+	}
+	for vsNN := range c.syncTargets {
+		if _, ok := vsNodes[vsNN]; !ok {
+			delete(c.syncTargets, vsNN)
+			changed[vsNN] = struct{}{}
+		}
+	}
+
+	result := make([]helpers.NamespacedName, 0, len(changed))
+	for vsNN := range changed {
+		result = append(result, vsNN)
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package updater
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+)
+
+type staticAckSource map[string][]map[string]string
+
+func (s staticAckSource) AcknowledgedVersions(nodeID string) []map[string]string {
+	return s[nodeID]
+}
+
+// TestSyncStatus verifies that a VirtualService is synced once proxies acknowledged the snapshot
+// that first contained it, and that changes of other VirtualServices do not reset its status.
+func TestSyncStatus(t *testing.T) {
+	ctx := context.Background()
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, _ store.Store) (*resbuilder.Resources, error) {
+		return &resbuilder.Resources{
+			Listener:    helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain: []*listenerv3.FilterChain{{Name: vs.Name}},
+			Clusters:    []*clusterv3.Cluster{{Name: "cluster-" + vs.Name}},
+			Domains:     []string{vs.Name + ".example.com"},
+		}, nil
+	})()
+
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-a", []string{testNodeID}, "http"))
+	vsA := helpers.NamespacedName{Namespace: "ns", Name: "vs-a"}
+
+	status := cu.GetSyncStatus(vsA)
+	if len(status.Nodes) != 1 || status.Nodes[0].NodeID != testNodeID || status.Synced() {
+		t.Fatalf("expected a single node without proxies, got %+v", status)
+	}
+	if msg := status.Message(); msg != "no connected proxies serve the VirtualService" {
+		t.Errorf("unexpected message %q", msg)
+	}
+
+	acked := map[string]string{
+		resource.ListenerType: cu.snapshotCache.GetVersion(testNodeID, resource.ListenerType),
+		resource.ClusterType:  cu.snapshotCache.GetVersion(testNodeID, resource.ClusterType),
+	}
+	cu.SetAckSource(staticAckSource{testNodeID: {acked, {resource.ListenerType: ""}}})
+	status = cu.GetSyncStatus(vsA)
+	if acknowledged, total := status.Proxies(); acknowledged != 1 || total != 2 || status.Synced() {
+		t.Errorf("expected 1/2 proxies, got %d/%d", acknowledged, total)
+	}
+	if msg := status.Message(); !strings.HasPrefix(msg, "1/2 proxies acknowledged version ") {
+		t.Errorf("unexpected message %q", msg)
+	}
+
+	cu.SetAckSource(staticAckSource{testNodeID: {acked}})
+	if status = cu.GetSyncStatus(vsA); !status.Synced() {
+		t.Errorf("expected vs-a to be synced, got %+v", status)
+	}
+
+	// A new VirtualService on the node is pending, while vs-a stays synced
+	var notified []helpers.NamespacedName
+	cu.SetOnStatusChange(func(vss []helpers.NamespacedName) { notified = append(notified, vss...) })
+	cu.ApplyVirtualService(ctx, makeVSWithListener("vs-b", []string{testNodeID}, "http"))
+	vsB := helpers.NamespacedName{Namespace: "ns", Name: "vs-b"}
+	if len(notified) != 1 || notified[0] != vsB {
+		t.Errorf("expected only vs-b to be notified, got %v", notified)
+	}
+	if status = cu.GetSyncStatus(vsA); !status.Synced() {
+		t.Errorf("expected vs-a to stay synced, got %+v", status)
+	}
+	if status = cu.GetSyncStatus(vsB); status.Synced() {
+		t.Errorf("expected vs-b to be pending, got %+v", status)
+	}
+	if vss := cu.GetVirtualServicesForNode(testNodeID); len(vss) != 2 || vss[0] != vsA || vss[1] != vsB {
+		t.Errorf("expected both VirtualServices on the node, got %v", vss)
+	}
+}
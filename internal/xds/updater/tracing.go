@@ -5,6 +5,7 @@ import (
 
 	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -14,14 +15,16 @@ func (c *CacheUpdater) ApplyTracing(ctx context.Context, tracing *v1alpha1.Traci
 	prevTracing := c.store.GetTracing(helpers.NamespacedName{Namespace: tracing.Namespace, Name: tracing.Name})
 	if prevTracing == nil {
 		c.store.SetTracing(tracing)
-		_ = c.rebuildSnapshots(ctx)
+		c.invalidateDependents(dependency(store.DependencyTracing, tracing.Namespace, tracing.Name))
+		_ = c.requestRebuild(ctx)
 		return
 	}
 	if prevTracing.IsEqual(tracing) {
 		return
 	}
 	c.store.SetTracing(tracing)
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencyTracing, tracing.Namespace, tracing.Name))
+	_ = c.requestRebuild(ctx)
 }
 
 func (c *CacheUpdater) DeleteTracing(ctx context.Context, nn types.NamespacedName) {
@@ -31,5 +34,6 @@ func (c *CacheUpdater) DeleteTracing(ctx context.Context, nn types.NamespacedNam
 		return
 	}
 	c.store.DeleteTracing(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencyTracing, nn.Namespace, nn.Name))
+	_ = c.requestRebuild(ctx)
 }
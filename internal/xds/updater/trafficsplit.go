@@ -0,0 +1,116 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/routes"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TrafficSplits are rendered into routes of the VirtualServices they target: a change only
+// rebuilds the VirtualServices it was and is targeting. A TrafficSplit which cannot be applied,
+// like one splitting a missing route, is skipped without invalidating its VirtualService, and
+// its error is reported in its own status.
+
+func (c *CacheUpdater) ApplyTrafficSplit(ctx context.Context, ts *v1alpha1.TrafficSplit) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	prevTS := c.store.GetTrafficSplit(helpers.NamespacedName{Namespace: ts.Namespace, Name: ts.Name})
+	if prevTS.IsEqual(ts) {
+		return
+	}
+	c.store.SetTrafficSplit(ts)
+	c.invalidateDependents(trafficSplitDependencies(prevTS, ts)...)
+	_ = c.requestRebuild(ctx)
+}
+
+func (c *CacheUpdater) DeleteTrafficSplit(ctx context.Context, nn types.NamespacedName) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	prevTS := c.store.GetTrafficSplit(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
+	if prevTS == nil {
+		return
+	}
+	c.store.DeleteTrafficSplit(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
+	c.invalidateDependents(trafficSplitDependencies(prevTS)...)
+	_ = c.requestRebuild(ctx)
+}
+
+// trafficSplitDependencies returns the dependencies of VirtualServices targeted by the TrafficSplits
+func trafficSplitDependencies(splits ...*v1alpha1.TrafficSplit) []store.Dependency {
+	deps := make([]store.Dependency, 0, len(splits))
+	for _, ts := range splits {
+		if ts != nil {
+			vsNN := ts.GetVirtualServiceNamespacedName()
+			deps = append(deps, dependency(store.DependencyTrafficSplit, vsNN.Namespace, vsNN.Name))
+		}
+	}
+	return deps
+}
+
+// SetOnTrafficSplitsChange registers a function called with TrafficSplits whose error, as
+// returned by GetTrafficSplitError, changed after a rebuild. It must not block.
+func (c *CacheUpdater) SetOnTrafficSplitsChange(fn func([]helpers.NamespacedName)) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.onTrafficSplitsChange = fn
+}
+
+// GetTrafficSplitError returns why the last rebuild could not apply the TrafficSplit to the
+// routes of its VirtualService. It is nil if the TrafficSplit was applied or its VirtualService
+// was not built.
+func (c *CacheUpdater) GetTrafficSplitError(nn helpers.NamespacedName) error {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	if msg, ok := c.trafficSplitErrors[nn]; ok {
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// updateTrafficSplitErrors checks the TrafficSplits against the routes of the last builds of
+// their VirtualServices and reports those whose error changed. Must be called with c.mx held.
+func (c *CacheUpdater) updateTrafficSplitErrors() {
+	splits := c.store.MapTrafficSplits()
+	tsErrors := make(map[helpers.NamespacedName]string)
+	builder := routes.NewBuilder(c.store)
+	for tsNN, ts := range splits {
+		vsNN := ts.GetVirtualServiceNamespacedName()
+		build, ok := c.incremental.builds[vsNN]
+		if !ok || build.err != nil || build.res == nil {
+			continue
+		}
+		for _, virtualHost := range build.res.RouteConfig.GetVirtualHosts() {
+			if virtualHost.GetName() != vsNN.String() {
+				continue
+			}
+			if err := builder.TrafficSplitError(virtualHost, ts); err != nil {
+				tsErrors[tsNN] = err.Error()
+			}
+		}
+	}
+
+	var changed []helpers.NamespacedName
+	for tsNN, msg := range tsErrors {
+		if prev, ok := c.trafficSplitErrors[tsNN]; !ok || prev != msg {
+			changed = append(changed, tsNN)
+		}
+	}
+	for tsNN := range c.trafficSplitErrors {
+		if _, ok := tsErrors[tsNN]; !ok {
+			if _, exists := splits[tsNN]; exists {
+				changed = append(changed, tsNN)
+			}
+		}
+	}
+	c.trafficSplitErrors = tsErrors
+	if c.onTrafficSplitsChange != nil && len(changed) > 0 {
+		sort.Slice(changed, func(i, j int) bool { return changed[i].String() < changed[j].String() })
+		c.onTrafficSplitsChange(changed)
+	}
+}
@@ -0,0 +1,158 @@
+package updater
+
+import (
+	"context"
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
+	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
+	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/routes"
+	"google.golang.org/protobuf/types/known/anypb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TestTrafficSplit verifies that TrafficSplits are rendered into the clusters of the routes
+// they split, at the weight of their current step, and removed with them.
+func TestTrafficSplit(t *testing.T) {
+	ctx := context.Background()
+	var builds []string
+	defer withStubbedBuilder(t, func(vs *v1alpha1.VirtualService, st store.Store) (*resbuilder.Resources, error) {
+		builds = append(builds, vs.Name)
+		nn := helpers.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+		virtualHost, err := routes.NewBuilder(st).BuildVirtualHost(vs, nn)
+		if err != nil {
+			return nil, err
+		}
+		var clusters []*clusterv3.Cluster
+		for _, route := range virtualHost.Routes {
+			if name := route.GetRoute().GetCluster(); name != "" {
+				clusters = append(clusters, &clusterv3.Cluster{Name: name})
+			}
+			for _, wc := range route.GetRoute().GetWeightedClusters().GetClusters() {
+				clusters = append(clusters, &clusterv3.Cluster{Name: wc.Name})
+			}
+		}
+		// The filter chain references the route configuration, so that the snapshot is consistent
+		hcm, err := anypb.New(&hcmv3.HttpConnectionManager{
+			StatPrefix:     vs.Name,
+			RouteSpecifier: &hcmv3.HttpConnectionManager_Rds{Rds: &hcmv3.Rds{RouteConfigName: vs.Name}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &resbuilder.Resources{
+			Listener: helpers.NamespacedName{Namespace: "ns", Name: "http"},
+			FilterChain: []*listenerv3.FilterChain{{
+				Name: vs.Name,
+				Filters: []*listenerv3.Filter{{
+					Name:       "envoy.filters.network.http_connection_manager",
+					ConfigType: &listenerv3.Filter_TypedConfig{TypedConfig: hcm},
+				}},
+			}},
+			RouteConfig: &routev3.RouteConfiguration{Name: vs.Name, VirtualHosts: []*routev3.VirtualHost{virtualHost}},
+			Clusters:    clusters,
+			Domains:     virtualHost.Domains,
+		}, nil
+	})()
+
+	cu := NewCacheUpdater(wrapped.NewSnapshotCache(), store.New())
+	var changedSplits []helpers.NamespacedName
+	cu.SetOnTrafficSplitsChange(func(nns []helpers.NamespacedName) { changedSplits = append(changedSplits, nns...) })
+	cu.ApplyListener(ctx, makeListenerCR("ns", "http", "0.0.0.0", 8080))
+	cu.ApplyCluster(ctx, makeClusterCR("ns", "api-stable", "stable"))
+	cu.ApplyCluster(ctx, makeClusterCR("ns", "api-canary", "canary"))
+	cu.ApplyVirtualService(ctx, makeVSWithRoutes("vs-a", "a.example.com"))
+	cu.ApplyVirtualService(ctx, makeVSWithRoutes("vs-b", "b.example.com"))
+	assertClusters(t, cu, testNodeID, "stable")
+
+	ts := &v1alpha1.TrafficSplit{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "api"},
+		Spec: v1alpha1.TrafficSplitSpec{
+			VirtualService: "vs-a",
+			Route:          "api",
+			Stable:         v1alpha1.ResourceRef{Name: "api-stable"},
+			Canary:         v1alpha1.ResourceRef{Name: "api-canary"},
+			Steps:          []v1alpha1.TrafficSplitStep{{Weight: 5}, {Weight: 100}},
+		},
+	}
+	builds = nil
+	cu.ApplyTrafficSplit(ctx, ts)
+	if len(builds) != 1 || builds[0] != "vs-a" {
+		t.Errorf("expected only vs-a to be rebuilt, got %v", builds)
+	}
+	assertClusters(t, cu, testNodeID, "canary", "stable")
+	vsA := cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: "vs-a"})
+	if vsA.Status.Invalid {
+		t.Fatalf("expected vs-a to be valid, got %q", vsA.Status.Message)
+	}
+
+	// The last step sends all traffic to the canary
+	ts = ts.DeepCopy()
+	ts.Status.CurrentStep = 1
+	cu.ApplyTrafficSplit(ctx, ts)
+	assertClusters(t, cu, testNodeID, "canary", "stable")
+	vsB := cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: "vs-b"})
+	if vsB.Status.Invalid {
+		t.Fatalf("expected vs-b to be valid, got %q", vsB.Status.Message)
+	}
+
+	// Aborting sends all traffic to the stable cluster
+	ts = ts.DeepCopy()
+	ts.Spec.Aborted = true
+	cu.ApplyTrafficSplit(ctx, ts)
+	assertClusters(t, cu, testNodeID, "stable")
+
+	// A TrafficSplit of a missing route is skipped and reports the error, the VirtualService stays valid
+	tsNN := helpers.NamespacedName{Namespace: "ns", Name: "api"}
+	if err := cu.GetTrafficSplitError(tsNN); err != nil {
+		t.Errorf("expected no error for an applied TrafficSplit, got %v", err)
+	}
+	ts = ts.DeepCopy()
+	ts.Spec.Aborted = false
+	ts.Spec.Route = "missing"
+	cu.ApplyTrafficSplit(ctx, ts)
+	vsA = cu.GetVirtualServiceWithStatus(helpers.NamespacedName{Namespace: "ns", Name: "vs-a"})
+	if vsA.Status.Invalid {
+		t.Errorf("expected vs-a to stay valid with a missing route, got %q", vsA.Status.Message)
+	}
+	assertClusters(t, cu, testNodeID, "stable")
+	if err := cu.GetTrafficSplitError(tsNN); err == nil || err.Error() != "route missing not found" {
+		t.Errorf("expected the missing route to be reported, got %v", err)
+	}
+	if len(changedSplits) != 1 || changedSplits[0] != tsNN {
+		t.Errorf("expected the TrafficSplit error change to be reported, got %v", changedSplits)
+	}
+
+	// Fixing the route clears the error
+	changedSplits = nil
+	ts = ts.DeepCopy()
+	ts.Spec.Route = "api"
+	cu.ApplyTrafficSplit(ctx, ts)
+	assertClusters(t, cu, testNodeID, "canary", "stable")
+	if err := cu.GetTrafficSplitError(tsNN); err != nil {
+		t.Errorf("expected the error to be cleared, got %v", err)
+	}
+	if len(changedSplits) != 1 || changedSplits[0] != tsNN {
+		t.Errorf("expected the TrafficSplit error change to be reported, got %v", changedSplits)
+	}
+
+	cu.DeleteTrafficSplit(ctx, types.NamespacedName{Namespace: "ns", Name: "api"})
+	assertClusters(t, cu, testNodeID, "stable")
+}
+
+// makeVSWithRoutes creates a VirtualService on the http listener routing to the stable cluster
+func makeVSWithRoutes(name, domain string) *v1alpha1.VirtualService {
+	vs := makeVSWithListener(name, []string{testNodeID}, "http")
+	vs.Spec.VirtualHost = &runtime.RawExtension{Raw: []byte(`{"domains":["` + domain + `"],"routes":[` +
+		`{"name":"api","match":{"prefix":"/api"},"route":{"cluster":"stable"}}]}`)}
+	return vs
+}
@@ -6,7 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"strconv"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -20,9 +21,11 @@ import (
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
 	"github.com/kaasops/envoy-xds-controller/internal/store"
 	wrapped "github.com/kaasops/envoy-xds-controller/internal/xds/cache"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/endpoints"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/filters"
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/utils"
 	"go.uber.org/multierr"
 	"golang.org/x/exp/maps"
-	"google.golang.org/protobuf/proto"
 )
 
 // CacheUpdater manages the xDS snapshot cache and coordinates updates between
@@ -38,12 +41,86 @@ type CacheUpdater struct {
 	snapshotCache *wrapped.SnapshotCache
 	store         store.Store
 	usedSecrets   map[helpers.NamespacedName]helpers.NamespacedName
+
+	// incremental keeps VirtualService build results between rebuilds
+	incremental *incrementalBuild
+
+	// scheduler coalesces rebuilds when enabled; nil means synchronous rebuilds.
+	scheduler      *rebuildScheduler
+	vsStatuses     map[helpers.NamespacedName]VSStatus
+	onStatusChange func([]helpers.NamespacedName)
+	// onReferencesChange is called with objects VirtualServices started or stopped being built from
+	onReferencesChange func([]store.Dependency)
+	// trafficSplitErrors are the errors of TrafficSplits the last rebuild could not apply
+	trafficSplitErrors    map[helpers.NamespacedName]string
+	onTrafficSplitsChange func([]helpers.NamespacedName)
+
+	// ownersMx guards resourceOwners separately from mx, so xDS stream callbacks
+	// resolving NACKs are not blocked by a running rebuild.
+	ownersMx       sync.RWMutex
+	resourceOwners resourceOwners
+	// syncTargets are the snapshot generations per node VirtualServices must be acknowledged at
+	syncTargets map[helpers.NamespacedName]map[string]syncTarget
+	ackSource   AckSource
+
+	// nodesMx guards nodes separately from mx, so xDS stream callbacks reporting
+	// connected nodes are not blocked by a running rebuild.
+	nodesMx       sync.RWMutex
+	nodes         nodeLabels
+	defaultNodeID string
+	// releasedNodes were disconnected since the last rebuild
+	releasedNodes map[string]struct{}
+	// unmatchedNodes were connected and not selected by any VirtualService in the last rebuild
+	unmatchedNodes map[string]struct{}
 }
 
 // VSStatus represents the status of a VirtualService after processing
 type VSStatus struct {
 	Invalid bool
 	Message string
+	Reason  InvalidReason
+	// Generation is the generation of the VirtualService the status was built from
+	Generation int64
+}
+
+// InvalidReason classifies why a VirtualService is invalid
+type InvalidReason string
+
+// Reasons of invalid VirtualServices. Other failures have an empty reason.
+const (
+	InvalidReasonTemplateFailed   InvalidReason = "TemplateFailed"
+	InvalidReasonListenerNotFound InvalidReason = "ListenerNotFound"
+	InvalidReasonSecretNotFound   InvalidReason = "SecretNotFound"
+	InvalidReasonClusterNotFound  InvalidReason = "ClusterNotFound"
+)
+
+// invalid returns the status of the same generation failing with err, reported with message
+func (s VSStatus) invalid(err error, message string) VSStatus {
+	return VSStatus{Invalid: true, Message: message, Reason: invalidReasonOf(err), Generation: s.Generation}
+}
+
+// invalidReasonOf classifies the build error of a VirtualService
+func invalidReasonOf(err error) InvalidReason {
+	var templateErr *utils.TemplateError
+	if errors.As(err, &templateErr) {
+		return InvalidReasonTemplateFailed
+	}
+	var notFoundErr *utils.NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		return ""
+	}
+	switch notFoundErr.Kind {
+	case utils.KindTemplate:
+		return InvalidReasonTemplateFailed
+	case utils.KindListener:
+		return InvalidReasonListenerNotFound
+	case utils.KindSecret:
+		return InvalidReasonSecretNotFound
+	case utils.KindCluster:
+		return InvalidReasonClusterNotFound
+	default:
+		return ""
+	}
 }
 
 // buildMetrics tracks timing for different phases of snapshot building
@@ -51,11 +128,14 @@ type buildMetrics struct {
 	initDuration           time.Duration
 	vsProcessingDuration   time.Duration
 	commonVSsDuration      time.Duration
+	domainsDuration        time.Duration
 	listenerBuildDuration  time.Duration
 	snapshotCreateDuration time.Duration
 	totalVSCount           int
 	processedVSCount       int
 	commonVSCount          int
+	// unmatchedNodes are connected nodes no VirtualService selects, sorted
+	unmatchedNodes []string
 }
 
 var buildVSResources = buildResourcesAdapter
@@ -65,6 +145,7 @@ func NewCacheUpdater(wsc *wrapped.SnapshotCache, store store.Store) *CacheUpdate
 		snapshotCache: wsc,
 		usedSecrets:   make(map[helpers.NamespacedName]helpers.NamespacedName),
 		store:         store,
+		incremental:   newIncrementalBuild(),
 	}
 }
 
@@ -73,9 +154,11 @@ func NewCacheUpdater(wsc *wrapped.SnapshotCache, store store.Store) *CacheUpdate
 // cannot be guaranteed; callers should fallback to heavy dry-run.
 var ErrLightValidationInsufficientCoverage = errors.New("light validation insufficient coverage")
 
+// RebuildSnapshots rebuilds resources of all VirtualServices and snapshots of all nodes.
 func (c *CacheUpdater) RebuildSnapshots(ctx context.Context) error {
 	c.mx.Lock()
 	defer c.mx.Unlock()
+	c.incremental.reset()
 	return c.rebuildSnapshots(ctx)
 }
 
@@ -88,10 +171,13 @@ func (c *CacheUpdater) CopyStore() store.Store {
 func (c *CacheUpdater) DryBuildSnapshotsWithVirtualService(ctx context.Context, vs *v1alpha1.VirtualService) error {
 	c.mx.RLock()
 	storeCopy := c.store.Copy()
+	knownRejections := maps.Clone(c.incremental.rejected)
 	c.mx.RUnlock()
+	// Only rejections of other VirtualServices are known, the validated one must pass on its own
+	delete(knownRejections, helpers.NamespacedName{Namespace: vs.Namespace, Name: vs.Name})
 	storeCopy.SetVirtualService(vs)
-	err, _, _, _ := buildSnapshots(ctx, wrapped.NewSnapshotCache(), storeCopy)
-	return err
+	err, _, _, _, _ := buildSnapshots(ctx, wrapped.NewSnapshotCache(), storeCopy, nil, c.getConnectedNodes())
+	return dropKnownRejections(err, knownRejections)
 }
 
 // DryValidateVirtualServiceLight performs a lightweight validation for a VirtualService without rebuilding
@@ -111,6 +197,10 @@ func (c *CacheUpdater) DryValidateVirtualServiceLight(
 	if err := ctx.Err(); err != nil {
 		return err
 	}
+	// Nodes selected by labels are not known from the node IDs, fall back to heavy dry-run
+	if hasNodeSelector(vs) || (prevVS != nil && hasNodeSelector(prevVS)) {
+		return ErrLightValidationInsufficientCoverage
+	}
 
 	// Work on a copy of the store and overlay the candidate VS
 	c.mx.RLock()
@@ -197,6 +287,7 @@ func (c *CacheUpdater) DryBuildSnapshotsWithVirtualServiceTemplate(
 
 	start := time.Now()
 	storeCopy := c.store.Copy()
+	knownRejections := c.incremental.rejected
 	c.mx.RUnlock()
 	copyDuration := time.Since(start)
 
@@ -214,7 +305,8 @@ func (c *CacheUpdater) DryBuildSnapshotsWithVirtualServiceTemplate(
 	storeCopy.SetVirtualServiceTemplate(vst)
 
 	buildStart := time.Now()
-	err, usedSecrets, vsStatuses, metrics := buildSnapshots(ctx, wrapped.NewSnapshotCache(), storeCopy)
+	err, usedSecrets, vsStatuses, metrics, _ := buildSnapshots(ctx, wrapped.NewSnapshotCache(), storeCopy, nil, c.getConnectedNodes())
+	err = dropKnownRejections(err, knownRejections)
 	buildDuration := time.Since(buildStart)
 	totalDuration := time.Since(validationStart)
 
@@ -237,6 +329,7 @@ func (c *CacheUpdater) DryBuildSnapshotsWithVirtualServiceTemplate(
 		"build.initDuration", metrics.initDuration.String(),
 		"build.vsProcessingDuration", metrics.vsProcessingDuration.String(),
 		"build.commonVSsDuration", metrics.commonVSsDuration.String(),
+		"build.domainsDuration", metrics.domainsDuration.String(),
 		"build.listenerBuildDuration", metrics.listenerBuildDuration.String(),
 		"build.snapshotCreateDuration", metrics.snapshotCreateDuration.String(),
 		"build.totalVSCount", metrics.totalVSCount,
@@ -264,7 +357,10 @@ func (c *CacheUpdater) rebuildSnapshots(ctx context.Context) error {
 	rlog := log.FromContext(ctx).WithName("cache-updater")
 	rlog.Info("rebuild snapshots started")
 	start := time.Now()
-	err, usedSecrets, vsStatuses, _ := buildSnapshots(ctx, c.snapshotCache, c.store)
+	nodes := c.getConnectedNodes()
+	c.clearReleasedNodes(nodes.labels)
+	c.incremental.prepare(c.store.MapVirtualServices(), nodes.labels)
+	err, usedSecrets, vsStatuses, metrics, owners := buildSnapshots(ctx, c.snapshotCache, c.store, c.incremental, nodes)
 
 	// Apply statuses to VirtualServices ALWAYS (even if build failed)
 	// This ensures invalid VS get their error status set in store
@@ -272,25 +368,53 @@ func (c *CacheUpdater) rebuildSnapshots(ctx context.Context) error {
 	for vsNN, status := range vsStatuses {
 		// Check if OptimizedStore (has status storage)
 		if optimizedStore, ok := c.store.(interface {
-			SetVirtualServiceStatus(helpers.NamespacedName, bool, string)
+			SetVirtualServiceStatus(helpers.NamespacedName, bool, string, int64)
 		}); ok {
 			// Use separate status storage - no mutation, no DeepCopy needed
-			optimizedStore.SetVirtualServiceStatus(vsNN, status.Invalid, status.Message)
+			optimizedStore.SetVirtualServiceStatus(vsNN, status.Invalid, status.Message, status.Generation)
 		} else {
 			// Fallback for LegacyStore - use DeepCopy pattern
 			if vs := c.store.GetVirtualService(vsNN); vs != nil {
 				vsNew := vs.DeepCopy()
 				vsNew.UpdateStatus(status.Invalid, status.Message)
+				vsNew.Status.ObservedGeneration = status.Generation
 				c.store.SetVirtualService(vsNew)
 			}
 		}
 	}
+	c.notifyStatusChanges(vsStatuses)
+	c.notifyReferenceChanges()
+	c.updateTrafficSplitErrors()
+
+	updateNodeHealthMetrics(c.snapshotCache.ListNodeHealth())
+
+	if owners == nil {
+		c.incremental.done(false)
+		rlog.Error(err, "rebuild snapshots failed", "duration", time.Since(start).String())
+		return err
+	}
+	c.usedSecrets = usedSecrets
+	c.reportUnmatchedNodes(ctx, metrics.unmatchedNodes, nodes.defaultNodeID)
+
+	c.ownersMx.Lock()
+	// Owners of nodes that were not re-mixed or kept their previous snapshot are carried over
+	for nodeID, byType := range c.resourceOwners {
+		if _, ok := owners[nodeID]; !ok && (!c.incremental.isAffected(nodeID) || c.incremental.isFailed(nodeID)) {
+			owners[nodeID] = byType
+		}
+	}
+	c.resourceOwners = owners
+	syncChanged := c.recordSyncTargets(owners)
+	c.ownersMx.Unlock()
+	c.incremental.done(true)
+	if c.onStatusChange != nil && len(syncChanged) > 0 {
+		c.onStatusChange(syncChanged)
+	}
 
 	if err != nil {
 		rlog.Error(err, "rebuild snapshots with errors", "duration", time.Since(start).String())
 		return err
 	}
-	c.usedSecrets = usedSecrets
 
 	rlog.Info("rebuild snapshots done", "duration", time.Since(start).String())
 	return nil
@@ -312,12 +436,31 @@ func getRootCause(err error) error {
 	}
 }
 
+// buildSnapshots builds snapshots of all nodes. With a non-nil inc, only the nodes
+// affected since the previous rebuild are re-mixed and staged, reusing cached
+// VirtualService resources; snapshots of the other nodes are left untouched.
+// Nodes are committed independently: a node failing to build or stage keeps its
+// previous snapshot and is reported as failed in the snapshot cache. The returned
+// owners are nil if nothing was committed; otherwise the error lists failures of
+// individual VirtualServices and nodes.
+//
+// Connected nodes no VirtualService selects by node ID or node selector are served
+// common VirtualServices and those of the default node, or an empty snapshot.
+//
 // nolint: gocyclo
 func buildSnapshots(
 	ctx context.Context,
 	snapshotCache *wrapped.SnapshotCache,
 	store store.Store,
-) (error, map[helpers.NamespacedName]helpers.NamespacedName, map[helpers.NamespacedName]VSStatus, buildMetrics) {
+	inc *incrementalBuild,
+	nodes connectedNodes,
+) (
+	error,
+	map[helpers.NamespacedName]helpers.NamespacedName,
+	map[helpers.NamespacedName]VSStatus,
+	buildMetrics,
+	resourceOwners,
+) {
 	metrics := buildMetrics{}
 	initStart := time.Now()
 
@@ -326,7 +469,7 @@ func buildSnapshots(
 
 	// Honor context cancellation early
 	if err := ctx.Err(); err != nil {
-		return err, nil, vsStatuses, metrics
+		return err, nil, vsStatuses, metrics, nil
 	}
 
 	mixer := NewMixer()
@@ -334,7 +477,6 @@ func buildSnapshots(
 	// ---------------------------------------------
 
 	usedSecrets := make(map[helpers.NamespacedName]helpers.NamespacedName)
-	nodeIDDomainsSet := make(map[string]struct{})
 
 	// Optional index of domains per node for light validation (feature-flagged)
 	buildDomainsIndex := getValidationIndicesEnabled()
@@ -344,7 +486,20 @@ func buildSnapshots(
 	}
 
 	nodeIDsForCleanup := snapshotCache.GetNodeIDsAsMap()
-	var commonVirtualServices []*v1alpha1.VirtualService
+	// Failing nodes keep their previous snapshots
+	failedNodes := make(map[string]error)
+	failNode := func(nodeID string, err error) {
+		if _, ok := failedNodes[nodeID]; !ok {
+			failedNodes[nodeID] = err
+		}
+	}
+	var builtVirtualServices []builtVirtualService
+	candidateNodeIDs := make(map[string]struct{})
+	// selected nodes are served a VirtualService listing them or selecting them by labels
+	selected := make(map[string]struct{})
+	// rejected VirtualServices are invalid but do not fail their nodes: they are not served
+	// on them, like VirtualServices using secrets they are not allowed to or duplicate domains
+	rejected := make(map[helpers.NamespacedName][]string)
 
 	metrics.initDuration = time.Since(initStart)
 	vsProcessingStart := time.Now()
@@ -352,170 +507,292 @@ func buildSnapshots(
 	allVSs := store.MapVirtualServices()
 	metrics.totalVSCount = len(allVSs)
 
+	var commonVirtualServices []*v1alpha1.VirtualService
 	for _, vs := range allVSs {
 		// Check cancellation between iterations
 		if err := ctx.Err(); err != nil {
-			return err, usedSecrets, vsStatuses, metrics
+			return err, usedSecrets, vsStatuses, metrics, nil
 		}
 
 		vsNN := helpers.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
 		// Initialize status as valid (replaces vs.UpdateStatus(false, ""))
-		vsStatuses[vsNN] = VSStatus{Invalid: false, Message: ""}
+		vsStatuses[vsNN] = VSStatus{Invalid: false, Message: "", Generation: vs.Generation}
 
-		vsNodeIDs := vs.GetNodeIDs()
-		if len(vsNodeIDs) == 0 {
-			err := fmt.Errorf("virtual service %s/%s has no node IDs", vs.Namespace, vs.Name)
+		vsNodeIDs, err := nodes.labels.resolveNodeIDs(vs)
+		if err == nil && len(vsNodeIDs) == 0 && !hasNodeSelector(vs) {
+			err = fmt.Errorf("virtual service %s/%s has no node IDs", vs.Namespace, vs.Name)
+		}
+		if err != nil {
 			// Store error status instead of mutating (replaces vs.UpdateStatus(true, err.Error()))
-			rootErr := getRootCause(err)
-			vsStatuses[vsNN] = VSStatus{Invalid: true, Message: rootErr.Error()}
+			vsStatuses[vsNN] = vsStatuses[vsNN].invalid(err, getRootCause(err).Error())
 			errs = append(errs, err)
 			continue
 		}
+		if len(vsNodeIDs) == 0 {
+			// No connected node matches the node selector yet
+			continue
+		}
 
 		if isCommonVirtualService(vsNodeIDs) {
 			commonVirtualServices = append(commonVirtualServices, vs)
 			metrics.commonVSCount++
 			continue
 		}
+		for _, nodeID := range vsNodeIDs {
+			selected[nodeID] = struct{}{}
+		}
 
 		// Build resources for VS (may be heavy); check ctx before and after
 		if err := ctx.Err(); err != nil {
-			return err, usedSecrets, vsStatuses, metrics
+			return err, usedSecrets, vsStatuses, metrics, nil
 		}
-		vsRes, err := buildVSResources(vs, store)
+		vsRes, err := inc.build(vs, store, vsNodeIDs)
 		if err != nil {
 			// Store error status instead of mutating (replaces vs.UpdateStatus(true, err.Error()))
-			rootErr := getRootCause(err)
-			vsStatuses[vsNN] = VSStatus{Invalid: true, Message: rootErr.Error()}
+			vsStatuses[vsNN] = vsStatuses[vsNN].invalid(err, getRootCause(err).Error())
 			errs = append(errs, err)
+			if isSecretAccessError(err) {
+				rejected[vsNN] = vsNodeIDs
+				continue
+			}
+			for _, nodeID := range vsNodeIDs {
+				failNode(nodeID, fmt.Errorf("virtual service %s: %w", vsNN.String(), err))
+			}
 			continue
 		}
 		if err := ctx.Err(); err != nil {
-			return err, usedSecrets, vsStatuses, metrics
+			return err, usedSecrets, vsStatuses, metrics, nil
 		}
 		metrics.processedVSCount++
 
 		for _, secret := range vsRes.UsedSecrets {
 			usedSecrets[secret] = helpers.NamespacedName{Name: vs.Name, Namespace: vs.Namespace}
 		}
-
 		for _, nodeID := range vsNodeIDs {
-			// Check ctx inside nested loops too
-			if err := ctx.Err(); err != nil {
-				return err, usedSecrets, vsStatuses, metrics
-			}
-
-			for _, domain := range vsRes.Domains {
-				if err := ctx.Err(); err != nil { // cheap check
-					return err, usedSecrets, vsStatuses, metrics
-				}
-				nodeDom := nodeIDDomain(nodeID, domain)
-				if _, ok := nodeIDDomainsSet[nodeDom]; ok {
-					return fmt.Errorf("duplicate domain %s for node %s", domain, nodeID), nil, vsStatuses, metrics
-				}
-				nodeIDDomainsSet[nodeDom] = struct{}{}
-				if buildDomainsIndex {
-					set, ok := nodeDomainsIndex[nodeID]
-					if !ok {
-						set = make(map[string]struct{})
-						nodeDomainsIndex[nodeID] = set
-					}
-					set[domain] = struct{}{}
-				}
-			}
+			candidateNodeIDs[nodeID] = struct{}{}
+		}
+		builtVirtualServices = append(builtVirtualServices, builtVirtualService{vs: vs, nodeIDs: vsNodeIDs, res: vsRes})
+	}
 
-			if vsRes.RouteConfig != nil {
-				mixer.Add(nodeID, resource.RouteType, vsRes.RouteConfig)
-			}
-			for _, cl := range vsRes.Clusters {
-				mixer.Add(nodeID, resource.ClusterType, cl)
-			}
-			for _, secret := range vsRes.Secrets {
-				mixer.Add(nodeID, resource.SecretType, secret)
-			}
-			mixer.AddListenerParams(vsRes.Listener, vsRes.FilterChain, nodeID)
+	// Common VirtualServices are served on connected nodes too
+	var unmatched []string
+	for nodeID := range nodes.labels {
+		candidateNodeIDs[nodeID] = struct{}{}
+		if _, ok := selected[nodeID]; !ok {
+			unmatched = append(unmatched, nodeID)
 		}
 	}
+	sort.Strings(unmatched)
+	metrics.unmatchedNodes = unmatched
 
 	metrics.vsProcessingDuration = time.Since(vsProcessingStart)
 
 	// Process common VirtualServices
 	commonVSsStart := time.Now()
-	if len(commonVirtualServices) > 0 {
-		for _, vs := range commonVirtualServices {
-			if err := ctx.Err(); err != nil {
-				return err, usedSecrets, vsStatuses, metrics
-			}
-			vsRes, err := buildVSResources(vs, store)
-			if err != nil {
-				errs = append(errs, err)
+	var commonErrs []error
+	for _, vs := range commonVirtualServices {
+		if err := ctx.Err(); err != nil {
+			return err, usedSecrets, vsStatuses, metrics, nil
+		}
+		vsRes, err := inc.build(vs, store, vs.GetNodeIDs())
+		if err != nil {
+			errs = append(errs, err)
+			if isSecretAccessError(err) {
+				vsNN := helpers.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}
+				vsStatuses[vsNN] = vsStatuses[vsNN].invalid(err, getRootCause(err).Error())
+				rejected[vsNN] = vs.GetNodeIDs()
 				continue
 			}
-			if err := ctx.Err(); err != nil {
-				return err, usedSecrets, vsStatuses, metrics
+			commonErrs = append(commonErrs, fmt.Errorf("virtual service %s/%s: %w", vs.Namespace, vs.Name, err))
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err, usedSecrets, vsStatuses, metrics, nil
+		}
+		for _, secret := range vsRes.UsedSecrets {
+			usedSecrets[secret] = helpers.NamespacedName{Name: vs.Name, Namespace: vs.Namespace}
+		}
+		builtVirtualServices = append(builtVirtualServices, builtVirtualService{vs: vs, nodeIDs: vs.GetNodeIDs(), res: vsRes})
+	}
+	metrics.commonVSsDuration = time.Since(commonVSsStart)
+
+	// Resolve domain ownership. On a duplicate domain the older VirtualService keeps it
+	// and the newer one is marked invalid, so other VirtualServices are still delivered.
+	// Common VirtualServices using secrets not allowed on all nodes are rejected the same way.
+	// Dry runs fail instead, so that webhooks reject the change introducing the duplicate.
+	domainsStart := time.Now()
+	sortByCreation(builtVirtualServices)
+	owners := make(domainOwners)
+	accepted := make([]builtVirtualService, 0, len(builtVirtualServices))
+	indexDomains := func(nodeIDs, domains []string) {
+		if !buildDomainsIndex {
+			return
+		}
+		for _, nodeID := range nodeIDs {
+			set, ok := nodeDomainsIndex[nodeID]
+			if !ok {
+				set = make(map[string]struct{})
+				nodeDomainsIndex[nodeID] = set
 			}
-			for _, secret := range vsRes.UsedSecrets {
-				usedSecrets[secret] = helpers.NamespacedName{Name: vs.Name, Namespace: vs.Namespace}
+			for _, domain := range domains {
+				set[domain] = struct{}{}
 			}
+		}
+	}
+	for _, b := range builtVirtualServices {
+		if err := ctx.Err(); err != nil {
+			return err, usedSecrets, vsStatuses, metrics, nil
+		}
+		vsNN := b.namespacedName()
+		nodeIDs := b.nodeIDs
+		if isCommonVirtualService(nodeIDs) {
+			nodeIDs = sortedNodeIDs(candidateNodeIDs)
+		}
+		err := checkSecretAccess(store, b.res.UsedSecrets, "", nodeIDs)
+		if err == nil {
+			err = owners.claim(vsNN, nodeIDs, b.res.Domains, b.res.ScopedRoutes)
+		}
+		if err != nil {
+			vsStatuses[vsNN] = vsStatuses[vsNN].invalid(err, err.Error())
+			rejected[vsNN] = b.nodeIDs
+			if inc == nil {
+				errs = append(errs, &rejectionError{vs: vsNN, err: err})
+			}
+			continue
+		}
+		indexDomains(nodeIDs, b.res.Domains)
+		accepted = append(accepted, b)
+	}
+	// Nodes of VirtualServices that became or stopped being rejected are re-mixed as well
+	inc.setRejected(rejected)
+	// Unmatched nodes are re-mixed with the default node
+	if nodes.defaultNodeID != "" && inc.isAffected(nodes.defaultNodeID) {
+		inc.affect(unmatched)
+	}
+	metrics.domainsDuration = time.Since(domainsStart)
 
-			for nodeID := range mixer.nodeIDs {
-				if err := ctx.Err(); err != nil {
-					return err, usedSecrets, vsStatuses, metrics
-				}
-
-				for _, domain := range vsRes.Domains {
-					if err := ctx.Err(); err != nil {
-						return err, usedSecrets, vsStatuses, metrics
-					}
-					nodeDom := nodeIDDomain(nodeID, domain)
-					if _, ok := nodeIDDomainsSet[nodeDom]; ok {
-						return fmt.Errorf("duplicate domain %s for node %s", domain, nodeID), nil, vsStatuses, metrics
-					}
-					nodeIDDomainsSet[nodeDom] = struct{}{}
-					if buildDomainsIndex {
-						set, ok := nodeDomainsIndex[nodeID]
-						if !ok {
-							set = make(map[string]struct{})
-							nodeDomainsIndex[nodeID] = set
-						}
-						set[domain] = struct{}{}
-					}
-				}
-
-				if vsRes.RouteConfig != nil {
-					mixer.Add(nodeID, resource.RouteType, vsRes.RouteConfig)
-				}
-				for _, cl := range vsRes.Clusters {
-					mixer.Add(nodeID, resource.ClusterType, cl)
-				}
-				for _, secret := range vsRes.Secrets {
-					mixer.Add(nodeID, resource.SecretType, secret)
-				}
-				mixer.AddListenerParams(vsRes.Listener, vsRes.FilterChain, nodeID)
+	for _, b := range accepted {
+		if isCommonVirtualService(b.nodeIDs) {
+			continue
+		}
+		for _, nodeID := range b.nodeIDs {
+			if inc.isAffected(nodeID) {
+				mixer.AddVirtualServiceResources(nodeID, b.namespacedName(), b.res)
 			}
 		}
 	}
-	metrics.commonVSsDuration = time.Since(commonVSsStart)
+	// Runtime layers are served to the nodes they list, and common ones to all nodes.
+	// Invalid or conflicting layers are skipped and do not fail the node.
+	runtimeLayers, runtimeErrs := buildRuntimeLayers(store)
+	runtimeErrs = append(runtimeErrs, mixRuntimeLayers(mixer, runtimeLayers, inc, false)...)
+	for _, nodeID := range unmatched {
+		if !inc.isAffected(nodeID) {
+			continue
+		}
+		mixer.AddNode(nodeID)
+		if err, ok := failedNodes[nodes.defaultNodeID]; ok {
+			failNode(nodeID, err)
+			continue
+		}
+		for _, b := range accepted {
+			if isCommonVirtualService(b.nodeIDs) || !slices.Contains(b.nodeIDs, nodes.defaultNodeID) {
+				continue
+			}
+			// Secrets the node may not use are not served on it
+			if checkSecretAccess(store, b.res.UsedSecrets, "", []string{nodeID}) != nil {
+				continue
+			}
+			mixer.AddVirtualServiceResources(nodeID, b.namespacedName(), b.res)
+			indexDomains([]string{nodeID}, b.res.Domains)
+		}
+	}
+	for _, b := range accepted {
+		if !isCommonVirtualService(b.nodeIDs) {
+			continue
+		}
+		for nodeID := range mixer.nodeIDs {
+			mixer.AddVirtualServiceResources(nodeID, b.namespacedName(), b.res)
+		}
+	}
+	runtimeErrs = append(runtimeErrs, mixRuntimeLayers(mixer, runtimeLayers, inc, true)...)
+	if len(runtimeErrs) > 0 && inc != nil {
+		log.FromContext(ctx).Error(multierr.Combine(runtimeErrs...), "runtime layers skipped")
+	}
+	// A broken common VirtualService affects every node it would be served on
+	if len(commonErrs) > 0 {
+		for nodeID := range mixer.nodeIDs {
+			failNode(nodeID, multierr.Combine(commonErrs...))
+		}
+	}
+
+	for nodeID := range nodeIDsForCleanup {
+		if !inc.isAffected(nodeID) {
+			delete(nodeIDsForCleanup, nodeID)
+		}
+	}
 
 	// Build listeners
 	listenerBuildStart := time.Now()
 	if err := ctx.Err(); err != nil {
-		return err, usedSecrets, vsStatuses, metrics
+		return err, usedSecrets, vsStatuses, metrics, nil
 	}
-	tmp, err := mixer.Mix(store)
+	tmp, nodeErrs, err := mixer.Mix(store)
 	if err != nil {
+		for nodeID := range mixer.nodeIDs {
+			failNode(nodeID, err)
+			inc.fail(nodeID)
+			snapshotCache.SetNodeFailed(nodeID, err)
+		}
 		errs = append(errs, err)
-		return multierr.Combine(errs...), usedSecrets, vsStatuses, metrics
+		return multierr.Combine(errs...), usedSecrets, vsStatuses, metrics, nil
+	}
+	// Nodes served a secret they may not use fail on their own
+	for nodeID, err := range nodeErrs {
+		failNode(nodeID, err)
+		errs = append(errs, fmt.Errorf("node %s: %w", nodeID, err))
 	}
 	metrics.listenerBuildDuration = time.Since(listenerBuildStart)
 
-	// Stage snapshots to avoid partial updates on cancellation or errors
+	// Resolve load assignments of EDS clusters. Unresolved ones are empty and do not fail the node.
+	endpointsBuilder := endpoints.NewBuilder(store)
+	for _, resMap := range tmp {
+		if las := endpointsBuilder.Build(resMap[resource.ClusterType]); len(las) > 0 {
+			resMap[resource.EndpointType] = las
+		}
+	}
+	if err := endpointsBuilder.Errors(); err != nil {
+		log.FromContext(ctx).Error(err, "failed to resolve load assignments")
+	}
+
+	// Resolve extension configs of HTTP filters served over ECDS. HCMs of the node reference
+	// them, so a node with an unresolved extension config fails and keeps its previous snapshot.
+	extensionConfigBuilder := filters.NewExtensionConfigBuilder(store)
+	for nodeID, resMap := range tmp {
+		refs := mixer.extensionConfigs[nodeID]
+		if len(refs) == 0 {
+			continue
+		}
+		tecs, err := extensionConfigBuilder.Build(refs)
+		if err != nil {
+			failNode(nodeID, err)
+			errs = append(errs, fmt.Errorf("node %s: %w", nodeID, err))
+			continue
+		}
+		resMap[resource.ExtensionConfigType] = tecs
+	}
+
+	// Stage snapshots to avoid partial updates on cancellation. Each node is committed
+	// on its own: nodes failing to build or stage keep their previous snapshot.
 	snapshotCreateStart := time.Now()
 	staged := make(map[string]cache.ResourceSnapshot)
+	unchanged := make(map[string]struct{})
 
 	for nodeID, resMap := range tmp {
 		if err := ctx.Err(); err != nil {
-			return err, usedSecrets, vsStatuses, metrics
+			return err, usedSecrets, vsStatuses, metrics, nil
+		}
+		if _, failed := failedNodes[nodeID]; failed {
+			continue
 		}
 		var snapshot *cache.Snapshot
 		var err error
@@ -524,56 +801,75 @@ func buildSnapshots(
 		if prevSnapshot != nil {
 			snapshot, hasChanges, err = updateSnapshot(prevSnapshot, resMap)
 			if err != nil {
-				errs = append(errs, err)
+				failNode(nodeID, err)
+				errs = append(errs, fmt.Errorf("node %s: %w", nodeID, err))
 				continue
 			}
+		} else if len(resMap) == 0 {
+			// An unmatched node is answered with an empty snapshot instead of hanging
+			hasChanges = true
+			snapshot = wrapped.NewEmptySnapshot()
 		} else {
 			hasChanges = true
-			snapshot, err = cache.NewSnapshot("1", resMap)
+			snapshot, err = wrapped.NewSnapshot(resMap, nil)
 			if err != nil {
-				errs = append(errs, err)
+				failNode(nodeID, err)
+				errs = append(errs, fmt.Errorf("node %s: %w", nodeID, err))
 				continue
 			}
 		}
 		if hasChanges {
 			if err := snapshot.Consistent(); err != nil {
-				errs = append(errs, err)
+				failNode(nodeID, err)
+				errs = append(errs, fmt.Errorf("node %s: %w", nodeID, err))
 				continue
 			}
 			// Stage for commit later
 			staged[nodeID] = snapshot
+		} else {
+			unchanged[nodeID] = struct{}{}
 		}
 		// Mark as not needing cleanup since we have a plan for this node
 		delete(nodeIDsForCleanup, nodeID)
 	}
-
-	// If any error occurred during staging, abort without mutating cache
-	if len(errs) > 0 {
-		return multierr.Combine(errs...), usedSecrets, vsStatuses, metrics
+	for nodeID := range failedNodes {
+		delete(nodeIDsForCleanup, nodeID)
 	}
+
 	// Abort on cancellation prior to commit to avoid partial state
 	if err := ctx.Err(); err != nil {
-		return err, usedSecrets, vsStatuses, metrics
+		return err, usedSecrets, vsStatuses, metrics, nil
 	}
 
 	// Commit phase: ensure we are not interrupted mid-commit
 	commitCtx := context.WithoutCancel(ctx)
 	for nodeID, snapshot := range staged {
 		if err := snapshotCache.SetSnapshot(commitCtx, nodeID, snapshot); err != nil {
-			errs = append(errs, err)
+			failNode(nodeID, err)
+			errs = append(errs, fmt.Errorf("node %s: %w", nodeID, err))
+			continue
 		}
+		snapshotCache.SetNodeHealthy(nodeID)
 	}
 	for nodeID := range nodeIDsForCleanup {
 		if err := snapshotCache.SetSnapshot(commitCtx, nodeID, &cache.Snapshot{}); err != nil {
-			errs = append(errs, err)
+			failNode(nodeID, err)
+			errs = append(errs, fmt.Errorf("node %s: %w", nodeID, err))
+			continue
 		}
+		snapshotCache.SetNodeHealthy(nodeID)
 	}
-
-	if len(errs) > 0 {
-		return multierr.Combine(errs...), usedSecrets, vsStatuses, metrics
+	for nodeID := range unchanged {
+		snapshotCache.SetNodeHealthy(nodeID)
+	}
+	for nodeID, err := range failedNodes {
+		snapshotCache.SetNodeFailed(nodeID, err)
+		inc.fail(nodeID)
+		// Failing nodes keep their previous owners
+		delete(mixer.owners, nodeID)
 	}
 
-	// Update Store domain index after successful commit (feature-flagged)
+	// Update Store domain index after commit (feature-flagged)
 	if buildDomainsIndex {
 		// Ensure index has entries (possibly empty) for all nodes we touched this rebuild
 		allNodes := make(map[string]struct{}, len(staged)+len(nodeIDsForCleanup))
@@ -588,11 +884,20 @@ func buildSnapshots(
 				nodeDomainsIndex[nodeID] = make(map[string]struct{})
 			}
 		}
+		// Failing nodes still serve the domains of their previous snapshots
+		prevIndex := store.GetNodeDomainsIndex()
+		for nodeID := range failedNodes {
+			if domains, ok := prevIndex[nodeID]; ok {
+				nodeDomainsIndex[nodeID] = domains
+			} else {
+				delete(nodeDomainsIndex, nodeID)
+			}
+		}
 		store.ReplaceNodeDomainsIndex(nodeDomainsIndex)
 	}
 
 	metrics.snapshotCreateDuration = time.Since(snapshotCreateStart)
-	return nil, usedSecrets, vsStatuses, metrics
+	return multierr.Combine(errs...), usedSecrets, vsStatuses, metrics, mixer.owners
 }
 
 func (c *CacheUpdater) GetUsedSecrets() map[helpers.NamespacedName]helpers.NamespacedName {
@@ -618,6 +923,22 @@ func (c *CacheUpdater) GetVirtualServiceWithStatus(nn helpers.NamespacedName) *v
 	return c.store.GetVirtualService(nn)
 }
 
+// GetVirtualServiceInvalidReason returns why the VirtualService was invalid in the last rebuild,
+// empty if it was valid or failed for another reason
+func (c *CacheUpdater) GetVirtualServiceInvalidReason(nn helpers.NamespacedName) InvalidReason {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	return c.vsStatuses[nn].Reason
+}
+
+// ResolveRejectedOwners returns VirtualServices that contributed resources of the given
+// type to the snapshot of the node. It is used to map Envoy NACKs back to VirtualServices.
+func (c *CacheUpdater) ResolveRejectedOwners(nodeID, typeURL, message string) []helpers.NamespacedName {
+	c.ownersMx.RLock()
+	defer c.ownersMx.RUnlock()
+	return c.resourceOwners.resolve(nodeID, typeURL, message)
+}
+
 func (c *CacheUpdater) GetMarshaledStore() ([]byte, error) {
 	c.mx.RLock()
 	defer c.mx.RUnlock()
@@ -678,56 +999,24 @@ func updateSnapshot(
 		return nil, false, errors.New("snapshot is nil")
 	}
 
+	// Versions are derived from content: unchanged resources keep their versions, so delta
+	// streams are sent only changed ones, and a type changes its version only if any changed
+	snapshot, err := wrapped.NewSnapshot(resources, prevSnapshot)
+	if err != nil {
+		return nil, false, err
+	}
 	hasChanges := false
-
-	snapshot := cache.Snapshot{}
-	for typ, res := range resources {
-		index := cache.GetResponseType(typ)
-		if index == types.UnknownType {
-			return nil, false, errors.New("unknown resource type: " + typ)
-		}
-
-		version := prevSnapshot.GetVersion(typ)
-		if version == "" {
-			version = "0"
+	for i := range snapshot.Resources {
+		typeURL, err := cache.GetResponseTypeURL(types.ResponseType(i))
+		if err != nil {
+			return nil, false, err
 		}
-
-		if checkResourcesChanged(prevSnapshot.GetResources(typ), res) {
+		if snapshot.GetVersion(typeURL) != prevSnapshot.GetVersion(typeURL) {
 			hasChanges = true
-			vInt, _ := strconv.Atoi(version)
-			vInt++
-			version = strconv.Itoa(vInt)
-		}
-
-		snapshot.Resources[index] = cache.NewResources(version, res)
-	}
-	return &snapshot, hasChanges, nil
-}
-
-func checkResourcesChanged(prevRes map[string]types.Resource, newRes []types.Resource) bool {
-	if len(prevRes) != len(newRes) {
-		return true
-	}
-	for _, newR := range newRes {
-		if val, ok := prevRes[getName(newR)]; ok {
-			if !proto.Equal(val, newR) {
-				return true
-			}
-		} else {
-			return true
-		}
-	}
-	return false
-}
-
-func getName(msg proto.Message) string {
-	msgDesc := msg.ProtoReflect().Descriptor()
-	for i := 0; i < msgDesc.Fields().Len(); i++ {
-		if msgDesc.Fields().Get(i).Name() == "name" {
-			return msg.ProtoReflect().Get(msgDesc.Fields().Get(i)).String()
+			break
 		}
 	}
-	return ""
+	return snapshot, hasChanges, nil
 }
 
 func isCommonVirtualService(nodeIDs []string) bool {
@@ -0,0 +1,57 @@
+package updater
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kaasops/envoy-xds-controller/internal/xds/resbuilder/utils"
+)
+
+// TestInvalidReasonOf verifies that build errors are classified by their type, also when wrapped
+func TestInvalidReasonOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want InvalidReason
+	}{
+		{
+			name: "template not found",
+			err:  utils.NewNotFoundError(utils.KindTemplate, "virtual service template default/tpl not found"),
+			want: InvalidReasonTemplateFailed,
+		},
+		{
+			name: "template failed to apply",
+			err:  &utils.TemplateError{Err: errors.New("unknown field")},
+			want: InvalidReasonTemplateFailed,
+		},
+		{
+			name: "listener not found",
+			err:  utils.NewNotFoundError(utils.KindListener, "listener default/http not found"),
+			want: InvalidReasonListenerNotFound,
+		},
+		{
+			name: "wrapped secret not found",
+			err: fmt.Errorf("failed to build: %w",
+				utils.NewNotFoundError(utils.KindSecret, "can't find secret for domain example.com")),
+			want: InvalidReasonSecretNotFound,
+		},
+		{
+			name: "cluster not found",
+			err:  utils.NewNotFoundError(utils.KindCluster, "mirror cluster default/missing not found"),
+			want: InvalidReasonClusterNotFound,
+		},
+		{
+			name: "message mentioning a cluster",
+			err:  errors.New("cluster default/missing not found"),
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := invalidReasonOf(tt.err); got != tt.want {
+				t.Errorf("invalidReasonOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
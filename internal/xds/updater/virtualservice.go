@@ -20,26 +20,29 @@ func (c *CacheUpdater) ApplyVirtualService(ctx context.Context, vs *v1alpha1.Vir
 	prevVS := c.store.GetVirtualService(nn)
 	if prevVS == nil {
 		c.store.SetVirtualService(vs)
-		_ = c.rebuildSnapshots(ctx)
+		_ = c.requestRebuild(ctx)
 		return
 	}
-	if prevVS.IsEqual(vs) {
+	// A new generation is rebuilt even with an equal spec, for the status to observe it
+	if prevVS.IsEqual(vs) && prevVS.Generation == vs.Generation {
 		rlog := log.FromContext(ctx).WithName("cache-updater")
 		rlog.V(1).Info("Skipping unchanged VirtualService", "namespace", vs.Namespace, "name", vs.Name)
 		return
 	}
 	c.store.SetVirtualService(vs)
-	_ = c.rebuildSnapshots(ctx)
+	_ = c.requestRebuild(ctx)
 }
 
 func (c *CacheUpdater) DeleteVirtualService(ctx context.Context, nn types.NamespacedName) error {
 	c.mx.Lock()
 	defer c.mx.Unlock()
-	if !c.store.IsExistingVirtualService(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name}) {
+	vsNN := helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name}
+	if !c.store.IsExistingVirtualService(vsNN) {
 		return nil
 	}
-	c.store.DeleteVirtualService(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
-	return c.rebuildSnapshots(ctx)
+	c.incremental.changeReferences(c.store.GetVirtualServiceDependencies(vsNN), nil)
+	c.store.DeleteVirtualService(vsNN)
+	return c.requestRebuild(ctx)
 }
 
 func (c *CacheUpdater) GetVirtualServicesByTemplate(vst *v1alpha1.VirtualServiceTemplate) []*v1alpha1.VirtualService {
@@ -47,3 +50,15 @@ func (c *CacheUpdater) GetVirtualServicesByTemplate(vst *v1alpha1.VirtualService
 	defer c.mx.RUnlock()
 	return c.store.GetVirtualServicesByTemplateNN(helpers.NamespacedName{Name: vst.Name, Namespace: vst.Namespace})
 }
+
+// ListVirtualServices returns names of all stored VirtualServices
+func (c *CacheUpdater) ListVirtualServices() []helpers.NamespacedName {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	vss := c.store.MapVirtualServices()
+	result := make([]helpers.NamespacedName, 0, len(vss))
+	for nn := range vss {
+		result = append(result, nn)
+	}
+	return result
+}
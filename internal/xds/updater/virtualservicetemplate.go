@@ -6,6 +6,7 @@ import (
 
 	"github.com/kaasops/envoy-xds-controller/api/v1alpha1"
 	"github.com/kaasops/envoy-xds-controller/internal/helpers"
+	"github.com/kaasops/envoy-xds-controller/internal/store"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -33,7 +34,8 @@ func (c *CacheUpdater) ApplyVirtualServiceTemplate(
 	prevVST := c.store.GetVirtualServiceTemplate(nn)
 	if prevVST == nil {
 		c.store.SetVirtualServiceTemplate(vst)
-		_ = c.rebuildSnapshots(ctx)
+		c.invalidateDependents(dependency(store.DependencyTemplate, vst.Namespace, vst.Name))
+		_ = c.requestRebuild(ctx)
 		return false
 	}
 	if prevVST.IsEqual(vst) {
@@ -41,7 +43,8 @@ func (c *CacheUpdater) ApplyVirtualServiceTemplate(
 		return false
 	}
 	c.store.SetVirtualServiceTemplate(vst)
-	_ = c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencyTemplate, vst.Namespace, vst.Name))
+	_ = c.requestRebuild(ctx)
 	return true
 }
 
@@ -60,5 +63,6 @@ func (c *CacheUpdater) DeleteVirtualServiceTemplate(ctx context.Context, nn type
 		return nil
 	}
 	c.store.DeleteVirtualServiceTemplate(helpers.NamespacedName{Namespace: nn.Namespace, Name: nn.Name})
-	return c.rebuildSnapshots(ctx)
+	c.invalidateDependents(dependency(store.DependencyTemplate, nn.Namespace, nn.Name))
+	return c.requestRebuild(ctx)
 }
@@ -36,12 +36,17 @@ const (
 	// NodeStoreServiceListNodesProcedure is the fully-qualified name of the NodeStoreService's
 	// ListNodes RPC.
 	NodeStoreServiceListNodesProcedure = "/node.v1.NodeStoreService/ListNodes"
+	// NodeStoreServiceListClientsProcedure is the fully-qualified name of the NodeStoreService's
+	// ListClients RPC.
+	NodeStoreServiceListClientsProcedure = "/node.v1.NodeStoreService/ListClients"
 )
 
 // NodeStoreServiceClient is a client for the node.v1.NodeStoreService service.
 type NodeStoreServiceClient interface {
 	// ListNodes retrieves a list of nodes belonging to the specified access group.
 	ListNodes(context.Context, *connect.Request[v1.ListNodesRequest]) (*connect.Response[v1.ListNodesResponse], error)
+	// ListClients retrieves the Envoy clients connected to this replica with the state of their subscriptions.
+	ListClients(context.Context, *connect.Request[v1.ListClientsRequest]) (*connect.Response[v1.ListClientsResponse], error)
 }
 
 // NewNodeStoreServiceClient constructs a client for the node.v1.NodeStoreService service. By
@@ -61,12 +66,19 @@ func NewNodeStoreServiceClient(httpClient connect.HTTPClient, baseURL string, op
 			connect.WithSchema(nodeStoreServiceMethods.ByName("ListNodes")),
 			connect.WithClientOptions(opts...),
 		),
+		listClients: connect.NewClient[v1.ListClientsRequest, v1.ListClientsResponse](
+			httpClient,
+			baseURL+NodeStoreServiceListClientsProcedure,
+			connect.WithSchema(nodeStoreServiceMethods.ByName("ListClients")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // nodeStoreServiceClient implements NodeStoreServiceClient.
 type nodeStoreServiceClient struct {
-	listNodes *connect.Client[v1.ListNodesRequest, v1.ListNodesResponse]
+	listNodes   *connect.Client[v1.ListNodesRequest, v1.ListNodesResponse]
+	listClients *connect.Client[v1.ListClientsRequest, v1.ListClientsResponse]
 }
 
 // ListNodes calls node.v1.NodeStoreService.ListNodes.
@@ -74,10 +86,17 @@ func (c *nodeStoreServiceClient) ListNodes(ctx context.Context, req *connect.Req
 	return c.listNodes.CallUnary(ctx, req)
 }
 
+// ListClients calls node.v1.NodeStoreService.ListClients.
+func (c *nodeStoreServiceClient) ListClients(ctx context.Context, req *connect.Request[v1.ListClientsRequest]) (*connect.Response[v1.ListClientsResponse], error) {
+	return c.listClients.CallUnary(ctx, req)
+}
+
 // NodeStoreServiceHandler is an implementation of the node.v1.NodeStoreService service.
 type NodeStoreServiceHandler interface {
 	// ListNodes retrieves a list of nodes belonging to the specified access group.
 	ListNodes(context.Context, *connect.Request[v1.ListNodesRequest]) (*connect.Response[v1.ListNodesResponse], error)
+	// ListClients retrieves the Envoy clients connected to this replica with the state of their subscriptions.
+	ListClients(context.Context, *connect.Request[v1.ListClientsRequest]) (*connect.Response[v1.ListClientsResponse], error)
 }
 
 // NewNodeStoreServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -93,10 +112,18 @@ func NewNodeStoreServiceHandler(svc NodeStoreServiceHandler, opts ...connect.Han
 		connect.WithSchema(nodeStoreServiceMethods.ByName("ListNodes")),
 		connect.WithHandlerOptions(opts...),
 	)
+	nodeStoreServiceListClientsHandler := connect.NewUnaryHandler(
+		NodeStoreServiceListClientsProcedure,
+		svc.ListClients,
+		connect.WithSchema(nodeStoreServiceMethods.ByName("ListClients")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/node.v1.NodeStoreService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case NodeStoreServiceListNodesProcedure:
 			nodeStoreServiceListNodesHandler.ServeHTTP(w, r)
+		case NodeStoreServiceListClientsProcedure:
+			nodeStoreServiceListClientsHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -109,3 +136,7 @@ type UnimplementedNodeStoreServiceHandler struct{}
 func (UnimplementedNodeStoreServiceHandler) ListNodes(context.Context, *connect.Request[v1.ListNodesRequest]) (*connect.Response[v1.ListNodesResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("node.v1.NodeStoreService.ListNodes is not implemented"))
 }
+
+func (UnimplementedNodeStoreServiceHandler) ListClients(context.Context, *connect.Request[v1.ListClientsRequest]) (*connect.Response[v1.ListClientsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("node.v1.NodeStoreService.ListClients is not implemented"))
+}
@@ -670,7 +670,9 @@ type GetVirtualServiceResponse struct {
 	// Extra fields
 	ExtraFields map[string]string `protobuf:"bytes,18,rep,name=extra_fields,json=extraFields,proto3" json:"extra_fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	// TLS config
-	TlsConfig     *v1.TLSConfig `protobuf:"bytes,19,opt,name=tls_config,json=tlsConfig,proto3" json:"tls_config,omitempty"`
+	TlsConfig *v1.TLSConfig `protobuf:"bytes,19,opt,name=tls_config,json=tlsConfig,proto3" json:"tls_config,omitempty"`
+	// Whether the current configuration was acknowledged by the proxies connected to the answering replica
+	SyncStatus    *SyncStatus `protobuf:"bytes,20,opt,name=sync_status,json=syncStatus,proto3" json:"sync_status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -849,6 +851,13 @@ func (x *GetVirtualServiceResponse) GetTlsConfig() *v1.TLSConfig {
 	return nil
 }
 
+func (x *GetVirtualServiceResponse) GetSyncStatus() *SyncStatus {
+	if x != nil {
+		return x.SyncStatus
+	}
+	return nil
+}
+
 type isGetVirtualServiceResponse_AccessLog interface {
 	isGetVirtualServiceResponse_AccessLog()
 }
@@ -867,6 +876,143 @@ func (*GetVirtualServiceResponse_AccessLogConfigs) isGetVirtualServiceResponse_A
 
 func (*GetVirtualServiceResponse_AccessLogConfigRaw) isGetVirtualServiceResponse_AccessLog() {}
 
+// SyncStatus describes whether the current configuration of a virtual service has reached its proxies.
+type SyncStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether all connected proxies acknowledged the current configuration.
+	Synced bool `protobuf:"varint,1,opt,name=synced,proto3" json:"synced,omitempty"`
+	// A summary such as "3/3 proxies acknowledged version 42".
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// The status per node the virtual service is served on.
+	Nodes         []*NodeSyncStatus `protobuf:"bytes,3,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncStatus) Reset() {
+	*x = SyncStatus{}
+	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncStatus) ProtoMessage() {}
+
+func (x *SyncStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncStatus.ProtoReflect.Descriptor instead.
+func (*SyncStatus) Descriptor() ([]byte, []int) {
+	return file_virtual_service_v1_virtual_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SyncStatus) GetSynced() bool {
+	if x != nil {
+		return x.Synced
+	}
+	return false
+}
+
+func (x *SyncStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SyncStatus) GetNodes() []*NodeSyncStatus {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+// NodeSyncStatus describes how many proxies of a node acknowledged the current configuration.
+type NodeSyncStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The node ID.
+	NodeId string `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	// The version of the node snapshot that first contained the current configuration.
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// The number of connected proxies that acknowledged the version or a later one.
+	Acknowledged uint32 `protobuf:"varint,3,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
+	// The number of connected proxies.
+	Total         uint32 `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeSyncStatus) Reset() {
+	*x = NodeSyncStatus{}
+	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeSyncStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeSyncStatus) ProtoMessage() {}
+
+func (x *NodeSyncStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeSyncStatus.ProtoReflect.Descriptor instead.
+func (*NodeSyncStatus) Descriptor() ([]byte, []int) {
+	return file_virtual_service_v1_virtual_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *NodeSyncStatus) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *NodeSyncStatus) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *NodeSyncStatus) GetAcknowledged() uint32 {
+	if x != nil {
+		return x.Acknowledged
+	}
+	return 0
+}
+
+func (x *NodeSyncStatus) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
 // ListVirtualServicesRequest is the request message for listing virtual services.
 type ListVirtualServicesRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -878,7 +1024,7 @@ type ListVirtualServicesRequest struct {
 
 func (x *ListVirtualServicesRequest) Reset() {
 	*x = ListVirtualServicesRequest{}
-	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[9]
+	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -890,7 +1036,7 @@ func (x *ListVirtualServicesRequest) String() string {
 func (*ListVirtualServicesRequest) ProtoMessage() {}
 
 func (x *ListVirtualServicesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[9]
+	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -903,7 +1049,7 @@ func (x *ListVirtualServicesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListVirtualServicesRequest.ProtoReflect.Descriptor instead.
 func (*ListVirtualServicesRequest) Descriptor() ([]byte, []int) {
-	return file_virtual_service_v1_virtual_service_proto_rawDescGZIP(), []int{9}
+	return file_virtual_service_v1_virtual_service_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ListVirtualServicesRequest) GetAccessGroup() string {
@@ -940,7 +1086,7 @@ type VirtualServiceListItem struct {
 
 func (x *VirtualServiceListItem) Reset() {
 	*x = VirtualServiceListItem{}
-	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[10]
+	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -952,7 +1098,7 @@ func (x *VirtualServiceListItem) String() string {
 func (*VirtualServiceListItem) ProtoMessage() {}
 
 func (x *VirtualServiceListItem) ProtoReflect() protoreflect.Message {
-	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[10]
+	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -965,7 +1111,7 @@ func (x *VirtualServiceListItem) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VirtualServiceListItem.ProtoReflect.Descriptor instead.
 func (*VirtualServiceListItem) Descriptor() ([]byte, []int) {
-	return file_virtual_service_v1_virtual_service_proto_rawDescGZIP(), []int{10}
+	return file_virtual_service_v1_virtual_service_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *VirtualServiceListItem) GetUid() string {
@@ -1042,7 +1188,7 @@ type ListVirtualServicesResponse struct {
 
 func (x *ListVirtualServicesResponse) Reset() {
 	*x = ListVirtualServicesResponse{}
-	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[11]
+	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1054,7 +1200,7 @@ func (x *ListVirtualServicesResponse) String() string {
 func (*ListVirtualServicesResponse) ProtoMessage() {}
 
 func (x *ListVirtualServicesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[11]
+	mi := &file_virtual_service_v1_virtual_service_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1067,7 +1213,7 @@ func (x *ListVirtualServicesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListVirtualServicesResponse.ProtoReflect.Descriptor instead.
 func (*ListVirtualServicesResponse) Descriptor() ([]byte, []int) {
-	return file_virtual_service_v1_virtual_service_proto_rawDescGZIP(), []int{11}
+	return file_virtual_service_v1_virtual_service_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ListVirtualServicesResponse) GetItems() []*VirtualServiceListItem {
@@ -1204,7 +1350,7 @@ var file_virtual_service_v1_virtual_service_proto_rawDesc = string([]byte{
 	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2c, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75,
 	0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
 	0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75,
-	0x69, 0x64, 0x22, 0xc6, 0x08, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
+	0x69, 0x64, 0x22, 0x87, 0x09, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
 	0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
 	0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75,
 	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
@@ -1266,107 +1412,127 @@ var file_virtual_service_v1_virtual_service_proto_rawDesc = string([]byte{
 	0x73, 0x12, 0x33, 0x0a, 0x0a, 0x74, 0x6c, 0x73, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18,
 	0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76,
 	0x31, 0x2e, 0x54, 0x4c, 0x53, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x09, 0x74, 0x6c, 0x73,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x1a, 0x3e, 0x0a, 0x10, 0x45, 0x78, 0x74, 0x72, 0x61, 0x46,
-	0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73,
-	0x5f, 0x6c, 0x6f, 0x67, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x75, 0x73, 0x65, 0x5f, 0x72, 0x65, 0x6d,
-	0x6f, 0x74, 0x65, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x3f, 0x0a, 0x1a, 0x4c,
-	0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x63, 0x63,
-	0x65, 0x73, 0x73, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x22, 0xc7, 0x03, 0x0a,
-	0x16, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4c,
-	0x69, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a,
-	0x08, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x07, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x63, 0x63, 0x65,
-	0x73, 0x73, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
-	0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x32, 0x0a, 0x08, 0x74,
-	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
-	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
-	0x63, 0x65, 0x52, 0x65, 0x66, 0x52, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12,
-	0x1f, 0x0a, 0x0b, 0x69, 0x73, 0x5f, 0x65, 0x64, 0x69, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x73, 0x45, 0x64, 0x69, 0x74, 0x61, 0x62, 0x6c, 0x65,
-	0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x08, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x5e, 0x0a, 0x0c, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f,
-	0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3b, 0x2e, 0x76,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x3f, 0x0a, 0x0b, 0x73, 0x79, 0x6e, 0x63, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x76, 0x69,
+	0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0a, 0x73, 0x79, 0x6e,
+	0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x1a, 0x3e, 0x0a, 0x10, 0x45, 0x78, 0x74, 0x72, 0x61,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x61, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x5f, 0x6c, 0x6f, 0x67, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x75, 0x73, 0x65, 0x5f, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x78, 0x0a, 0x0a,
+	0x53, 0x79, 0x6e, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79,
+	0x6e, 0x63, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x79, 0x6e, 0x63,
+	0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x38, 0x0a, 0x05,
+	0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x76, 0x69,
+	0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x22, 0x7d, 0x0a, 0x0e, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x79,
+	0x6e, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x61,
+	0x63, 0x6b, 0x6e, 0x6f, 0x77, 0x6c, 0x65, 0x64, 0x67, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0c, 0x61, 0x63, 0x6b, 0x6e, 0x6f, 0x77, 0x6c, 0x65, 0x64, 0x67, 0x65, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x3f, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72,
+	0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x22, 0xc7, 0x03, 0x0a, 0x16, 0x56, 0x69, 0x72, 0x74, 0x75,
+	0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x74, 0x65,
+	0x6d, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x5f,
+	0x69, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x49,
+	0x64, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x32, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x65, 0x66, 0x52,
+	0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x73, 0x5f,
+	0x65, 0x64, 0x69, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x69, 0x73, 0x45, 0x64, 0x69, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76,
 	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76,
-	0x31, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x4c, 0x69, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x2e, 0x45, 0x78, 0x74, 0x72, 0x61, 0x46, 0x69,
-	0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x65, 0x78, 0x74, 0x72, 0x61,
-	0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x1a, 0x3e, 0x0a, 0x10, 0x45, 0x78, 0x74, 0x72, 0x61, 0x46,
-	0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x5f, 0x0a, 0x1b, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69,
-	0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
-	0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d,
-	0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x32, 0xf7, 0x04, 0x0a, 0x1a, 0x56, 0x69, 0x72, 0x74,
-	0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x79, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x5e, 0x0a, 0x0c, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73,
+	0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3b, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x69, 0x72, 0x74,
+	0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x74,
+	0x65, 0x6d, 0x2e, 0x45, 0x78, 0x74, 0x72, 0x61, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x0b, 0x65, 0x78, 0x74, 0x72, 0x61, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73,
+	0x1a, 0x3e, 0x0a, 0x10, 0x45, 0x78, 0x74, 0x72, 0x61, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0x5f, 0x0a, 0x1b, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x40, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a,
+	0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d,
+	0x73, 0x32, 0xf7, 0x04, 0x0a, 0x1a, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x79, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
+	0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2f, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75,
+	0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x30, 0x2e, 0x76, 0x69, 0x72, 0x74,
+	0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x79, 0x0a, 0x14, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x2f, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x56,
+	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x30, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x79, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
 	0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2f,
 	0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
+	0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
 	0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
 	0x30, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75,
 	0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x79, 0x0a, 0x14, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75,
-	0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2f, 0x2e, 0x76, 0x69, 0x72, 0x74,
-	0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x55,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x30, 0x2e, 0x76, 0x69, 0x72,
+	0x65, 0x12, 0x70, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2c, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x56,
+	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72,
+	0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x76, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75,
+	0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x2e, 0x2e, 0x76, 0x69, 0x72,
 	0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x79, 0x0a, 0x14,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x12, 0x2f, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
-	0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x30, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f,
-	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x70, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x56, 0x69,
-	0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2c, 0x2e, 0x76,
-	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76,
-	0x31, 0x2e, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x76, 0x69, 0x72,
+	0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2f, 0x2e, 0x76, 0x69, 0x72,
 	0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e,
-	0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x76, 0x0a, 0x13, 0x4c, 0x69, 0x73,
-	0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73,
-	0x12, 0x2e, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
-	0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x2f, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
-	0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x42, 0xed, 0x01, 0x0a, 0x16, 0x63, 0x6f, 0x6d, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61,
-	0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x42, 0x13, 0x56, 0x69,
-	0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x74,
-	0x6f, 0x50, 0x01, 0x5a, 0x59, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x6b, 0x61, 0x61, 0x73, 0x6f, 0x70, 0x73, 0x2f, 0x65, 0x6e, 0x76, 0x6f, 0x79, 0x2d, 0x78, 0x64,
-	0x73, 0x2d, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x2f, 0x70, 0x6b, 0x67,
-	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61,
-	0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x76, 0x31, 0x3b, 0x76, 0x69, 0x72,
-	0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x76, 0x31, 0xa2, 0x02,
-	0x03, 0x56, 0x58, 0x58, 0xaa, 0x02, 0x11, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x11, 0x56, 0x69, 0x72, 0x74, 0x75,
-	0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x1d, 0x56,
-	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5c, 0x56, 0x31,
-	0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x12, 0x56,
-	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x3a, 0x3a, 0x56,
-	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0xed, 0x01, 0x0a, 0x16,
+	0x63, 0x6f, 0x6d, 0x2e, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x42, 0x13, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x59, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x61, 0x61, 0x73, 0x6f, 0x70,
+	0x73, 0x2f, 0x65, 0x6e, 0x76, 0x6f, 0x79, 0x2d, 0x78, 0x64, 0x73, 0x2d, 0x63, 0x6f, 0x6e, 0x74,
+	0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67,
+	0x72, 0x70, 0x63, 0x2f, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2f, 0x76, 0x31, 0x3b, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x56, 0x58, 0x58, 0xaa, 0x02,
+	0x11, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x56, 0x31, 0xca, 0x02, 0x11, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x1d, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5c, 0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x12, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 })
 
 var (
@@ -1381,7 +1547,7 @@ func file_virtual_service_v1_virtual_service_proto_rawDescGZIP() []byte {
 	return file_virtual_service_v1_virtual_service_proto_rawDescData
 }
 
-var file_virtual_service_v1_virtual_service_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_virtual_service_v1_virtual_service_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_virtual_service_v1_virtual_service_proto_goTypes = []any{
 	(*Status)(nil),                       // 0: virtual_service.v1.Status
 	(*CreateVirtualServiceRequest)(nil),  // 1: virtual_service.v1.CreateVirtualServiceRequest
@@ -1392,58 +1558,62 @@ var file_virtual_service_v1_virtual_service_proto_goTypes = []any{
 	(*DeleteVirtualServiceResponse)(nil), // 6: virtual_service.v1.DeleteVirtualServiceResponse
 	(*GetVirtualServiceRequest)(nil),     // 7: virtual_service.v1.GetVirtualServiceRequest
 	(*GetVirtualServiceResponse)(nil),    // 8: virtual_service.v1.GetVirtualServiceResponse
-	(*ListVirtualServicesRequest)(nil),   // 9: virtual_service.v1.ListVirtualServicesRequest
-	(*VirtualServiceListItem)(nil),       // 10: virtual_service.v1.VirtualServiceListItem
-	(*ListVirtualServicesResponse)(nil),  // 11: virtual_service.v1.ListVirtualServicesResponse
-	nil,                                  // 12: virtual_service.v1.CreateVirtualServiceRequest.ExtraFieldsEntry
-	nil,                                  // 13: virtual_service.v1.UpdateVirtualServiceRequest.ExtraFieldsEntry
-	nil,                                  // 14: virtual_service.v1.GetVirtualServiceResponse.ExtraFieldsEntry
-	nil,                                  // 15: virtual_service.v1.VirtualServiceListItem.ExtraFieldsEntry
-	(*v1.VirtualHost)(nil),               // 16: common.v1.VirtualHost
-	(*v1.UIDS)(nil),                      // 17: common.v1.UIDS
-	(*v11.TemplateOption)(nil),           // 18: virtual_service_template.v1.TemplateOption
-	(*v1.ResourceRef)(nil),               // 19: common.v1.ResourceRef
-	(*v1.ResourceRefs)(nil),              // 20: common.v1.ResourceRefs
-	(*v1.TLSConfig)(nil),                 // 21: common.v1.TLSConfig
+	(*SyncStatus)(nil),                   // 9: virtual_service.v1.SyncStatus
+	(*NodeSyncStatus)(nil),               // 10: virtual_service.v1.NodeSyncStatus
+	(*ListVirtualServicesRequest)(nil),   // 11: virtual_service.v1.ListVirtualServicesRequest
+	(*VirtualServiceListItem)(nil),       // 12: virtual_service.v1.VirtualServiceListItem
+	(*ListVirtualServicesResponse)(nil),  // 13: virtual_service.v1.ListVirtualServicesResponse
+	nil,                                  // 14: virtual_service.v1.CreateVirtualServiceRequest.ExtraFieldsEntry
+	nil,                                  // 15: virtual_service.v1.UpdateVirtualServiceRequest.ExtraFieldsEntry
+	nil,                                  // 16: virtual_service.v1.GetVirtualServiceResponse.ExtraFieldsEntry
+	nil,                                  // 17: virtual_service.v1.VirtualServiceListItem.ExtraFieldsEntry
+	(*v1.VirtualHost)(nil),               // 18: common.v1.VirtualHost
+	(*v1.UIDS)(nil),                      // 19: common.v1.UIDS
+	(*v11.TemplateOption)(nil),           // 20: virtual_service_template.v1.TemplateOption
+	(*v1.ResourceRef)(nil),               // 21: common.v1.ResourceRef
+	(*v1.ResourceRefs)(nil),              // 22: common.v1.ResourceRefs
+	(*v1.TLSConfig)(nil),                 // 23: common.v1.TLSConfig
 }
 var file_virtual_service_v1_virtual_service_proto_depIdxs = []int32{
-	16, // 0: virtual_service.v1.CreateVirtualServiceRequest.virtual_host:type_name -> common.v1.VirtualHost
-	17, // 1: virtual_service.v1.CreateVirtualServiceRequest.access_log_config_uids:type_name -> common.v1.UIDS
-	18, // 2: virtual_service.v1.CreateVirtualServiceRequest.template_options:type_name -> virtual_service_template.v1.TemplateOption
-	12, // 3: virtual_service.v1.CreateVirtualServiceRequest.extra_fields:type_name -> virtual_service.v1.CreateVirtualServiceRequest.ExtraFieldsEntry
-	16, // 4: virtual_service.v1.UpdateVirtualServiceRequest.virtual_host:type_name -> common.v1.VirtualHost
-	17, // 5: virtual_service.v1.UpdateVirtualServiceRequest.access_log_config_uids:type_name -> common.v1.UIDS
-	18, // 6: virtual_service.v1.UpdateVirtualServiceRequest.template_options:type_name -> virtual_service_template.v1.TemplateOption
-	13, // 7: virtual_service.v1.UpdateVirtualServiceRequest.extra_fields:type_name -> virtual_service.v1.UpdateVirtualServiceRequest.ExtraFieldsEntry
-	19, // 8: virtual_service.v1.GetVirtualServiceResponse.template:type_name -> common.v1.ResourceRef
-	19, // 9: virtual_service.v1.GetVirtualServiceResponse.listener:type_name -> common.v1.ResourceRef
-	16, // 10: virtual_service.v1.GetVirtualServiceResponse.virtual_host:type_name -> common.v1.VirtualHost
-	20, // 11: virtual_service.v1.GetVirtualServiceResponse.access_log_configs:type_name -> common.v1.ResourceRefs
-	19, // 12: virtual_service.v1.GetVirtualServiceResponse.additional_http_filters:type_name -> common.v1.ResourceRef
-	19, // 13: virtual_service.v1.GetVirtualServiceResponse.additional_routes:type_name -> common.v1.ResourceRef
-	18, // 14: virtual_service.v1.GetVirtualServiceResponse.template_options:type_name -> virtual_service_template.v1.TemplateOption
+	18, // 0: virtual_service.v1.CreateVirtualServiceRequest.virtual_host:type_name -> common.v1.VirtualHost
+	19, // 1: virtual_service.v1.CreateVirtualServiceRequest.access_log_config_uids:type_name -> common.v1.UIDS
+	20, // 2: virtual_service.v1.CreateVirtualServiceRequest.template_options:type_name -> virtual_service_template.v1.TemplateOption
+	14, // 3: virtual_service.v1.CreateVirtualServiceRequest.extra_fields:type_name -> virtual_service.v1.CreateVirtualServiceRequest.ExtraFieldsEntry
+	18, // 4: virtual_service.v1.UpdateVirtualServiceRequest.virtual_host:type_name -> common.v1.VirtualHost
+	19, // 5: virtual_service.v1.UpdateVirtualServiceRequest.access_log_config_uids:type_name -> common.v1.UIDS
+	20, // 6: virtual_service.v1.UpdateVirtualServiceRequest.template_options:type_name -> virtual_service_template.v1.TemplateOption
+	15, // 7: virtual_service.v1.UpdateVirtualServiceRequest.extra_fields:type_name -> virtual_service.v1.UpdateVirtualServiceRequest.ExtraFieldsEntry
+	21, // 8: virtual_service.v1.GetVirtualServiceResponse.template:type_name -> common.v1.ResourceRef
+	21, // 9: virtual_service.v1.GetVirtualServiceResponse.listener:type_name -> common.v1.ResourceRef
+	18, // 10: virtual_service.v1.GetVirtualServiceResponse.virtual_host:type_name -> common.v1.VirtualHost
+	22, // 11: virtual_service.v1.GetVirtualServiceResponse.access_log_configs:type_name -> common.v1.ResourceRefs
+	21, // 12: virtual_service.v1.GetVirtualServiceResponse.additional_http_filters:type_name -> common.v1.ResourceRef
+	21, // 13: virtual_service.v1.GetVirtualServiceResponse.additional_routes:type_name -> common.v1.ResourceRef
+	20, // 14: virtual_service.v1.GetVirtualServiceResponse.template_options:type_name -> virtual_service_template.v1.TemplateOption
 	0,  // 15: virtual_service.v1.GetVirtualServiceResponse.status:type_name -> virtual_service.v1.Status
-	14, // 16: virtual_service.v1.GetVirtualServiceResponse.extra_fields:type_name -> virtual_service.v1.GetVirtualServiceResponse.ExtraFieldsEntry
-	21, // 17: virtual_service.v1.GetVirtualServiceResponse.tls_config:type_name -> common.v1.TLSConfig
-	19, // 18: virtual_service.v1.VirtualServiceListItem.template:type_name -> common.v1.ResourceRef
-	0,  // 19: virtual_service.v1.VirtualServiceListItem.status:type_name -> virtual_service.v1.Status
-	15, // 20: virtual_service.v1.VirtualServiceListItem.extra_fields:type_name -> virtual_service.v1.VirtualServiceListItem.ExtraFieldsEntry
-	10, // 21: virtual_service.v1.ListVirtualServicesResponse.items:type_name -> virtual_service.v1.VirtualServiceListItem
-	1,  // 22: virtual_service.v1.VirtualServiceStoreService.CreateVirtualService:input_type -> virtual_service.v1.CreateVirtualServiceRequest
-	3,  // 23: virtual_service.v1.VirtualServiceStoreService.UpdateVirtualService:input_type -> virtual_service.v1.UpdateVirtualServiceRequest
-	5,  // 24: virtual_service.v1.VirtualServiceStoreService.DeleteVirtualService:input_type -> virtual_service.v1.DeleteVirtualServiceRequest
-	7,  // 25: virtual_service.v1.VirtualServiceStoreService.GetVirtualService:input_type -> virtual_service.v1.GetVirtualServiceRequest
-	9,  // 26: virtual_service.v1.VirtualServiceStoreService.ListVirtualServices:input_type -> virtual_service.v1.ListVirtualServicesRequest
-	2,  // 27: virtual_service.v1.VirtualServiceStoreService.CreateVirtualService:output_type -> virtual_service.v1.CreateVirtualServiceResponse
-	4,  // 28: virtual_service.v1.VirtualServiceStoreService.UpdateVirtualService:output_type -> virtual_service.v1.UpdateVirtualServiceResponse
-	6,  // 29: virtual_service.v1.VirtualServiceStoreService.DeleteVirtualService:output_type -> virtual_service.v1.DeleteVirtualServiceResponse
-	8,  // 30: virtual_service.v1.VirtualServiceStoreService.GetVirtualService:output_type -> virtual_service.v1.GetVirtualServiceResponse
-	11, // 31: virtual_service.v1.VirtualServiceStoreService.ListVirtualServices:output_type -> virtual_service.v1.ListVirtualServicesResponse
-	27, // [27:32] is the sub-list for method output_type
-	22, // [22:27] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	16, // 16: virtual_service.v1.GetVirtualServiceResponse.extra_fields:type_name -> virtual_service.v1.GetVirtualServiceResponse.ExtraFieldsEntry
+	23, // 17: virtual_service.v1.GetVirtualServiceResponse.tls_config:type_name -> common.v1.TLSConfig
+	9,  // 18: virtual_service.v1.GetVirtualServiceResponse.sync_status:type_name -> virtual_service.v1.SyncStatus
+	10, // 19: virtual_service.v1.SyncStatus.nodes:type_name -> virtual_service.v1.NodeSyncStatus
+	21, // 20: virtual_service.v1.VirtualServiceListItem.template:type_name -> common.v1.ResourceRef
+	0,  // 21: virtual_service.v1.VirtualServiceListItem.status:type_name -> virtual_service.v1.Status
+	17, // 22: virtual_service.v1.VirtualServiceListItem.extra_fields:type_name -> virtual_service.v1.VirtualServiceListItem.ExtraFieldsEntry
+	12, // 23: virtual_service.v1.ListVirtualServicesResponse.items:type_name -> virtual_service.v1.VirtualServiceListItem
+	1,  // 24: virtual_service.v1.VirtualServiceStoreService.CreateVirtualService:input_type -> virtual_service.v1.CreateVirtualServiceRequest
+	3,  // 25: virtual_service.v1.VirtualServiceStoreService.UpdateVirtualService:input_type -> virtual_service.v1.UpdateVirtualServiceRequest
+	5,  // 26: virtual_service.v1.VirtualServiceStoreService.DeleteVirtualService:input_type -> virtual_service.v1.DeleteVirtualServiceRequest
+	7,  // 27: virtual_service.v1.VirtualServiceStoreService.GetVirtualService:input_type -> virtual_service.v1.GetVirtualServiceRequest
+	11, // 28: virtual_service.v1.VirtualServiceStoreService.ListVirtualServices:input_type -> virtual_service.v1.ListVirtualServicesRequest
+	2,  // 29: virtual_service.v1.VirtualServiceStoreService.CreateVirtualService:output_type -> virtual_service.v1.CreateVirtualServiceResponse
+	4,  // 30: virtual_service.v1.VirtualServiceStoreService.UpdateVirtualService:output_type -> virtual_service.v1.UpdateVirtualServiceResponse
+	6,  // 31: virtual_service.v1.VirtualServiceStoreService.DeleteVirtualService:output_type -> virtual_service.v1.DeleteVirtualServiceResponse
+	8,  // 32: virtual_service.v1.VirtualServiceStoreService.GetVirtualService:output_type -> virtual_service.v1.GetVirtualServiceResponse
+	13, // 33: virtual_service.v1.VirtualServiceStoreService.ListVirtualServices:output_type -> virtual_service.v1.ListVirtualServicesResponse
+	29, // [29:34] is the sub-list for method output_type
+	24, // [24:29] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
 }
 
 func init() { file_virtual_service_v1_virtual_service_proto_init() }
@@ -1467,7 +1637,7 @@ func file_virtual_service_v1_virtual_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_virtual_service_v1_virtual_service_proto_rawDesc), len(file_virtual_service_v1_virtual_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   16,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
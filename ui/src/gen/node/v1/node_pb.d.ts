@@ -3,7 +3,8 @@
 /* eslint-disable */
 
 import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv1";
-import type { Message } from "@bufbuild/protobuf";
+import type { Timestamp } from "@bufbuild/protobuf/wkt";
+import type { JsonObject, Message } from "@bufbuild/protobuf";
 
 /**
  * Describes the file node/v1/node.proto.
@@ -70,6 +71,280 @@ export declare type ListNodesResponse = Message<"node.v1.ListNodesResponse"> & {
  */
 export declare const ListNodesResponseSchema: GenMessage<ListNodesResponse>;
 
+/**
+ * ListClientsRequest represents the request to list connected clients.
+ *
+ * @generated from message node.v1.ListClientsRequest
+ */
+export declare type ListClientsRequest = Message<"node.v1.ListClientsRequest"> & {
+  /**
+   * The access group to filter the clients by.
+   *
+   * @generated from field: string access_group = 1;
+   */
+  accessGroup: string;
+
+  /**
+   * The node ID to filter the clients by, all nodes if empty.
+   *
+   * @generated from field: string node_id = 2;
+   */
+  nodeId: string;
+};
+
+/**
+ * Describes the message node.v1.ListClientsRequest.
+ * Use `create(ListClientsRequestSchema)` to create a new message.
+ */
+export declare const ListClientsRequestSchema: GenMessage<ListClientsRequest>;
+
+/**
+ * ListClientsResponse represents the response containing the connected clients.
+ *
+ * @generated from message node.v1.ListClientsResponse
+ */
+export declare type ListClientsResponse = Message<"node.v1.ListClientsResponse"> & {
+  /**
+   * The list of connected clients sorted by stream ID.
+   *
+   * @generated from field: repeated node.v1.Client items = 1;
+   */
+  items: Client[];
+};
+
+/**
+ * Describes the message node.v1.ListClientsResponse.
+ * Use `create(ListClientsResponseSchema)` to create a new message.
+ */
+export declare const ListClientsResponseSchema: GenMessage<ListClientsResponse>;
+
+/**
+ * Client represents an xDS stream of a connected Envoy.
+ *
+ * @generated from message node.v1.Client
+ */
+export declare type Client = Message<"node.v1.Client"> & {
+  /**
+   * The identifier of the xDS stream.
+   *
+   * @generated from field: int64 stream_id = 1;
+   */
+  streamId: bigint;
+
+  /**
+   * The node ID of the Envoy.
+   *
+   * @generated from field: string node_id = 2;
+   */
+  nodeId: string;
+
+  /**
+   * The cluster of the Envoy.
+   *
+   * @generated from field: string cluster = 3;
+   */
+  cluster: string;
+
+  /**
+   * The address the Envoy connected from.
+   *
+   * @generated from field: string address = 4;
+   */
+  address: string;
+
+  /**
+   * The xDS protocol of the stream, sotw or delta.
+   *
+   * @generated from field: string protocol = 5;
+   */
+  protocol: string;
+
+  /**
+   * The Envoy build version.
+   *
+   * @generated from field: string envoy_version = 6;
+   */
+  envoyVersion: string;
+
+  /**
+   * The time the stream was opened.
+   *
+   * @generated from field: google.protobuf.Timestamp connected_at = 7;
+   */
+  connectedAt?: Timestamp;
+
+  /**
+   * The node metadata reported by the Envoy.
+   *
+   * @generated from field: google.protobuf.Struct metadata = 8;
+   */
+  metadata?: JsonObject;
+
+  /**
+   * The locality reported by the Envoy.
+   *
+   * @generated from field: node.v1.Locality locality = 9;
+   */
+  locality?: Locality;
+
+  /**
+   * The subscriptions of the stream sorted by type URL.
+   *
+   * @generated from field: repeated node.v1.Subscription subscriptions = 10;
+   */
+  subscriptions: Subscription[];
+
+  /**
+   * Indicates that the Envoy has not acknowledged the current version of a subscribed type.
+   *
+   * @generated from field: bool stale = 11;
+   */
+  stale: boolean;
+};
+
+/**
+ * Describes the message node.v1.Client.
+ * Use `create(ClientSchema)` to create a new message.
+ */
+export declare const ClientSchema: GenMessage<Client>;
+
+/**
+ * Locality represents the locality of an Envoy.
+ *
+ * @generated from message node.v1.Locality
+ */
+export declare type Locality = Message<"node.v1.Locality"> & {
+  /**
+   * The region of the Envoy.
+   *
+   * @generated from field: string region = 1;
+   */
+  region: string;
+
+  /**
+   * The zone of the Envoy.
+   *
+   * @generated from field: string zone = 2;
+   */
+  zone: string;
+
+  /**
+   * The sub-zone of the Envoy.
+   *
+   * @generated from field: string sub_zone = 3;
+   */
+  subZone: string;
+};
+
+/**
+ * Describes the message node.v1.Locality.
+ * Use `create(LocalitySchema)` to create a new message.
+ */
+export declare const LocalitySchema: GenMessage<Locality>;
+
+/**
+ * Subscription represents the state of a resource type requested on a stream.
+ *
+ * @generated from message node.v1.Subscription
+ */
+export declare type Subscription = Message<"node.v1.Subscription"> & {
+  /**
+   * The type URL of the resources.
+   *
+   * @generated from field: string type_url = 1;
+   */
+  typeUrl: string;
+
+  /**
+   * The requested resource names, empty for wildcard subscriptions.
+   *
+   * @generated from field: repeated string resource_names = 2;
+   */
+  resourceNames: string[];
+
+  /**
+   * The version of the last response sent to the Envoy.
+   *
+   * @generated from field: string sent_version = 3;
+   */
+  sentVersion: string;
+
+  /**
+   * The last version acknowledged by the Envoy.
+   *
+   * @generated from field: string acked_version = 4;
+   */
+  ackedVersion: string;
+
+  /**
+   * The time of the last acknowledgement.
+   *
+   * @generated from field: google.protobuf.Timestamp acked_at = 5;
+   */
+  ackedAt?: Timestamp;
+
+  /**
+   * The last response rejected by the Envoy.
+   *
+   * @generated from field: node.v1.Nack last_nack = 6;
+   */
+  lastNack?: Nack;
+
+  /**
+   * The version of the resource type in the current snapshot of the node.
+   *
+   * @generated from field: string current_version = 7;
+   */
+  currentVersion: string;
+
+  /**
+   * Indicates that the Envoy acknowledged the current version.
+   *
+   * @generated from field: bool synced = 8;
+   */
+  synced: boolean;
+};
+
+/**
+ * Describes the message node.v1.Subscription.
+ * Use `create(SubscriptionSchema)` to create a new message.
+ */
+export declare const SubscriptionSchema: GenMessage<Subscription>;
+
+/**
+ * Nack represents a response rejected by an Envoy.
+ *
+ * @generated from message node.v1.Nack
+ */
+export declare type Nack = Message<"node.v1.Nack"> & {
+  /**
+   * The rejected version.
+   *
+   * @generated from field: string version = 1;
+   */
+  version: string;
+
+  /**
+   * The error message reported by the Envoy.
+   *
+   * @generated from field: string message = 2;
+   */
+  message: string;
+
+  /**
+   * The time of the rejection.
+   *
+   * @generated from field: google.protobuf.Timestamp timestamp = 3;
+   */
+  timestamp?: Timestamp;
+};
+
+/**
+ * Describes the message node.v1.Nack.
+ * Use `create(NackSchema)` to create a new message.
+ */
+export declare const NackSchema: GenMessage<Nack>;
+
 /**
  * NodeStoreService provides operations for managing nodes.
  *
@@ -86,5 +361,15 @@ export declare const NodeStoreService: GenService<{
     input: typeof ListNodesRequestSchema;
     output: typeof ListNodesResponseSchema;
   },
+  /**
+   * ListClients retrieves the Envoy clients connected to this replica with the state of their subscriptions.
+   *
+   * @generated from rpc node.v1.NodeStoreService.ListClients
+   */
+  listClients: {
+    methodKind: "unary";
+    input: typeof ListClientsRequestSchema;
+    output: typeof ListClientsResponseSchema;
+  },
 }>;
 
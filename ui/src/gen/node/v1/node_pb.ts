@@ -4,13 +4,15 @@
 
 import type { GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv1";
 import { fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv1";
-import type { Message } from "@bufbuild/protobuf";
+import type { Timestamp } from "@bufbuild/protobuf/wkt";
+import { file_google_protobuf_struct, file_google_protobuf_timestamp } from "@bufbuild/protobuf/wkt";
+import type { JsonObject, Message } from "@bufbuild/protobuf";
 
 /**
  * Describes the file node/v1/node.proto.
  */
 export const file_node_v1_node: GenFile = /*@__PURE__*/
-  fileDesc("ChJub2RlL3YxL25vZGUucHJvdG8SB25vZGUudjEiGgoMTm9kZUxpc3RJdGVtEgoKAmlkGAEgASgJIigKEExpc3ROb2Rlc1JlcXVlc3QSFAoMYWNjZXNzX2dyb3VwGAEgASgJIjkKEUxpc3ROb2Rlc1Jlc3BvbnNlEiQKBWl0ZW1zGAEgAygLMhUubm9kZS52MS5Ob2RlTGlzdEl0ZW0yVgoQTm9kZVN0b3JlU2VydmljZRJCCglMaXN0Tm9kZXMSGS5ub2RlLnYxLkxpc3ROb2Rlc1JlcXVlc3QaGi5ub2RlLnYxLkxpc3ROb2Rlc1Jlc3BvbnNlQpoBCgtjb20ubm9kZS52MUIJTm9kZVByb3RvUAFaQ2dpdGh1Yi5jb20va2Fhc29wcy9lbnZveS14ZHMtY29udHJvbGxlci9wa2cvYXBpL2dycGMvbm9kZS92MTtub2RldjGiAgNOWFiqAgdOb2RlLlYxygIHTm9kZVxWMeICE05vZGVcVjFcR1BCTWV0YWRhdGHqAghOb2RlOjpWMWIGcHJvdG8z");
+  fileDesc("ChJub2RlL3YxL25vZGUucHJvdG8SB25vZGUudjEiGgoMTm9kZUxpc3RJdGVtEgoKAmlkGAEgASgJIigKEExpc3ROb2Rlc1JlcXVlc3QSFAoMYWNjZXNzX2dyb3VwGAEgASgJIjkKEUxpc3ROb2Rlc1Jlc3BvbnNlEiQKBWl0ZW1zGAEgAygLMhUubm9kZS52MS5Ob2RlTGlzdEl0ZW0iOwoSTGlzdENsaWVudHNSZXF1ZXN0EhQKDGFjY2Vzc19ncm91cBgBIAEoCRIPCgdub2RlX2lkGAIgASgJIjUKE0xpc3RDbGllbnRzUmVzcG9uc2USHgoFaXRlbXMYASADKAsyDy5ub2RlLnYxLkNsaWVudCK2AgoGQ2xpZW50EhEKCXN0cmVhbV9pZBgBIAEoAxIPCgdub2RlX2lkGAIgASgJEg8KB2NsdXN0ZXIYAyABKAkSDwoHYWRkcmVzcxgEIAEoCRIQCghwcm90b2NvbBgFIAEoCRIVCg1lbnZveV92ZXJzaW9uGAYgASgJEjAKDGNvbm5lY3RlZF9hdBgHIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASKQoIbWV0YWRhdGEYCCABKAsyFy5nb29nbGUucHJvdG9idWYuU3RydWN0EiMKCGxvY2FsaXR5GAkgASgLMhEubm9kZS52MS5Mb2NhbGl0eRIsCg1zdWJzY3JpcHRpb25zGAogAygLMhUubm9kZS52MS5TdWJzY3JpcHRpb24SDQoFc3RhbGUYCyABKAgiOgoITG9jYWxpdHkSDgoGcmVnaW9uGAEgASgJEgwKBHpvbmUYAiABKAkSEAoIc3ViX3pvbmUYAyABKAki3gEKDFN1YnNjcmlwdGlvbhIQCgh0eXBlX3VybBgBIAEoCRIWCg5yZXNvdXJjZV9uYW1lcxgCIAMoCRIUCgxzZW50X3ZlcnNpb24YAyABKAkSFQoNYWNrZWRfdmVyc2lvbhgEIAEoCRIsCghhY2tlZF9hdBgFIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASIAoJbGFzdF9uYWNrGAYgASgLMg0ubm9kZS52MS5OYWNrEhcKD2N1cnJlbnRfdmVyc2lvbhgHIAEoCRIOCgZzeW5jZWQYCCABKAgiVwoETmFjaxIPCgd2ZXJzaW9uGAEgASgJEg8KB21lc3NhZ2UYAiABKAkSLQoJdGltZXN0YW1wGAMgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcDKgAQoQTm9kZVN0b3JlU2VydmljZRJCCglMaXN0Tm9kZXMSGS5ub2RlLnYxLkxpc3ROb2Rlc1JlcXVlc3QaGi5ub2RlLnYxLkxpc3ROb2Rlc1Jlc3BvbnNlEkgKC0xpc3RDbGllbnRzEhsubm9kZS52MS5MaXN0Q2xpZW50c1JlcXVlc3QaHC5ub2RlLnYxLkxpc3RDbGllbnRzUmVzcG9uc2VCmgEKC2NvbS5ub2RlLnYxQglOb2RlUHJvdG9QAVpDZ2l0aHViLmNvbS9rYWFzb3BzL2Vudm95LXhkcy1jb250cm9sbGVyL3BrZy9hcGkvZ3JwYy9ub2RlL3YxO25vZGV2MaICA05YWKoCB05vZGUuVjHKAgdOb2RlXFYx4gITTm9kZVxWMVxHUEJNZXRhZGF0YeoCCE5vZGU6OlYxYgZwcm90bzM", [file_google_protobuf_struct, file_google_protobuf_timestamp]);
 
 /**
  * NodeListItem represents a node with its unique identifier.
@@ -75,6 +77,286 @@ export type ListNodesResponse = Message<"node.v1.ListNodesResponse"> & {
 export const ListNodesResponseSchema: GenMessage<ListNodesResponse> = /*@__PURE__*/
   messageDesc(file_node_v1_node, 2);
 
+/**
+ * ListClientsRequest represents the request to list connected clients.
+ *
+ * @generated from message node.v1.ListClientsRequest
+ */
+export type ListClientsRequest = Message<"node.v1.ListClientsRequest"> & {
+  /**
+   * The access group to filter the clients by.
+   *
+   * @generated from field: string access_group = 1;
+   */
+  accessGroup: string;
+
+  /**
+   * The node ID to filter the clients by, all nodes if empty.
+   *
+   * @generated from field: string node_id = 2;
+   */
+  nodeId: string;
+};
+
+/**
+ * Describes the message node.v1.ListClientsRequest.
+ * Use `create(ListClientsRequestSchema)` to create a new message.
+ */
+export const ListClientsRequestSchema: GenMessage<ListClientsRequest> = /*@__PURE__*/
+  messageDesc(file_node_v1_node, 3);
+
+/**
+ * ListClientsResponse represents the response containing the connected clients.
+ *
+ * @generated from message node.v1.ListClientsResponse
+ */
+export type ListClientsResponse = Message<"node.v1.ListClientsResponse"> & {
+  /**
+   * The list of connected clients sorted by stream ID.
+   *
+   * @generated from field: repeated node.v1.Client items = 1;
+   */
+  items: Client[];
+};
+
+/**
+ * Describes the message node.v1.ListClientsResponse.
+ * Use `create(ListClientsResponseSchema)` to create a new message.
+ */
+export const ListClientsResponseSchema: GenMessage<ListClientsResponse> = /*@__PURE__*/
+  messageDesc(file_node_v1_node, 4);
+
+/**
+ * Client represents an xDS stream of a connected Envoy.
+ *
+ * @generated from message node.v1.Client
+ */
+export type Client = Message<"node.v1.Client"> & {
+  /**
+   * The identifier of the xDS stream.
+   *
+   * @generated from field: int64 stream_id = 1;
+   */
+  streamId: bigint;
+
+  /**
+   * The node ID of the Envoy.
+   *
+   * @generated from field: string node_id = 2;
+   */
+  nodeId: string;
+
+  /**
+   * The cluster of the Envoy.
+   *
+   * @generated from field: string cluster = 3;
+   */
+  cluster: string;
+
+  /**
+   * The address the Envoy connected from.
+   *
+   * @generated from field: string address = 4;
+   */
+  address: string;
+
+  /**
+   * The xDS protocol of the stream, sotw or delta.
+   *
+   * @generated from field: string protocol = 5;
+   */
+  protocol: string;
+
+  /**
+   * The Envoy build version.
+   *
+   * @generated from field: string envoy_version = 6;
+   */
+  envoyVersion: string;
+
+  /**
+   * The time the stream was opened.
+   *
+   * @generated from field: google.protobuf.Timestamp connected_at = 7;
+   */
+  connectedAt?: Timestamp;
+
+  /**
+   * The node metadata reported by the Envoy.
+   *
+   * @generated from field: google.protobuf.Struct metadata = 8;
+   */
+  metadata?: JsonObject;
+
+  /**
+   * The locality reported by the Envoy.
+   *
+   * @generated from field: node.v1.Locality locality = 9;
+   */
+  locality?: Locality;
+
+  /**
+   * The subscriptions of the stream sorted by type URL.
+   *
+   * @generated from field: repeated node.v1.Subscription subscriptions = 10;
+   */
+  subscriptions: Subscription[];
+
+  /**
+   * Indicates that the Envoy has not acknowledged the current version of a subscribed type.
+   *
+   * @generated from field: bool stale = 11;
+   */
+  stale: boolean;
+};
+
+/**
+ * Describes the message node.v1.Client.
+ * Use `create(ClientSchema)` to create a new message.
+ */
+export const ClientSchema: GenMessage<Client> = /*@__PURE__*/
+  messageDesc(file_node_v1_node, 5);
+
+/**
+ * Locality represents the locality of an Envoy.
+ *
+ * @generated from message node.v1.Locality
+ */
+export type Locality = Message<"node.v1.Locality"> & {
+  /**
+   * The region of the Envoy.
+   *
+   * @generated from field: string region = 1;
+   */
+  region: string;
+
+  /**
+   * The zone of the Envoy.
+   *
+   * @generated from field: string zone = 2;
+   */
+  zone: string;
+
+  /**
+   * The sub-zone of the Envoy.
+   *
+   * @generated from field: string sub_zone = 3;
+   */
+  subZone: string;
+};
+
+/**
+ * Describes the message node.v1.Locality.
+ * Use `create(LocalitySchema)` to create a new message.
+ */
+export const LocalitySchema: GenMessage<Locality> = /*@__PURE__*/
+  messageDesc(file_node_v1_node, 6);
+
+/**
+ * Subscription represents the state of a resource type requested on a stream.
+ *
+ * @generated from message node.v1.Subscription
+ */
+export type Subscription = Message<"node.v1.Subscription"> & {
+  /**
+   * The type URL of the resources.
+   *
+   * @generated from field: string type_url = 1;
+   */
+  typeUrl: string;
+
+  /**
+   * The requested resource names, empty for wildcard subscriptions.
+   *
+   * @generated from field: repeated string resource_names = 2;
+   */
+  resourceNames: string[];
+
+  /**
+   * The version of the last response sent to the Envoy.
+   *
+   * @generated from field: string sent_version = 3;
+   */
+  sentVersion: string;
+
+  /**
+   * The last version acknowledged by the Envoy.
+   *
+   * @generated from field: string acked_version = 4;
+   */
+  ackedVersion: string;
+
+  /**
+   * The time of the last acknowledgement.
+   *
+   * @generated from field: google.protobuf.Timestamp acked_at = 5;
+   */
+  ackedAt?: Timestamp;
+
+  /**
+   * The last response rejected by the Envoy.
+   *
+   * @generated from field: node.v1.Nack last_nack = 6;
+   */
+  lastNack?: Nack;
+
+  /**
+   * The version of the resource type in the current snapshot of the node.
+   *
+   * @generated from field: string current_version = 7;
+   */
+  currentVersion: string;
+
+  /**
+   * Indicates that the Envoy acknowledged the current version.
+   *
+   * @generated from field: bool synced = 8;
+   */
+  synced: boolean;
+};
+
+/**
+ * Describes the message node.v1.Subscription.
+ * Use `create(SubscriptionSchema)` to create a new message.
+ */
+export const SubscriptionSchema: GenMessage<Subscription> = /*@__PURE__*/
+  messageDesc(file_node_v1_node, 7);
+
+/**
+ * Nack represents a response rejected by an Envoy.
+ *
+ * @generated from message node.v1.Nack
+ */
+export type Nack = Message<"node.v1.Nack"> & {
+  /**
+   * The rejected version.
+   *
+   * @generated from field: string version = 1;
+   */
+  version: string;
+
+  /**
+   * The error message reported by the Envoy.
+   *
+   * @generated from field: string message = 2;
+   */
+  message: string;
+
+  /**
+   * The time of the rejection.
+   *
+   * @generated from field: google.protobuf.Timestamp timestamp = 3;
+   */
+  timestamp?: Timestamp;
+};
+
+/**
+ * Describes the message node.v1.Nack.
+ * Use `create(NackSchema)` to create a new message.
+ */
+export const NackSchema: GenMessage<Nack> = /*@__PURE__*/
+  messageDesc(file_node_v1_node, 8);
+
 /**
  * NodeStoreService provides operations for managing nodes.
  *
@@ -91,6 +373,16 @@ export const NodeStoreService: GenService<{
     input: typeof ListNodesRequestSchema;
     output: typeof ListNodesResponseSchema;
   },
+  /**
+   * ListClients retrieves the Envoy clients connected to this replica with the state of their subscriptions.
+   *
+   * @generated from rpc node.v1.NodeStoreService.ListClients
+   */
+  listClients: {
+    methodKind: "unary";
+    input: typeof ListClientsRequestSchema;
+    output: typeof ListClientsResponseSchema;
+  },
 }> = /*@__PURE__*/
   serviceDesc(file_node_v1_node, 0);
 
@@ -479,6 +479,13 @@ export declare type GetVirtualServiceResponse = Message<"virtual_service.v1.GetV
    * @generated from field: common.v1.TLSConfig tls_config = 19;
    */
   tlsConfig?: TLSConfig;
+
+  /**
+   * Whether the current configuration was acknowledged by the proxies connected to the answering replica
+   *
+   * @generated from field: virtual_service.v1.SyncStatus sync_status = 20;
+   */
+  syncStatus?: SyncStatus;
 };
 
 /**
@@ -487,6 +494,81 @@ export declare type GetVirtualServiceResponse = Message<"virtual_service.v1.GetV
  */
 export declare const GetVirtualServiceResponseSchema: GenMessage<GetVirtualServiceResponse>;
 
+/**
+ * SyncStatus describes whether the current configuration of a virtual service has reached its proxies.
+ *
+ * @generated from message virtual_service.v1.SyncStatus
+ */
+export type SyncStatus = Message<"virtual_service.v1.SyncStatus"> & {
+  /**
+   * Whether all connected proxies acknowledged the current configuration.
+   *
+   * @generated from field: bool synced = 1;
+   */
+  synced: boolean;
+
+  /**
+   * A summary such as "3/3 proxies acknowledged version 42".
+   *
+   * @generated from field: string message = 2;
+   */
+  message: string;
+
+  /**
+   * The status per node the virtual service is served on.
+   *
+   * @generated from field: repeated virtual_service.v1.NodeSyncStatus nodes = 3;
+   */
+  nodes: NodeSyncStatus[];
+};
+
+/**
+ * Describes the message virtual_service.v1.SyncStatus.
+ * Use `create(SyncStatusSchema)` to create a new message.
+ */
+export declare const SyncStatusSchema: GenMessage<SyncStatus>;
+
+/**
+ * NodeSyncStatus describes how many proxies of a node acknowledged the current configuration.
+ *
+ * @generated from message virtual_service.v1.NodeSyncStatus
+ */
+export type NodeSyncStatus = Message<"virtual_service.v1.NodeSyncStatus"> & {
+  /**
+   * The node ID.
+   *
+   * @generated from field: string node_id = 1;
+   */
+  nodeId: string;
+
+  /**
+   * The version of the node snapshot that first contained the current configuration.
+   *
+   * @generated from field: string version = 2;
+   */
+  version: string;
+
+  /**
+   * The number of connected proxies that acknowledged the version or a later one.
+   *
+   * @generated from field: uint32 acknowledged = 3;
+   */
+  acknowledged: number;
+
+  /**
+   * The number of connected proxies.
+   *
+   * @generated from field: uint32 total = 4;
+   */
+  total: number;
+};
+
+/**
+ * Describes the message virtual_service.v1.NodeSyncStatus.
+ * Use `create(NodeSyncStatusSchema)` to create a new message.
+ */
+export declare const NodeSyncStatusSchema: GenMessage<NodeSyncStatus>;
+
 /**
  * ListVirtualServicesRequest is the request message for listing virtual services.
  *
@@ -14,7 +14,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file virtual_service/v1/virtual_service.proto.
  */
 export const file_virtual_service_v1_virtual_service: GenFile = /*@__PURE__*/
-  fileDesc("Cih2aXJ0dWFsX3NlcnZpY2UvdjEvdmlydHVhbF9zZXJ2aWNlLnByb3RvEhJ2aXJ0dWFsX3NlcnZpY2UudjEiKgoGU3RhdHVzEg8KB2ludmFsaWQYASABKAgSDwoHbWVzc2FnZRgCIAEoCSLZBAobQ3JlYXRlVmlydHVhbFNlcnZpY2VSZXF1ZXN0EgwKBG5hbWUYASABKAkSEAoIbm9kZV9pZHMYAiADKAkSFAoMYWNjZXNzX2dyb3VwGAMgASgJEhQKDHRlbXBsYXRlX3VpZBgEIAEoCRIUCgxsaXN0ZW5lcl91aWQYBSABKAkSLAoMdmlydHVhbF9ob3N0GAYgASgLMhYuY29tbW9uLnYxLlZpcnR1YWxIb3N0EjEKFmFjY2Vzc19sb2dfY29uZmlnX3VpZHMYByABKAsyDy5jb21tb24udjEuVUlEU0gAEiMKG2FkZGl0aW9uYWxfaHR0cF9maWx0ZXJfdWlkcxgIIAMoCRIdChVhZGRpdGlvbmFsX3JvdXRlX3VpZHMYCSADKAkSHwoSdXNlX3JlbW90ZV9hZGRyZXNzGAogASgISAGIAQESRQoQdGVtcGxhdGVfb3B0aW9ucxgLIAMoCzIrLnZpcnR1YWxfc2VydmljZV90ZW1wbGF0ZS52MS5UZW1wbGF0ZU9wdGlvbhITCgtkZXNjcmlwdGlvbhgMIAEoCRJWCgxleHRyYV9maWVsZHMYDSADKAsyQC52aXJ0dWFsX3NlcnZpY2UudjEuQ3JlYXRlVmlydHVhbFNlcnZpY2VSZXF1ZXN0LkV4dHJhRmllbGRzRW50cnkaMgoQRXh0cmFGaWVsZHNFbnRyeRILCgNrZXkYASABKAkSDQoFdmFsdWUYAiABKAk6AjgBQhMKEWFjY2Vzc19sb2dfY29uZmlnQhUKE191c2VfcmVtb3RlX2FkZHJlc3MiHgocQ3JlYXRlVmlydHVhbFNlcnZpY2VSZXNwb25zZSLCBAobVXBkYXRlVmlydHVhbFNlcnZpY2VSZXF1ZXN0EgsKA3VpZBgBIAEoCRIQCghub2RlX2lkcxgCIAMoCRIUCgx0ZW1wbGF0ZV91aWQYAyABKAkSFAoMbGlzdGVuZXJfdWlkGAQgASgJEiwKDHZpcnR1YWxfaG9zdBgFIAEoCzIWLmNvbW1vbi52MS5WaXJ0dWFsSG9zdBIxChZhY2Nlc3NfbG9nX2NvbmZpZ191aWRzGAYgASgLMg8uY29tbW9uLnYxLlVJRFNIABIjChthZGRpdGlvbmFsX2h0dHBfZmlsdGVyX3VpZHMYByADKAkSHQoVYWRkaXRpb25hbF9yb3V0ZV91aWRzGAggAygJEh8KEnVzZV9yZW1vdGVfYWRkcmVzcxgJIAEoCEgBiAEBEkUKEHRlbXBsYXRlX29wdGlvbnMYCiADKAsyKy52aXJ0dWFsX3NlcnZpY2VfdGVtcGxhdGUudjEuVGVtcGxhdGVPcHRpb24SEwoLZGVzY3JpcHRpb24YCyABKAkSVgoMZXh0cmFfZmllbGRzGAwgAygLMkAudmlydHVhbF9zZXJ2aWNlLnYxLlVwZGF0ZVZpcnR1YWxTZXJ2aWNlUmVxdWVzdC5FeHRyYUZpZWxkc0VudHJ5GjIKEEV4dHJhRmllbGRzRW50cnkSCwoDa2V5GAEgASgJEg0KBXZhbHVlGAIgASgJOgI4AUITChFhY2Nlc3NfbG9nX2NvbmZpZ0IVChNfdXNlX3JlbW90ZV9hZGRyZXNzIh4KHFVwZGF0ZVZpcnR1YWxTZXJ2aWNlUmVzcG9uc2UiKgobRGVsZXRlVmlydHVhbFNlcnZpY2VSZXF1ZXN0EgsKA3VpZBgBIAEoCSIeChxEZWxldGVWaXJ0dWFsU2VydmljZVJlc3BvbnNlIicKGEdldFZpcnR1YWxTZXJ2aWNlUmVxdWVzdBILCgN1aWQYASABKAkiyAYKGUdldFZpcnR1YWxTZXJ2aWNlUmVzcG9uc2USCwoDdWlkGAEgASgJEgwKBG5hbWUYAiABKAkSEAoIbm9kZV9pZHMYAyADKAkSFAoMYWNjZXNzX2dyb3VwGAQgASgJEigKCHRlbXBsYXRlGAUgASgLMhYuY29tbW9uLnYxLlJlc291cmNlUmVmEigKCGxpc3RlbmVyGAYgASgLMhYuY29tbW9uLnYxLlJlc291cmNlUmVmEiwKDHZpcnR1YWxfaG9zdBgHIAEoCzIWLmNvbW1vbi52MS5WaXJ0dWFsSG9zdBI1ChJhY2Nlc3NfbG9nX2NvbmZpZ3MYCCABKAsyFy5jb21tb24udjEuUmVzb3VyY2VSZWZzSAASHwoVYWNjZXNzX2xvZ19jb25maWdfcmF3GAkgASgJSAASNwoXYWRkaXRpb25hbF9odHRwX2ZpbHRlcnMYCiADKAsyFi5jb21tb24udjEuUmVzb3VyY2VSZWYSMQoRYWRkaXRpb25hbF9yb3V0ZXMYCyADKAsyFi5jb21tb24udjEuUmVzb3VyY2VSZWYSHwoSdXNlX3JlbW90ZV9hZGRyZXNzGAwgASgISAGIAQESRQoQdGVtcGxhdGVfb3B0aW9ucxgNIAMoCzIrLnZpcnR1YWxfc2VydmljZV90ZW1wbGF0ZS52MS5UZW1wbGF0ZU9wdGlvbhITCgtpc19lZGl0YWJsZRgOIAEoCBITCgtkZXNjcmlwdGlvbhgPIAEoCRILCgNyYXcYECABKAkSKgoGc3RhdHVzGBEgASgLMhoudmlydHVhbF9zZXJ2aWNlLnYxLlN0YXR1cxJUCgxleHRyYV9maWVsZHMYEiADKAsyPi52aXJ0dWFsX3NlcnZpY2UudjEuR2V0VmlydHVhbFNlcnZpY2VSZXNwb25zZS5FeHRyYUZpZWxkc0VudHJ5EigKCnRsc19jb25maWcYEyABKAsyFC5jb21tb24udjEuVExTQ29uZmlnGjIKEEV4dHJhRmllbGRzRW50cnkSCwoDa2V5GAEgASgJEg0KBXZhbHVlGAIgASgJOgI4AUIMCgphY2Nlc3NfbG9nQhUKE191c2VfcmVtb3RlX2FkZHJlc3MiMgoaTGlzdFZpcnR1YWxTZXJ2aWNlc1JlcXVlc3QSFAoMYWNjZXNzX2dyb3VwGAEgASgJIuICChZWaXJ0dWFsU2VydmljZUxpc3RJdGVtEgsKA3VpZBgBIAEoCRIMCgRuYW1lGAIgASgJEhAKCG5vZGVfaWRzGAMgAygJEhQKDGFjY2Vzc19ncm91cBgEIAEoCRIoCgh0ZW1wbGF0ZRgFIAEoCzIWLmNvbW1vbi52MS5SZXNvdXJjZVJlZhITCgtpc19lZGl0YWJsZRgGIAEoCBITCgtkZXNjcmlwdGlvbhgHIAEoCRIqCgZzdGF0dXMYCCABKAsyGi52aXJ0dWFsX3NlcnZpY2UudjEuU3RhdHVzElEKDGV4dHJhX2ZpZWxkcxgJIAMoCzI7LnZpcnR1YWxfc2VydmljZS52MS5WaXJ0dWFsU2VydmljZUxpc3RJdGVtLkV4dHJhRmllbGRzRW50cnkaMgoQRXh0cmFGaWVsZHNFbnRyeRILCgNrZXkYASABKAkSDQoFdmFsdWUYAiABKAk6AjgBIlgKG0xpc3RWaXJ0dWFsU2VydmljZXNSZXNwb25zZRI5CgVpdGVtcxgBIAMoCzIqLnZpcnR1YWxfc2VydmljZS52MS5WaXJ0dWFsU2VydmljZUxpc3RJdGVtMvcEChpWaXJ0dWFsU2VydmljZVN0b3JlU2VydmljZRJ5ChRDcmVhdGVWaXJ0dWFsU2VydmljZRIvLnZpcnR1YWxfc2VydmljZS52MS5DcmVhdGVWaXJ0dWFsU2VydmljZVJlcXVlc3QaMC52aXJ0dWFsX3NlcnZpY2UudjEuQ3JlYXRlVmlydHVhbFNlcnZpY2VSZXNwb25zZRJ5ChRVcGRhdGVWaXJ0dWFsU2VydmljZRIvLnZpcnR1YWxfc2VydmljZS52MS5VcGRhdGVWaXJ0dWFsU2VydmljZVJlcXVlc3QaMC52aXJ0dWFsX3NlcnZpY2UudjEuVXBkYXRlVmlydHVhbFNlcnZpY2VSZXNwb25zZRJ5ChREZWxldGVWaXJ0dWFsU2VydmljZRIvLnZpcnR1YWxfc2VydmljZS52MS5EZWxldGVWaXJ0dWFsU2VydmljZVJlcXVlc3QaMC52aXJ0dWFsX3NlcnZpY2UudjEuRGVsZXRlVmlydHVhbFNlcnZpY2VSZXNwb25zZRJwChFHZXRWaXJ0dWFsU2VydmljZRIsLnZpcnR1YWxfc2VydmljZS52MS5HZXRWaXJ0dWFsU2VydmljZVJlcXVlc3QaLS52aXJ0dWFsX3NlcnZpY2UudjEuR2V0VmlydHVhbFNlcnZpY2VSZXNwb25zZRJ2ChNMaXN0VmlydHVhbFNlcnZpY2VzEi4udmlydHVhbF9zZXJ2aWNlLnYxLkxpc3RWaXJ0dWFsU2VydmljZXNSZXF1ZXN0Gi8udmlydHVhbF9zZXJ2aWNlLnYxLkxpc3RWaXJ0dWFsU2VydmljZXNSZXNwb25zZULtAQoWY29tLnZpcnR1YWxfc2VydmljZS52MUITVmlydHVhbFNlcnZpY2VQcm90b1ABWllnaXRodWIuY29tL2thYXNvcHMvZW52b3kteGRzLWNvbnRyb2xsZXIvcGtnL2FwaS9ncnBjL3ZpcnR1YWxfc2VydmljZS92MTt2aXJ0dWFsX3NlcnZpY2V2MaICA1ZYWKoCEVZpcnR1YWxTZXJ2aWNlLlYxygIRVmlydHVhbFNlcnZpY2VcVjHiAh1WaXJ0dWFsU2VydmljZVxWMVxHUEJNZXRhZGF0YeoCElZpcnR1YWxTZXJ2aWNlOjpWMWIGcHJvdG8z", [file_common_v1_common, file_virtual_service_template_v1_virtual_service_template]);
+  fileDesc("Cih2aXJ0dWFsX3NlcnZpY2UvdjEvdmlydHVhbF9zZXJ2aWNlLnByb3RvEhJ2aXJ0dWFsX3NlcnZpY2UudjEiKgoGU3RhdHVzEg8KB2ludmFsaWQYASABKAgSDwoHbWVzc2FnZRgCIAEoCSLZBAobQ3JlYXRlVmlydHVhbFNlcnZpY2VSZXF1ZXN0EgwKBG5hbWUYASABKAkSEAoIbm9kZV9pZHMYAiADKAkSFAoMYWNjZXNzX2dyb3VwGAMgASgJEhQKDHRlbXBsYXRlX3VpZBgEIAEoCRIUCgxsaXN0ZW5lcl91aWQYBSABKAkSLAoMdmlydHVhbF9ob3N0GAYgASgLMhYuY29tbW9uLnYxLlZpcnR1YWxIb3N0EjEKFmFjY2Vzc19sb2dfY29uZmlnX3VpZHMYByABKAsyDy5jb21tb24udjEuVUlEU0gAEiMKG2FkZGl0aW9uYWxfaHR0cF9maWx0ZXJfdWlkcxgIIAMoCRIdChVhZGRpdGlvbmFsX3JvdXRlX3VpZHMYCSADKAkSHwoSdXNlX3JlbW90ZV9hZGRyZXNzGAogASgISAGIAQESRQoQdGVtcGxhdGVfb3B0aW9ucxgLIAMoCzIrLnZpcnR1YWxfc2VydmljZV90ZW1wbGF0ZS52MS5UZW1wbGF0ZU9wdGlvbhITCgtkZXNjcmlwdGlvbhgMIAEoCRJWCgxleHRyYV9maWVsZHMYDSADKAsyQC52aXJ0dWFsX3NlcnZpY2UudjEuQ3JlYXRlVmlydHVhbFNlcnZpY2VSZXF1ZXN0LkV4dHJhRmllbGRzRW50cnkaMgoQRXh0cmFGaWVsZHNFbnRyeRILCgNrZXkYASABKAkSDQoFdmFsdWUYAiABKAk6AjgBQhMKEWFjY2Vzc19sb2dfY29uZmlnQhUKE191c2VfcmVtb3RlX2FkZHJlc3MiHgocQ3JlYXRlVmlydHVhbFNlcnZpY2VSZXNwb25zZSLCBAobVXBkYXRlVmlydHVhbFNlcnZpY2VSZXF1ZXN0EgsKA3VpZBgBIAEoCRIQCghub2RlX2lkcxgCIAMoCRIUCgx0ZW1wbGF0ZV91aWQYAyABKAkSFAoMbGlzdGVuZXJfdWlkGAQgASgJEiwKDHZpcnR1YWxfaG9zdBgFIAEoCzIWLmNvbW1vbi52MS5WaXJ0dWFsSG9zdBIxChZhY2Nlc3NfbG9nX2NvbmZpZ191aWRzGAYgASgLMg8uY29tbW9uLnYxLlVJRFNIABIjChthZGRpdGlvbmFsX2h0dHBfZmlsdGVyX3VpZHMYByADKAkSHQoVYWRkaXRpb25hbF9yb3V0ZV91aWRzGAggAygJEh8KEnVzZV9yZW1vdGVfYWRkcmVzcxgJIAEoCEgBiAEBEkUKEHRlbXBsYXRlX29wdGlvbnMYCiADKAsyKy52aXJ0dWFsX3NlcnZpY2VfdGVtcGxhdGUudjEuVGVtcGxhdGVPcHRpb24SEwoLZGVzY3JpcHRpb24YCyABKAkSVgoMZXh0cmFfZmllbGRzGAwgAygLMkAudmlydHVhbF9zZXJ2aWNlLnYxLlVwZGF0ZVZpcnR1YWxTZXJ2aWNlUmVxdWVzdC5FeHRyYUZpZWxkc0VudHJ5GjIKEEV4dHJhRmllbGRzRW50cnkSCwoDa2V5GAEgASgJEg0KBXZhbHVlGAIgASgJOgI4AUITChFhY2Nlc3NfbG9nX2NvbmZpZ0IVChNfdXNlX3JlbW90ZV9hZGRyZXNzIh4KHFVwZGF0ZVZpcnR1YWxTZXJ2aWNlUmVzcG9uc2UiKgobRGVsZXRlVmlydHVhbFNlcnZpY2VSZXF1ZXN0EgsKA3VpZBgBIAEoCSIeChxEZWxldGVWaXJ0dWFsU2VydmljZVJlc3BvbnNlIicKGEdldFZpcnR1YWxTZXJ2aWNlUmVxdWVzdBILCgN1aWQYASABKAki/QYKGUdldFZpcnR1YWxTZXJ2aWNlUmVzcG9uc2USCwoDdWlkGAEgASgJEgwKBG5hbWUYAiABKAkSEAoIbm9kZV9pZHMYAyADKAkSFAoMYWNjZXNzX2dyb3VwGAQgASgJEigKCHRlbXBsYXRlGAUgASgLMhYuY29tbW9uLnYxLlJlc291cmNlUmVmEigKCGxpc3RlbmVyGAYgASgLMhYuY29tbW9uLnYxLlJlc291cmNlUmVmEiwKDHZpcnR1YWxfaG9zdBgHIAEoCzIWLmNvbW1vbi52MS5WaXJ0dWFsSG9zdBI1ChJhY2Nlc3NfbG9nX2NvbmZpZ3MYCCABKAsyFy5jb21tb24udjEuUmVzb3VyY2VSZWZzSAASHwoVYWNjZXNzX2xvZ19jb25maWdfcmF3GAkgASgJSAASNwoXYWRkaXRpb25hbF9odHRwX2ZpbHRlcnMYCiADKAsyFi5jb21tb24udjEuUmVzb3VyY2VSZWYSMQoRYWRkaXRpb25hbF9yb3V0ZXMYCyADKAsyFi5jb21tb24udjEuUmVzb3VyY2VSZWYSHwoSdXNlX3JlbW90ZV9hZGRyZXNzGAwgASgISAGIAQESRQoQdGVtcGxhdGVfb3B0aW9ucxgNIAMoCzIrLnZpcnR1YWxfc2VydmljZV90ZW1wbGF0ZS52MS5UZW1wbGF0ZU9wdGlvbhITCgtpc19lZGl0YWJsZRgOIAEoCBITCgtkZXNjcmlwdGlvbhgPIAEoCRILCgNyYXcYECABKAkSKgoGc3RhdHVzGBEgASgLMhoudmlydHVhbF9zZXJ2aWNlLnYxLlN0YXR1cxJUCgxleHRyYV9maWVsZHMYEiADKAsyPi52aXJ0dWFsX3NlcnZpY2UudjEuR2V0VmlydHVhbFNlcnZpY2VSZXNwb25zZS5FeHRyYUZpZWxkc0VudHJ5EigKCnRsc19jb25maWcYEyABKAsyFC5jb21tb24udjEuVExTQ29uZmlnEjMKC3N5bmNfc3RhdHVzGBQgASgLMh4udmlydHVhbF9zZXJ2aWNlLnYxLlN5bmNTdGF0dXMaMgoQRXh0cmFGaWVsZHNFbnRyeRILCgNrZXkYASABKAkSDQoFdmFsdWUYAiABKAk6AjgBQgwKCmFjY2Vzc19sb2dCFQoTX3VzZV9yZW1vdGVfYWRkcmVzcyJgCgpTeW5jU3RhdHVzEg4KBnN5bmNlZBgBIAEoCBIPCgdtZXNzYWdlGAIgASgJEjEKBW5vZGVzGAMgAygLMiIudmlydHVhbF9zZXJ2aWNlLnYxLk5vZGVTeW5jU3RhdHVzIlcKDk5vZGVTeW5jU3RhdHVzEg8KB25vZGVfaWQYASABKAkSDwoHdmVyc2lvbhgCIAEoCRIUCgxhY2tub3dsZWRnZWQYAyABKA0SDQoFdG90YWwYBCABKA0iMgoaTGlzdFZpcnR1YWxTZXJ2aWNlc1JlcXVlc3QSFAoMYWNjZXNzX2dyb3VwGAEgASgJIuICChZWaXJ0dWFsU2VydmljZUxpc3RJdGVtEgsKA3VpZBgBIAEoCRIMCgRuYW1lGAIgASgJEhAKCG5vZGVfaWRzGAMgAygJEhQKDGFjY2Vzc19ncm91cBgEIAEoCRIoCgh0ZW1wbGF0ZRgFIAEoCzIWLmNvbW1vbi52MS5SZXNvdXJjZVJlZhITCgtpc19lZGl0YWJsZRgGIAEoCBITCgtkZXNjcmlwdGlvbhgHIAEoCRIqCgZzdGF0dXMYCCABKAsyGi52aXJ0dWFsX3NlcnZpY2UudjEuU3RhdHVzElEKDGV4dHJhX2ZpZWxkcxgJIAMoCzI7LnZpcnR1YWxfc2VydmljZS52MS5WaXJ0dWFsU2VydmljZUxpc3RJdGVtLkV4dHJhRmllbGRzRW50cnkaMgoQRXh0cmFGaWVsZHNFbnRyeRILCgNrZXkYASABKAkSDQoFdmFsdWUYAiABKAk6AjgBIlgKG0xpc3RWaXJ0dWFsU2VydmljZXNSZXNwb25zZRI5CgVpdGVtcxgBIAMoCzIqLnZpcnR1YWxfc2VydmljZS52MS5WaXJ0dWFsU2VydmljZUxpc3RJdGVtMvcEChpWaXJ0dWFsU2VydmljZVN0b3JlU2VydmljZRJ5ChRDcmVhdGVWaXJ0dWFsU2VydmljZRIvLnZpcnR1YWxfc2VydmljZS52MS5DcmVhdGVWaXJ0dWFsU2VydmljZVJlcXVlc3QaMC52aXJ0dWFsX3NlcnZpY2UudjEuQ3JlYXRlVmlydHVhbFNlcnZpY2VSZXNwb25zZRJ5ChRVcGRhdGVWaXJ0dWFsU2VydmljZRIvLnZpcnR1YWxfc2VydmljZS52MS5VcGRhdGVWaXJ0dWFsU2VydmljZVJlcXVlc3QaMC52aXJ0dWFsX3NlcnZpY2UudjEuVXBkYXRlVmlydHVhbFNlcnZpY2VSZXNwb25zZRJ5ChREZWxldGVWaXJ0dWFsU2VydmljZRIvLnZpcnR1YWxfc2VydmljZS52MS5EZWxldGVWaXJ0dWFsU2VydmljZVJlcXVlc3QaMC52aXJ0dWFsX3NlcnZpY2UudjEuRGVsZXRlVmlydHVhbFNlcnZpY2VSZXNwb25zZRJwChFHZXRWaXJ0dWFsU2VydmljZRIsLnZpcnR1YWxfc2VydmljZS52MS5HZXRWaXJ0dWFsU2VydmljZVJlcXVlc3QaLS52aXJ0dWFsX3NlcnZpY2UudjEuR2V0VmlydHVhbFNlcnZpY2VSZXNwb25zZRJ2ChNMaXN0VmlydHVhbFNlcnZpY2VzEi4udmlydHVhbF9zZXJ2aWNlLnYxLkxpc3RWaXJ0dWFsU2VydmljZXNSZXF1ZXN0Gi8udmlydHVhbF9zZXJ2aWNlLnYxLkxpc3RWaXJ0dWFsU2VydmljZXNSZXNwb25zZULtAQoWY29tLnZpcnR1YWxfc2VydmljZS52MUITVmlydHVhbFNlcnZpY2VQcm90b1ABWllnaXRodWIuY29tL2thYXNvcHMvZW52b3kteGRzLWNvbnRyb2xsZXIvcGtnL2FwaS9ncnBjL3ZpcnR1YWxfc2VydmljZS92MTt2aXJ0dWFsX3NlcnZpY2V2MaICA1ZYWKoCEVZpcnR1YWxTZXJ2aWNlLlYxygIRVmlydHVhbFNlcnZpY2VcVjHiAh1WaXJ0dWFsU2VydmljZVxWMVxHUEJNZXRhZGF0YeoCElZpcnR1YWxTZXJ2aWNlOjpWMWIGcHJvdG8z", [file_common_v1_common, file_virtual_service_template_v1_virtual_service_template]);
 
 /**
  * @generated from message virtual_service.v1.Status
@@ -491,6 +491,13 @@ export type GetVirtualServiceResponse = Message<"virtual_service.v1.GetVirtualSe
    * @generated from field: common.v1.TLSConfig tls_config = 19;
    */
   tlsConfig?: TLSConfig;
+
+  /**
+   * Whether the current configuration was acknowledged by the proxies connected to the answering replica
+   *
+   * @generated from field: virtual_service.v1.SyncStatus sync_status = 20;
+   */
+  syncStatus?: SyncStatus;
 };
 
 /**
@@ -500,6 +507,83 @@ export type GetVirtualServiceResponse = Message<"virtual_service.v1.GetVirtualSe
 export const GetVirtualServiceResponseSchema: GenMessage<GetVirtualServiceResponse> = /*@__PURE__*/
   messageDesc(file_virtual_service_v1_virtual_service, 8);
 
+/**
+ * SyncStatus describes whether the current configuration of a virtual service has reached its proxies.
+ *
+ * @generated from message virtual_service.v1.SyncStatus
+ */
+export type SyncStatus = Message<"virtual_service.v1.SyncStatus"> & {
+  /**
+   * Whether all connected proxies acknowledged the current configuration.
+   *
+   * @generated from field: bool synced = 1;
+   */
+  synced: boolean;
+
+  /**
+   * A summary such as "3/3 proxies acknowledged version 42".
+   *
+   * @generated from field: string message = 2;
+   */
+  message: string;
+
+  /**
+   * The status per node the virtual service is served on.
+   *
+   * @generated from field: repeated virtual_service.v1.NodeSyncStatus nodes = 3;
+   */
+  nodes: NodeSyncStatus[];
+};
+
+/**
+ * Describes the message virtual_service.v1.SyncStatus.
+ * Use `create(SyncStatusSchema)` to create a new message.
+ */
+export const SyncStatusSchema: GenMessage<SyncStatus> = /*@__PURE__*/
+  messageDesc(file_virtual_service_v1_virtual_service, 9);
+
+/**
+ * NodeSyncStatus describes how many proxies of a node acknowledged the current configuration.
+ *
+ * @generated from message virtual_service.v1.NodeSyncStatus
+ */
+export type NodeSyncStatus = Message<"virtual_service.v1.NodeSyncStatus"> & {
+  /**
+   * The node ID.
+   *
+   * @generated from field: string node_id = 1;
+   */
+  nodeId: string;
+
+  /**
+   * The version of the node snapshot that first contained the current configuration.
+   *
+   * @generated from field: string version = 2;
+   */
+  version: string;
+
+  /**
+   * The number of connected proxies that acknowledged the version or a later one.
+   *
+   * @generated from field: uint32 acknowledged = 3;
+   */
+  acknowledged: number;
+
+  /**
+   * The number of connected proxies.
+   *
+   * @generated from field: uint32 total = 4;
+   */
+  total: number;
+};
+
+/**
+ * Describes the message virtual_service.v1.NodeSyncStatus.
+ * Use `create(NodeSyncStatusSchema)` to create a new message.
+ */
+export const NodeSyncStatusSchema: GenMessage<NodeSyncStatus> = /*@__PURE__*/
+  messageDesc(file_virtual_service_v1_virtual_service, 10);
+
 /**
  * ListVirtualServicesRequest is the request message for listing virtual services.
  *
@@ -519,7 +603,7 @@ export type ListVirtualServicesRequest = Message<"virtual_service.v1.ListVirtual
  * Use `create(ListVirtualServicesRequestSchema)` to create a new message.
  */
 export const ListVirtualServicesRequestSchema: GenMessage<ListVirtualServicesRequest> = /*@__PURE__*/
-  messageDesc(file_virtual_service_v1_virtual_service, 9);
+  messageDesc(file_virtual_service_v1_virtual_service, 11);
 
 /**
  * VirtualServiceListItem represents a single virtual service in a list response.
@@ -596,7 +680,7 @@ export type VirtualServiceListItem = Message<"virtual_service.v1.VirtualServiceL
  * Use `create(VirtualServiceListItemSchema)` to create a new message.
  */
 export const VirtualServiceListItemSchema: GenMessage<VirtualServiceListItem> = /*@__PURE__*/
-  messageDesc(file_virtual_service_v1_virtual_service, 10);
+  messageDesc(file_virtual_service_v1_virtual_service, 12);
 
 /**
  * ListVirtualServicesResponse is the response message for listing virtual services.
@@ -617,7 +701,7 @@ export type ListVirtualServicesResponse = Message<"virtual_service.v1.ListVirtua
  * Use `create(ListVirtualServicesResponseSchema)` to create a new message.
  */
 export const ListVirtualServicesResponseSchema: GenMessage<ListVirtualServicesResponse> = /*@__PURE__*/
-  messageDesc(file_virtual_service_v1_virtual_service, 11);
+  messageDesc(file_virtual_service_v1_virtual_service, 13);
 
 /**
  * The VirtualServiceStoreService defines operations for managing virtual services.